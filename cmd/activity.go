@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/output"
 	"github.com/faisalahmedsifat/yo/internal/timer"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
@@ -26,7 +27,7 @@ Examples:
   yo activity --week      - This week's activity`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		var entries []activity.Entry
@@ -55,6 +56,29 @@ Examples:
 			entries = filtered
 		}
 
+		if structured() {
+			report := &output.Activity{Range: "today", Entries: entries}
+			if activityYesterday {
+				report.Range = "yesterday"
+			} else if activityWeek {
+				report.Range = "week"
+			}
+			if report.Entries == nil {
+				report.Entries = []activity.Entry{}
+			}
+			for _, e := range entries {
+				switch e.Type {
+				case activity.TypeStageChange:
+					report.Counts.StageChanges++
+				case activity.TypeFileChange, activity.TypeFileRename, activity.TypeFileDelete:
+					report.Counts.FileChanges++
+				case activity.TypeEmergencyBypass:
+					report.Counts.Bypasses++
+				}
+			}
+			return printOutput(report)
+		}
+
 		// Display
 		fmt.Println()
 		fmt.Println("📊 Activity")
@@ -75,7 +99,7 @@ Examples:
 			switch e.Type {
 			case activity.TypeStageChange:
 				stageChanges++
-			case activity.TypeFileChange:
+			case activity.TypeFileChange, activity.TypeFileRename, activity.TypeFileDelete:
 				fileChanges++
 			case activity.TypeEmergencyBypass:
 				bypasses++
@@ -101,6 +125,10 @@ Examples:
 				fmt.Printf("    %s  %s → %s\n", ts, e.From, e.To)
 			case activity.TypeFileChange:
 				fmt.Printf("    %s  📝 %s\n", ts, e.File)
+			case activity.TypeFileRename:
+				fmt.Printf("    %s  🔀 %s → %s\n", ts, e.From, e.File)
+			case activity.TypeFileDelete:
+				fmt.Printf("    %s  🗑  %s\n", ts, e.File)
 			case activity.TypeEmergencyBypass:
 				fmt.Printf("    %s  🚨 Bypass: %s\n", ts, e.Reason)
 			case activity.TypeTaskComplete:
@@ -119,7 +147,7 @@ var focusCmd = &cobra.Command{
 	Long:  `Calculate and display your focus score based on today's activity.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		entries, err := activity.QueryToday()
@@ -132,7 +160,7 @@ var focusCmd = &cobra.Command{
 		repoTime := make(map[string]int)
 
 		for _, e := range entries {
-			if e.Type == activity.TypeFileChange {
+			if e.IsFileEvent() {
 				repoTime[e.Repo]++
 				if e.Untracked {
 					untracked++
@@ -150,6 +178,15 @@ var focusCmd = &cobra.Command{
 			focusScore = 100
 		}
 
+		if structured() {
+			return printOutput(&output.Focus{
+				Score:            focusScore,
+				OnTaskChanges:    onTask,
+				UntrackedChanges: untracked,
+				Repos:            repoTime,
+			})
+		}
+
 		fmt.Println()
 		fmt.Println("🎯 Focus Score")
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
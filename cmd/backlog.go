@@ -1,20 +1,28 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/faisalahmedsifat/yo/internal/backlog"
+	"github.com/faisalahmedsifat/yo/internal/output"
 	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	listP0Only bool
-	listP1Only bool
+	listP0Only   bool
+	listP1Only   bool
+	listTag      string
+	listDue      string
+	listAssignee string
+	listSort     string
+	addPriority  string
 )
 
 var listCmd = &cobra.Command{
@@ -22,13 +30,20 @@ var listCmd = &cobra.Command{
 	Short: "List backlog items",
 	Long: `Display items from the backlog organized by priority.
 
+Items can carry inline metadata: #tag, ~2h (estimate), due:2026-11-01
+and @assignee.
+
 Examples:
-  yo list       - Show all items
-  yo list --p0  - Show only P0 items
-  yo list --p1  - Show only P1 items`,
+  yo list                  - Show all items
+  yo list --p0             - Show only P0 items
+  yo list --p1             - Show only P1 items
+  yo list --tag frontend   - Items tagged #frontend
+  yo list --due week       - Items due within 7 days (also: overdue, today, 3d, YYYY-MM-DD)
+  yo list --assignee alice - Items assigned to @alice
+  yo list --sort due       - Soonest due first within each priority`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		b, err := backlog.Load()
@@ -36,13 +51,41 @@ Examples:
 			return err
 		}
 
+		now := time.Now()
+		filter := backlog.Filter{Tag: listTag, Assignee: listAssignee}
+		if listDue != "" {
+			due, err := backlog.ParseDueWindow(listDue, now)
+			if err != nil {
+				return err
+			}
+			filter.DueBy, filter.Overdue = due.DueBy, due.Overdue
+		}
+
+		switch listSort {
+		case "", "file", "due":
+		default:
+			return fmt.Errorf("invalid sort %q (use due or file)", listSort)
+		}
+
+		view := listView{filter: filter, sortDue: listSort == "due", now: now}
+
+		if structured() {
+			keys := []string{backlog.P0, backlog.P1, backlog.P2, backlog.P3}
+			if listP0Only {
+				keys = keys[:1]
+			} else if listP1Only {
+				keys = keys[1:2]
+			}
+			return printOutput(view.output(b, keys))
+		}
+
 		if listP0Only {
-			printPriorityItems("P0 - Launch Blockers", b.Items[backlog.P0])
+			view.print("P0 - Launch Blockers", b.Items[backlog.P0])
 			return nil
 		}
 
 		if listP1Only {
-			printPriorityItems("P1 - Paying User Blockers", b.Items[backlog.P1])
+			view.print("P1 - Paying User Blockers", b.Items[backlog.P1])
 			return nil
 		}
 
@@ -62,9 +105,10 @@ Examples:
 		}
 
 		totalItems := b.Total()
+		shown := 0
 		for _, p := range priorities {
-			if len(b.Items[p.key]) > 0 {
-				printPriorityItems(p.title, b.Items[p.key])
+			if len(filter.Apply(b.Items[p.key], now)) > 0 {
+				shown += view.print(p.title, b.Items[p.key])
 			}
 		}
 
@@ -72,25 +116,76 @@ Examples:
 			fmt.Println("  (no items in backlog)")
 			fmt.Println()
 			fmt.Println("  Add items with: yo add \"description\"")
+		} else if shown == 0 {
+			fmt.Println("  (no items match)")
 		}
 
 		return nil
 	},
 }
 
-func printPriorityItems(title string, items []backlog.Item) {
-	fmt.Printf("  ## %s (%d)\n", title, len(items))
-	if len(items) == 0 {
+// listView prints backlog items with the list filter and sort applied.
+// Items keep their position number from backlog.md (as used in P1.2).
+type listView struct {
+	filter  backlog.Filter
+	sortDue bool
+	now     time.Time
+}
+
+func (v listView) print(title string, items []backlog.Item) int {
+	positions := make(map[int]int)
+	for i, item := range items {
+		positions[item.Line] = i + 1
+	}
+
+	shown := v.filter.Apply(items, v.now)
+	if v.sortDue {
+		backlog.SortByDue(shown)
+	}
+
+	fmt.Printf("  ## %s (%d)\n", title, len(shown))
+	if len(shown) == 0 {
 		fmt.Println("    (none)")
 	}
-	for i, item := range items {
+	for _, item := range shown {
 		checkbox := "[ ]"
 		if item.Checked {
 			checkbox = "[x]"
 		}
-		fmt.Printf("    %d. %s %s\n", i+1, checkbox, item.Text)
+		suffix := ""
+		if item.Overdue(v.now) {
+			suffix += " ⚠️ overdue"
+		}
+		if item.DoneDate != "" {
+			suffix += " ✓ " + item.DoneDate
+		}
+		if item.ID != "" {
+			suffix += " (" + item.ID + ")"
+		}
+		fmt.Printf("    %d. %s %s%s\n", positions[item.Line], checkbox, item.Text, suffix)
 	}
 	fmt.Println()
+	return len(shown)
+}
+
+// output builds the --output form of the listed priorities
+func (v listView) output(b *backlog.Backlog, priorities []string) *output.BacklogList {
+	list := &output.BacklogList{Items: []output.BacklogItem{}, Total: b.Total()}
+	for _, p := range priorities {
+		positions := make(map[int]int)
+		for i, item := range b.Items[p] {
+			positions[item.Line] = i + 1
+		}
+
+		shown := v.filter.Apply(b.Items[p], v.now)
+		if v.sortDue {
+			backlog.SortByDue(shown)
+		}
+		for _, item := range shown {
+			list.Items = append(list.Items, output.NewBacklogItem(item, positions[item.Line], v.now))
+		}
+	}
+	return list
 }
 
 var addCmd = &cobra.Command{
@@ -100,10 +195,11 @@ var addCmd = &cobra.Command{
 
 Examples:
   yo add "OAuth login broken"
+  yo add --priority P0 "Checkout returns 500"
   yo add -i  # Interactive mode`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		var description string
@@ -111,41 +207,41 @@ Examples:
 
 		interactive, _ := cmd.Flags().GetBool("interactive")
 
-		if interactive || len(args) == 0 {
-			reader := bufio.NewReader(os.Stdin)
-
-			fmt.Print("Description: ")
-			description, _ = reader.ReadString('\n')
-			description = strings.TrimSpace(description)
+		if addPriority != "" {
+			p, err := backlog.ParsePriority(addPriority)
+			if err != nil {
+				return err
+			}
+			priority = p
+		}
 
+		if interactive || len(args) == 0 {
+			var err error
+			if description, err = ask.Ask("description", "Description: "); err != nil {
+				return err
+			}
 			if description == "" {
 				return fmt.Errorf("description cannot be empty")
 			}
+		} else {
+			description = strings.Join(args, " ")
+		}
 
-			fmt.Println()
-			fmt.Println("Priority:")
-			fmt.Println("  0. P0 - Launch blocker")
-			fmt.Println("  1. P1 - Paying user blocker")
-			fmt.Println("  2. P2 - Nice to have")
-			fmt.Println("  3. P3 - Future improvement")
-			fmt.Print("> ")
-			prioStr, _ := reader.ReadString('\n')
-			prioStr = strings.TrimSpace(prioStr)
-
-			switch prioStr {
-			case "0":
-				priority = backlog.P0
-			case "1":
-				priority = backlog.P1
-			case "2":
-				priority = backlog.P2
-			case "3":
-				priority = backlog.P3
-			default:
+		if priority == "" && (interactive || len(args) == 0 || ask.Answered("priority")) {
+			prioStr, err := ask.Ask("priority", `
+Priority:
+  0. P0 - Launch blocker
+  1. P1 - Paying user blocker
+  2. P2 - Nice to have
+  3. P3 - Future improvement
+> `)
+			if err != nil {
+				return err
+			}
+			if priority, err = backlog.ParsePriority(prioStr); err != nil {
 				priority = backlog.P2 // Default
 			}
-		} else {
-			description = strings.Join(args, " ")
+		} else if priority == "" {
 			priority = backlog.P2 // Default priority for non-interactive
 		}
 
@@ -155,18 +251,19 @@ Examples:
 			return err
 		}
 
-		if err := b.Add(description, priority); err != nil {
+		id, err := b.Add(description, priority)
+		if err != nil {
 			return err
 		}
 
 		fmt.Println()
-		fmt.Printf("✅ Added to %s: %s\n", priority, description)
+		fmt.Printf("✅ Added to %s: %s (%s)\n", priority, description, id)
 		fmt.Println()
-		fmt.Print("Work on it now? (y/n): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		if strings.TrimSpace(strings.ToLower(response)) == "y" {
+		now, err := offer("work-now", "Work on it now?")
+		if err != nil {
+			return err
+		}
+		if now {
 			fmt.Println("  Start with: yo red")
 		}
 
@@ -177,10 +274,15 @@ Examples:
 var nextCmd = &cobra.Command{
 	Use:   "next",
 	Short: "Pick next task from backlog and start RED LIGHT",
-	Long:  `Select the next task from your backlog (prioritizing P0 items) and start RED LIGHT.`,
+	Long: `Select the next task from your backlog (prioritizing P0 items) and start RED LIGHT.
+
+Pick without the menu with --pick, by list number or backlog ID, and fill
+in RED LIGHT at the same time with --impact and --severity:
+
+  yo next --pick 1 --impact users --severity P1`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -188,10 +290,15 @@ var nextCmd = &cobra.Command{
 			return err
 		}
 
+		if err := checkBypass(s); err != nil {
+			return err
+		}
+
 		// Check if already in a task
 		if s.CurrentStage != "none" && s.CurrentStage != "" {
 			fmt.Printf("⚠️  Already in %s stage with task: %s\n", strings.ToUpper(s.CurrentStage), s.CurrentTaskID)
-			fmt.Println("   Complete current task first with 'yo done' or 'yo off'")
+			fmt.Println("   Complete current task first with 'yo done' or 'yo off',")
+			fmt.Println("   or set it aside with 'yo park'")
 			return nil
 		}
 
@@ -200,61 +307,83 @@ var nextCmd = &cobra.Command{
 			return err
 		}
 
-		// Get unchecked items using backlog package
-		available := bl.GetUnchecked()
+		// Get unchecked items, overdue first within each priority
+		now := time.Now()
+		available := bl.GetNextCandidates(now)
 
 		if len(available) == 0 {
 			fmt.Println("🎉 Backlog is empty! Add items with: yo add \"description\"")
 			return nil
 		}
 
-		fmt.Println()
-		fmt.Println("📋 Pick next task:")
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Println()
+		// Debt that came due competes for the next slot
+		if due := dueDebt(); len(due) > 0 {
+			printDueDebt("Tech debt due:", due)
+		}
 
+		var menu strings.Builder
+		menu.WriteString("\n📋 Pick next task:\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
 		for i, item := range available {
-			fmt.Printf("  [%d] %s (%s)\n", i+1, item.Text, item.Priority)
+			due := ""
+			if item.Overdue(now) {
+				due = fmt.Sprintf(" ⚠️ overdue since %s", item.Due.Format("Jan 2"))
+			}
+			fmt.Fprintf(&menu, "  [%d] %s (%s)%s\n", i+1, item.Text, item.Priority, due)
 		}
-		fmt.Println("  [q] Quit")
-		fmt.Println()
-
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Print("> ")
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(response)
+		menu.WriteString("  [q] Quit\n\n> ")
 
+		response, err := ask.Ask("pick", menu.String())
+		if err != nil {
+			return err
+		}
 		if response == "q" || response == "" {
 			return nil
 		}
 
-		var choice int
-		if _, err := fmt.Sscanf(response, "%d", &choice); err != nil || choice < 1 || choice > len(available) {
-			return fmt.Errorf("invalid choice")
+		selected, ok := pickCandidate(available, response)
+		if !ok {
+			return fmt.Errorf("invalid choice %q: use a number from the list or a backlog ID", response)
 		}
 
-		selected := available[choice-1]
-
-		// Generate task ID from description
-		taskID := sanitizeForTaskID(selected.Text)
+		// Link the task to the backlog item through its stable ID
+		backlogID, err := bl.EnsureID(selected.Line)
+		if err != nil {
+			return err
+		}
+		taskID := task.MakeID(selected.Title(), backlogID)
+		if _, parked := s.Tasks[taskID]; parked {
+			return fmt.Errorf("%s is already parked. Pick it up with 'yo switch %s'", taskID, taskID)
+		}
 
-		// Update current_task.md with the problem from backlog
-		taskPath, err := workspace.GetCurrentTaskPath()
+		// Start the task file with the problem from backlog
+		taskPath, err := workspace.CreateTask(taskID)
 		if err != nil {
 			return err
 		}
-		content, err := os.ReadFile(taskPath)
+		t, err := task.Load(taskPath)
 		if err != nil {
 			return err
 		}
-		text := string(content)
-		text = strings.Replace(text, "<!-- Describe the problem clearly -->", selected.Text, 1)
-		os.WriteFile(taskPath, []byte(text), 0644)
+		t.Red.Problem = selected.Title()
+		if err := t.Save(taskPath); err != nil {
+			return err
+		}
 
 		// Update state to RED LIGHT
-		s.SetStage("red")
-		s.CurrentTaskID = taskID
-		if err := s.Save(); err != nil {
+		s, err = state.Update(func(s *state.State) error {
+			if s.CurrentStage != "none" && s.CurrentStage != "" {
+				return fmt.Errorf("task %s was started in the meantime", s.CurrentTaskID)
+			}
+			if _, parked := s.Tasks[taskID]; parked {
+				return fmt.Errorf("%s is already parked. Pick it up with 'yo switch %s'", taskID, taskID)
+			}
+			s.SetStage("red")
+			s.CurrentTaskID = taskID
+			s.CurrentBacklogID = backlogID
+			return nil
+		})
+		if err != nil {
+			os.Remove(taskPath)
 			return err
 		}
 
@@ -266,12 +395,20 @@ var nextCmd = &cobra.Command{
 		fmt.Printf("  From: %s\n", selected.Priority)
 		fmt.Println()
 
-		// Ask if user wants to continue interactively
-		fmt.Print("Fill in impact/severity now? (y/n): ")
-		response, _ = reader.ReadString('\n')
-		if strings.TrimSpace(strings.ToLower(response)) == "y" {
+		if fired := fireTagTriggers(selected.Text); len(fired) > 0 {
+			printDueDebt("This problem touches deferred tech debt:", fired)
+		}
+
+		// Fill in impact/severity now if given, or if the user wants to
+		fill := ask.Answered("impact") || ask.Answered("severity")
+		if !fill {
+			if fill, err = offer("fill-now", "Fill in impact/severity now?"); err != nil {
+				return err
+			}
+		}
+		if fill {
 			// Run impact and severity prompts only
-			return runRedContinue(taskPath, s, selected.Text)
+			return runRedContinue(taskPath, s, selected.Title())
 		}
 
 		fmt.Println()
@@ -283,26 +420,35 @@ var nextCmd = &cobra.Command{
 	},
 }
 
-func sanitizeForTaskID(s string) string {
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "_")
-	var result strings.Builder
-	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
-			result.WriteRune(r)
+// pickCandidate finds the choice among the candidates: a list number or a
+// backlog ID
+func pickCandidate(available []backlog.Item, choice string) (backlog.Item, bool) {
+	if n, err := strconv.Atoi(choice); err == nil {
+		if n >= 1 && n <= len(available) {
+			return available[n-1], true
 		}
+		return backlog.Item{}, false
 	}
-	id := result.String()
-	if len(id) > 30 {
-		id = id[:30]
+	for _, item := range available {
+		if item.ID != "" && strings.EqualFold(item.ID, strings.TrimPrefix(choice, "#")) {
+			return item, true
+		}
 	}
-	return id
+	return backlog.Item{}, false
 }
 
 func init() {
 	listCmd.Flags().BoolVar(&listP0Only, "p0", false, "Show only P0 items")
 	listCmd.Flags().BoolVar(&listP1Only, "p1", false, "Show only P1 items")
+	listCmd.Flags().StringVar(&listTag, "tag", "", "Show only items with this #tag")
+	listCmd.Flags().StringVar(&listDue, "due", "", "Show items due within a window: overdue, today, week, 3d or YYYY-MM-DD")
+	listCmd.Flags().StringVar(&listAssignee, "assignee", "", "Show only items assigned to this @user")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "Sort within each priority: due or file (default)")
 	addCmd.Flags().BoolP("interactive", "i", false, "Interactive mode")
+	addCmd.Flags().StringVarP(&addPriority, "priority", "p", "", "Priority: P0, P1, P2 or P3 (default P2)")
+	nextCmd.Flags().String("pick", "", "Item to start: its number in the list or its backlog ID")
+	nextCmd.Flags().StringSlice("impact", nil, "Impact: launch, users, frustration, debt, other (or 1-5)")
+	nextCmd.Flags().String("severity", "", "Severity: P0-P3")
 
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(addCmd)
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/backlog"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var backlogCmd = &cobra.Command{
+	Use:   "backlog",
+	Short: "Edit backlog items",
+	Long: `Move, edit, remove, tick and reorder backlog items.
+
+Items are referred to by the ID shown in 'yo list' (a unique prefix is
+enough) or by list position, e.g. P1.2 for the second P1 item.
+Only the item's own line in backlog.md is touched.
+
+Examples:
+  yo backlog mv a1b2c3 P0
+  yo backlog edit a1b2 "OAuth callback returns 500"
+  yo backlog rm P3.1
+  yo backlog check a1b2c3
+  yo backlog up a1b2c3`,
+}
+
+var backlogMvCmd = &cobra.Command{
+	Use:   "mv <id> <priority>",
+	Short: "Move an item to another priority",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, item, err := loadBacklogItem(args[0])
+		if err != nil {
+			return err
+		}
+
+		priority, err := backlog.ParsePriority(args[1])
+		if err != nil {
+			return err
+		}
+
+		if err := b.Move(item, priority); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Moved to %s: %s\n", priority, item.Text)
+		return nil
+	},
+}
+
+var backlogEditCmd = &cobra.Command{
+	Use:   "edit <id> [text]",
+	Short: "Change an item's text",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, item, err := loadBacklogItem(args[0])
+		if err != nil {
+			return err
+		}
+
+		text := strings.Join(args[1:], " ")
+		if text == "" {
+			text, err = ask.Ask("text", fmt.Sprintf("Current: %s\nNew text (empty to keep): ", item.Text))
+			if err != nil {
+				return err
+			}
+			if text == "" {
+				fmt.Println("Unchanged.")
+				return nil
+			}
+		}
+
+		if err := b.Edit(item, text); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Updated: %s\n", strings.TrimSpace(text))
+		return nil
+	},
+}
+
+var backlogRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Remove an item",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, item, err := loadBacklogItem(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := b.Remove(item); err != nil {
+			return err
+		}
+
+		fmt.Printf("🗑️  Removed from %s: %s\n", item.Priority, item.Text)
+		return nil
+	},
+}
+
+var backlogCheckCmd = &cobra.Command{
+	Use:   "check <id>",
+	Short: "Mark an item as done",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, item, err := loadBacklogItem(args[0])
+		if err != nil {
+			return err
+		}
+
+		if item.Checked {
+			fmt.Printf("Already done: %s\n", item.Text)
+			return nil
+		}
+
+		if err := b.Check(item, time.Now()); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Done: %s\n", item.Text)
+		return nil
+	},
+}
+
+var backlogUpCmd = &cobra.Command{
+	Use:   "up <id>",
+	Short: "Move an item up within its priority",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return reorderBacklogItem(args[0], -1)
+	},
+}
+
+var backlogDownCmd = &cobra.Command{
+	Use:   "down <id>",
+	Short: "Move an item down within its priority",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return reorderBacklogItem(args[0], 1)
+	},
+}
+
+// loadBacklogItem loads the backlog and resolves an item reference
+func loadBacklogItem(ref string) (*backlog.Backlog, backlog.Item, error) {
+	if !workspace.IsInitialized() {
+		return nil, backlog.Item{}, workspace.ErrNotInitialized
+	}
+
+	b, err := backlog.Load()
+	if err != nil {
+		return nil, backlog.Item{}, err
+	}
+
+	item, err := b.Resolve(ref)
+	if err != nil {
+		return nil, backlog.Item{}, err
+	}
+	return b, item, nil
+}
+
+func reorderBacklogItem(ref string, delta int) error {
+	b, item, err := loadBacklogItem(ref)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Reorder(item, delta); err != nil {
+		return err
+	}
+
+	direction := "down"
+	if delta < 0 {
+		direction = "up"
+	}
+	fmt.Printf("✅ Moved %s in %s: %s\n", direction, item.Priority, item.Text)
+	return nil
+}
+
+func init() {
+	backlogCmd.AddCommand(backlogMvCmd)
+	backlogCmd.AddCommand(backlogEditCmd)
+	backlogCmd.AddCommand(backlogRmCmd)
+	backlogCmd.AddCommand(backlogCheckCmd)
+	backlogCmd.AddCommand(backlogUpCmd)
+	backlogCmd.AddCommand(backlogDownCmd)
+	rootCmd.AddCommand(backlogCmd)
+}
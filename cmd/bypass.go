@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"strings"
@@ -9,11 +8,16 @@ import (
 
 	"github.com/faisalahmedsifat/yo/internal/activity"
 	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/notify"
 	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/templates"
+	"github.com/faisalahmedsifat/yo/internal/timer"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
+var bypassNote string
+
 var bypassCmd = &cobra.Command{
 	Use:   "bypass [reason]",
 	Short: "Emergency bypass - skip the framework",
@@ -22,20 +26,46 @@ Tracked and limited to:
   - 1 per day
   - 5 per week
 
+The bypass lasts bypass_minutes (default 30). A running GREEN LIGHT
+timer is paused meanwhile. When you're done (or the bypass expires),
+write a short post-incident note - it's appended to .yo/bypass_log.md
+and you can't start another stage until it's written.
+
+Examples:
+  yo bypass "production is down"
+  yo bypass --note "Rolled back deploy 142, follow up on migration lock"
+
 Use sparingly for genuine emergencies only.`,
-	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
-		reason := strings.Join(args, " ")
-
 		s, err := state.Load()
 		if err != nil {
 			return err
 		}
 
+		if bypassNote != "" {
+			if s.CurrentStage != "bypass" {
+				return fmt.Errorf("no emergency bypass is active")
+			}
+			return endBypass(s, bypassNote)
+		}
+
+		if len(args) == 0 {
+			return fmt.Errorf("give a reason: yo bypass \"what's on fire\"")
+		}
+
+		if s.CurrentStage == "bypass" {
+			if s.BypassExpired() {
+				return fmt.Errorf("previous bypass expired. Document it first: yo bypass --note \"what you fixed\"")
+			}
+			return fmt.Errorf("bypass already active (%s left)", timer.FormatDuration(s.BypassRemaining()))
+		}
+
+		reason := strings.Join(args, " ")
+
 		cfg, err := config.Load()
 		if err != nil {
 			return err
@@ -55,45 +85,159 @@ Use sparingly for genuine emergencies only.`,
 		if s.EmergencyBypasses.Today >= cfg.MaxBypassDay ||
 			s.EmergencyBypasses.ThisWeek >= cfg.MaxBypassWeek {
 			fmt.Println()
-			fmt.Print("Proceed anyway? (y/n): ")
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			if strings.TrimSpace(strings.ToLower(response)) != "y" {
+			proceed, err := ask.Confirm("proceed", "Proceed anyway?", false)
+			if err != nil {
+				return err
+			}
+			if !proceed {
 				fmt.Println("Bypass cancelled.")
 				return nil
 			}
 		}
 
-		// Increment counters
-		s.EmergencyBypasses.Today++
-		s.EmergencyBypasses.ThisWeek++
-		if err := s.Save(); err != nil {
+		// Increment counters and enter the bypass stage
+		s, err = state.Update(func(s *state.State) error {
+			if s.CurrentStage == "bypass" {
+				return fmt.Errorf("bypass already active (%s left)", timer.FormatDuration(s.BypassRemaining()))
+			}
+			s.EmergencyBypasses.Today++
+			s.EmergencyBypasses.ThisWeek++
+			s.StartBypass(reason, cfg.BypassMinutes)
+			return nil
+		})
+		if err != nil {
 			return err
 		}
 
 		// Log bypass
 		activity.LogEmergencyBypass(reason, s.EmergencyBypasses.Today, s.EmergencyBypasses.ThisWeek)
+		notify.New(cfg.Notifications).BypassStarted(cfg.BypassMinutes)
 
 		fmt.Println()
 		fmt.Println("🚨 BYPASS Active!")
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 		fmt.Printf("   Reason: %s\n", reason)
-		fmt.Printf("   Time limit: 30 minutes\n")
+		fmt.Printf("   Time limit: %d minutes (until %s)\n", cfg.BypassMinutes, s.BypassExpiresAt().Format("15:04"))
 		fmt.Printf("   Bypasses today: %d/%d\n", s.EmergencyBypasses.Today, cfg.MaxBypassDay)
 		fmt.Printf("   Bypasses this week: %d/%d\n", s.EmergencyBypasses.ThisWeek, cfg.MaxBypassWeek)
+		if s.Bypass.PausedTimer {
+			fmt.Printf("   Task timer paused: %s\n", s.CurrentTaskID)
+		}
 		fmt.Println()
-		fmt.Println("   ⏰ Remember to document what you fixed!")
-
-		// Start a 30-minute timer (in background)
-		go func() {
-			time.Sleep(30 * time.Minute)
-			// In a full implementation, this would trigger a notification
-		}()
+		fmt.Println("   ⏰ When it's fixed, document it:")
+		fmt.Println("      yo bypass --note \"what you fixed\"")
 
 		return nil
 	},
 }
 
+// checkBypass blocks stage changes during an emergency bypass. Once the
+// bypass has expired, it asks for the post-incident note and closes it.
+func checkBypass(s *state.State) error {
+	if s.CurrentStage != "bypass" {
+		return nil
+	}
+
+	if !s.BypassExpired() {
+		return fmt.Errorf("emergency bypass active (%s left). Finish it with: yo bypass --note \"what you fixed\"",
+			timer.FormatDuration(s.BypassRemaining()))
+	}
+
+	fmt.Println("⏰ Emergency bypass expired. Document what you fixed before moving on.")
+	fmt.Printf("   Reason was: %s\n", s.Bypass.Reason)
+	note, err := ask.Ask("note", "What did you fix?\n> ")
+	if err != nil {
+		return err
+	}
+	if note == "" {
+		return fmt.Errorf("a post-incident note is required: yo bypass --note \"what you fixed\"")
+	}
+
+	return endBypass(s, note)
+}
+
+// endBypass writes the post-incident note and restores the previous
+// stage. s is updated to the saved state.
+func endBypass(s *state.State, note string) error {
+	startedAt := s.Bypass.StartedAt
+	duration := time.Since(startedAt)
+	resumed := s.Bypass.PausedTimer
+
+	ns, err := state.Update(func(s *state.State) error {
+		if s.CurrentStage != "bypass" || !s.Bypass.StartedAt.Equal(startedAt) {
+			return fmt.Errorf("the bypass was already closed")
+		}
+		s.EndBypass()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := appendBypassLog(s, note, duration); err != nil {
+		return err
+	}
+	*s = *ns
+
+	activity.LogBypassEnd(note, int(duration.Minutes()))
+
+	fmt.Println()
+	fmt.Println("✅ Bypass closed and documented in .yo/bypass_log.md")
+	fmt.Printf("   Back to: %s\n", strings.ToUpper(s.CurrentStage))
+	if resumed {
+		fmt.Printf("   Task timer resumed: %s\n", s.CurrentTaskID)
+	}
+	fmt.Println()
+	return nil
+}
+
+func appendBypassLog(s *state.State, note string, duration time.Duration) error {
+	logPath, err := workspace.GetBypassLogPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(logPath); os.IsNotExist(err) {
+		if err := os.WriteFile(logPath, []byte(templates.BypassLog), 0644); err != nil {
+			return err
+		}
+	}
+
+	entry := fmt.Sprintf(`
+## Bypass on %s
+**Reason:** %s
+**Duration:** %s (limit %dm)
+**Interrupted:** %s
+
+**What was fixed:** %s
+
+---
+`,
+		s.Bypass.StartedAt.Format("2006-01-02 15:04"),
+		s.Bypass.Reason,
+		timer.FormatDuration(duration),
+		s.Bypass.DurationMinutes,
+		bypassInterrupted(s),
+		note,
+	)
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(entry)
+	return err
+}
+
+func bypassInterrupted(s *state.State) string {
+	if s.CurrentTaskID == "" || s.Bypass.PreviousStage == "" || s.Bypass.PreviousStage == "none" {
+		return "(no task)"
+	}
+	return fmt.Sprintf("%s (%s)", s.CurrentTaskID, strings.ToUpper(s.Bypass.PreviousStage))
+}
+
 func init() {
+	bypassCmd.Flags().StringVar(&bypassNote, "note", "", "Post-incident note: what you fixed (ends the bypass)")
 	rootCmd.AddCommand(bypassCmd)
 }
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/output"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var checkUndo bool
+
+var checkCmd = &cobra.Command{
+	Use:   "check <n>...",
+	Short: "Tick success criteria as you meet them",
+	Long: `Tick success criteria in the active task while in GREEN LIGHT.
+
+Each criterion is stamped with the time it was met, which ends up in the
+activity log and the archived task. 'yo done' then only asks about the
+criteria still unticked. Number criteria as 'yo criteria' lists them.
+
+Examples:
+  yo check 1
+  yo check 2 3
+  yo check --undo 2`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		s, err := state.Load()
+		if err != nil {
+			return err
+		}
+
+		if err := checkBypass(s); err != nil {
+			return err
+		}
+
+		if s.CurrentStage != "green" {
+			return fmt.Errorf("can only tick criteria in GREEN LIGHT. Current stage: %s", s.CurrentStage)
+		}
+
+		var indexes []int
+		for _, arg := range args {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 {
+				return usageError{fmt.Errorf("invalid criterion number %q", arg)}
+			}
+			indexes = append(indexes, n-1)
+		}
+
+		t, err := checkCriteria(s.CurrentTaskID, indexes, !checkUndo)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		printCriteria(t)
+		fmt.Println()
+		if met := t.CriteriaMet(); met == len(t.Yellow.Criteria) {
+			fmt.Println("  🎉 All criteria met. Finish with: yo done")
+		} else {
+			fmt.Printf("  %d/%d met\n", met, len(t.Yellow.Criteria))
+		}
+		return nil
+	},
+}
+
+var criteriaCmd = &cobra.Command{
+	Use:   "criteria",
+	Short: "List the current task's success criteria",
+	Long: `List the current task's success criteria with their numbers and the
+time each was ticked.
+
+Use --output json or yaml for scripts.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		s, err := state.Load()
+		if err != nil {
+			return err
+		}
+		taskPath, err := workspace.GetCurrentTaskPath(s)
+		if err != nil {
+			return err
+		}
+		t, err := task.Load(taskPath)
+		if err != nil {
+			return err
+		}
+
+		if structured() {
+			return printOutput(output.NewCriteria(t))
+		}
+
+		if len(t.Yellow.Criteria) == 0 {
+			fmt.Println("No success criteria yet. Plan them with: yo yellow")
+			return nil
+		}
+
+		fmt.Println("📋 Success Criteria")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		printCriteria(t)
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("  %d/%d met\n", t.CriteriaMet(), len(t.Yellow.Criteria))
+		return nil
+	},
+}
+
+// checkCriteria ticks or unticks the criteria at indexes in the task's
+// file and logs each one that changed
+func checkCriteria(taskID string, indexes []int, checked bool) (*task.Task, error) {
+	if taskID == "" {
+		return nil, workspace.ErrNoTask
+	}
+	taskPath, err := workspace.GetTaskPath(taskID)
+	if err != nil {
+		return nil, err
+	}
+	t, err := task.Load(taskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(t.Yellow.Criteria)
+	if n == 0 {
+		return nil, fmt.Errorf("no success criteria to tick. Plan them with: yo yellow")
+	}
+	for _, i := range indexes {
+		if i < 0 || i >= n {
+			return nil, fmt.Errorf("no criterion %d (the task has %d)", i+1, n)
+		}
+	}
+
+	now := time.Now()
+	var changed []int
+	for _, i := range indexes {
+		if t.Yellow.Criteria[i].Checked != checked {
+			t.CheckCriterion(i, checked, now)
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return t, nil
+	}
+
+	if err := t.Save(taskPath); err != nil {
+		return nil, err
+	}
+	for _, i := range changed {
+		activity.LogCriterion(taskID, t.Yellow.Criteria[i].Text, checked)
+	}
+	return t, nil
+}
+
+// printCriteria lists the criteria with their numbers and tick times
+func printCriteria(t *task.Task) {
+	for i, c := range t.Yellow.Criteria {
+		box := "[ ]"
+		if c.Checked {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("  %d. %s %s", i+1, box, c.Text)
+		if !c.CheckedAt.IsZero() {
+			line += "  (" + c.CheckedAt.Local().Format("15:04") + ")"
+		}
+		fmt.Println(line)
+		if c.Command != "" {
+			fmt.Printf("         $ %s\n", c.Command)
+		}
+	}
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkUndo, "undo", false, "Untick the criteria instead")
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(criteriaCmd)
+}
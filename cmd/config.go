@@ -19,7 +19,7 @@ var configListCmd = &cobra.Command{
 	Short: "List all configuration values",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		cfg, err := config.Load()
@@ -36,6 +36,12 @@ var configListCmd = &cobra.Command{
 		fmt.Printf("  watch_dirs:     %v\n", cfg.WatchDirs)
 		fmt.Printf("  max_bypass_day: %d\n", cfg.MaxBypassDay)
 		fmt.Printf("  max_bypass_week: %d\n", cfg.MaxBypassWeek)
+		fmt.Printf("  bypass_minutes: %d\n", cfg.BypassMinutes)
+		fmt.Printf("  max_extensions: %d\n", cfg.MaxExtensions)
+		fmt.Printf("  max_extension_hours: %g\n", cfg.MaxExtensionHours)
+		fmt.Printf("  criteria_timeout: %d\n", cfg.CriteriaTimeout)
+		fmt.Printf("  debt_budget_hours: %g\n", cfg.DebtBudgetHours)
+		fmt.Printf("  debt_budget_confirm: %v\n", cfg.DebtBudgetConfirm)
 		fmt.Println()
 
 		return nil
@@ -48,27 +54,26 @@ var configSetCmd = &cobra.Command{
 	Long: `Set a configuration value.
 
 Available keys:
-  notifications  - on/off
-  editor         - path to editor`,
+  notifications        - on/off
+  editor               - path to editor
+  bypass_minutes       - length of an emergency bypass
+  max_extensions       - timer extensions allowed per task
+  max_extension_hours  - total extension hours allowed per task
+  criteria_timeout     - seconds a success criterion's command may run
+  debt_budget_hours    - outstanding tech debt hours before yo defer warns (0: none)
+  debt_budget_confirm  - on/off: ask before logging debt over the budget`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		key := args[0]
 		value := args[1]
 
-		cfg, err := config.Load()
-		if err != nil {
-			return err
-		}
-
-		if err := cfg.Set(key, value); err != nil {
-			return err
-		}
-
-		if err := cfg.Save(); err != nil {
+		if _, err := config.Update(func(cfg *config.Config) error {
+			return cfg.Set(key, value)
+		}); err != nil {
 			return err
 		}
 
@@ -83,7 +88,7 @@ var configGetCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		key := args[0]
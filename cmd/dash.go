@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/dash"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
+	"github.com/faisalahmedsifat/yo/internal/timer"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var dashCmd = &cobra.Command{
+	Use:   "dash",
+	Short: "Full-screen dashboard for the current task",
+	Long: `Open a full-screen dashboard with the current task's problem and plan,
+a live timer, the success criteria, today's activity and backlog counts.
+
+Keys:
+  ↑/↓ or k/j   select a success criterion
+  space        tick or untick it (saved to the task file)
+  p            pause or resume the timer
+  e            extend the timer (asks for the time and a reason)
+  d            log tech debt (asks what, why, when and the estimate)
+  D            complete the task with the ticked criteria (runs their
+               commands first, as 'yo done' does)
+  r            refresh
+  q            quit
+
+Needs a Unix terminal; 'yo timer -w' works anywhere.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		s, err := state.Load()
+		if err != nil {
+			return err
+		}
+
+		if err := checkBypass(s); err != nil {
+			return err
+		}
+
+		return runDash()
+	},
+}
+
+// dashSession is a running dashboard: the view plus what the line being
+// typed at the bottom is for
+type dashSession struct {
+	loader  dash.Loader
+	view    dash.View
+	submit  func(value string) // handles the input line on Enter
+	confirm func()             // runs on 'y' at a yes/no question
+	done    bool               // leave and complete the task
+	quit    bool
+}
+
+func runDash() error {
+	d := &dashSession{}
+	if err := d.reload(); err != nil {
+		return err
+	}
+
+	term, err := dash.OpenTerminal()
+	if err != nil {
+		return err
+	}
+	defer term.Close() // restores the terminal if anything below panics
+
+	keys := make(chan []dash.Key)
+	go readKeys(keys)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for !d.quit && !d.done {
+		d.draw(term)
+
+		select {
+		case batch, ok := <-keys:
+			if !ok {
+				d.quit = true
+			}
+			for _, k := range batch {
+				d.handle(k)
+			}
+		case <-ticker.C:
+			if err := d.reload(); err != nil {
+				d.view.Message = "❌ " + err.Error()
+			}
+		case <-term.Resized():
+		}
+	}
+
+	if err := term.Close(); err != nil {
+		return err
+	}
+	if d.done {
+		return completeFromDash()
+	}
+	return nil
+}
+
+// completeFromDash completes the task with the criteria as ticked in the
+// dashboard, already confirmed there. Their commands run first, as in
+// 'yo done'; one failing on a criterion ticked in the dashboard asks again.
+func completeFromDash() error {
+	s, err := state.Load()
+	if err != nil {
+		return err
+	}
+	taskPath, err := workspace.GetCurrentTaskPath(s)
+	if err != nil {
+		return err
+	}
+	t, err := task.Load(taskPath)
+	if err != nil {
+		return completeTask(nil, nil)
+	}
+
+	ticked := make([]bool, len(t.Yellow.Criteria))
+	for i, c := range t.Yellow.Criteria {
+		ticked[i] = c.Checked
+	}
+	results, err := runDoneCommands(t, s.CurrentTaskID, taskPath)
+	if err != nil {
+		return err
+	}
+
+	met := make([]bool, len(t.Yellow.Criteria))
+	failed := false
+	for i, c := range t.Yellow.Criteria {
+		met[i] = c.Checked
+		if ticked[i] && !c.Checked {
+			failed = true
+		}
+	}
+	if failed {
+		fmt.Println("❌ A criterion ticked in the dashboard failed its command.")
+		keepWorking, err := ask.Confirm("continue-working", "   Continue working?", true)
+		if err != nil {
+			return err
+		}
+		if keepWorking {
+			fmt.Println("   Keep working! You've got this. 💪")
+			return nil
+		}
+	}
+	return completeTask(met, results)
+}
+
+// readKeys sends the key presses of each read from stdin
+func readKeys(keys chan<- []dash.Key) {
+	buf := make([]byte, 64)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		keys <- dash.ParseKeys(buf[:n])
+	}
+}
+
+func (d *dashSession) reload() error {
+	data, err := d.loader.Load()
+	if err != nil {
+		return err
+	}
+	d.view.Data = data
+	d.view.Now = time.Now()
+	d.view.MoveCursor(0)
+	return nil
+}
+
+func (d *dashSession) draw(term *dash.Terminal) {
+	width, height := term.Size()
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for i, line := range d.view.Render(width, height) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\033[K")
+	}
+	fmt.Print(b.String())
+}
+
+// ask starts reading a line at the bottom of the screen
+func (d *dashSession) ask(question string, submit func(string)) {
+	d.view.Input = &dash.Input{Prompt: question}
+	d.submit = submit
+}
+
+func (d *dashSession) handle(k dash.Key) {
+	// A line being typed takes every key
+	if in := d.view.Input; in != nil {
+		submitted, cancelled := in.Handle(k)
+		switch {
+		case cancelled:
+			d.view.Input = nil
+			d.view.Message = "Cancelled"
+		case submitted:
+			d.view.Input = nil
+			d.view.Message = ""
+			d.submit(strings.TrimSpace(in.Value))
+		}
+		return
+	}
+
+	// So does a yes/no question
+	if d.confirm != nil {
+		confirm := d.confirm
+		d.confirm = nil
+		d.view.Message = "Cancelled"
+		if k.Code == dash.KeyRune && (k.Rune == 'y' || k.Rune == 'Y') {
+			d.view.Message = ""
+			confirm()
+		}
+		return
+	}
+
+	switch k.Code {
+	case dash.KeyEsc, dash.KeyCtrlC:
+		d.quit = true
+	case dash.KeyUp:
+		d.view.MoveCursor(-1)
+	case dash.KeyDown:
+		d.view.MoveCursor(1)
+	case dash.KeyRune:
+		switch k.Rune {
+		case 'q':
+			d.quit = true
+		case 'k':
+			d.view.MoveCursor(-1)
+		case 'j':
+			d.view.MoveCursor(1)
+		case ' ':
+			d.toggleCriterion()
+		case 'p':
+			d.togglePause()
+		case 'e':
+			d.extend()
+		case 'd':
+			d.ask("Defer what? ", d.deferDebt)
+		case 'D':
+			d.complete()
+		case 'r':
+			d.view.Message = ""
+		}
+	}
+
+	if err := d.reload(); err != nil {
+		d.view.Message = "❌ " + err.Error()
+	}
+}
+
+// toggleCriterion ticks or unticks the selected criterion in the task file
+func (d *dashSession) toggleCriterion() {
+	criteria := d.view.Criteria()
+	i := d.view.Cursor
+	if i >= len(criteria) {
+		d.view.Message = "No success criteria to tick. Plan them with: yo yellow"
+		return
+	}
+	if _, err := checkCriteria(d.view.Data.State.CurrentTaskID, []int{i}, !criteria[i].Checked); err != nil {
+		d.view.Message = "❌ " + err.Error()
+	}
+}
+
+func (d *dashSession) togglePause() {
+	s := d.view.Data.State
+	if s.Timer.Paused {
+		if _, paused, err := resumeTimer(); err != nil {
+			d.view.Message = "❌ " + err.Error()
+		} else {
+			d.view.Message = "▶️  Timer resumed after " + timer.FormatDuration(paused)
+		}
+		return
+	}
+	if _, err := pauseTimer(""); err != nil {
+		d.view.Message = "❌ " + err.Error()
+	} else {
+		d.view.Message = "⏸️  Timer paused"
+	}
+}
+
+func (d *dashSession) extend() {
+	d.ask("Extend by (e.g. 30m, 1h): ", func(value string) {
+		hours, err := timer.ParseDuration(value)
+		if err != nil || hours <= 0 {
+			d.view.Message = fmt.Sprintf("❌ invalid time format: %s (use format like 2h, 1.5h, 30m)", value)
+			return
+		}
+		d.ask("Why do you need more time? ", func(reason string) {
+			if reason == "" {
+				d.view.Message = "❌ a reason is required to extend"
+				return
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				d.view.Message = "❌ " + err.Error()
+				return
+			}
+			s, err := extendTimer(hours, reason, cfg)
+			var limit *extensionLimitError
+			if errors.As(err, &limit) {
+				d.view.Message = "❌ " + limit.message
+				return
+			}
+			if err != nil {
+				d.view.Message = "❌ " + err.Error()
+				return
+			}
+			d.view.Message = fmt.Sprintf("⏱️  Extended by %s (%d/%d)", timer.FormatHours(hours), len(s.Timer.Extensions), cfg.MaxExtensions)
+		})
+	})
+}
+
+// deferDebt asks the rest of what 'yo defer -i' does, then logs the debt
+// within the debt budget
+func (d *dashSession) deferDebt(what string) {
+	if what == "" {
+		d.view.Message = "❌ description cannot be empty"
+		return
+	}
+	d.ask("Why skip it? ", func(why string) {
+		if why == "" {
+			why = defaultDebtWhy
+		}
+		d.ask("Come back when? (e.g. tasks:5, tag:auth) ", func(when string) {
+			if when == "" {
+				when = defaultDebtWhen
+			}
+			if err := checkDebtTriggers(when); err != nil {
+				d.view.Message = "❌ " + err.Error()
+				return
+			}
+			d.ask("Estimate to fix (e.g. 2h)? ", func(estimate string) {
+				if estimate == "" {
+					estimate = defaultDebtEstimate
+				}
+				b, err := loadDebtBudget(estimate)
+				if err != nil {
+					d.view.Message = "❌ " + err.Error()
+					return
+				}
+				logIt := func() { d.logDebt(what, why, when, estimate, b) }
+				if b.over() && b.confirm {
+					d.view.Message = "⚠️  " + b.warning() + ". Log it anyway? (y/n)"
+					d.confirm = logIt
+					return
+				}
+				logIt()
+			})
+		})
+	})
+}
+
+func (d *dashSession) logDebt(what, why, when, estimate string, b debtBudget) {
+	id, err := logTechDebt(d.view.Data.State.CurrentTaskID, what, why, when, estimate, "")
+	if err != nil {
+		d.view.Message = "❌ " + err.Error()
+		return
+	}
+	d.view.Message = fmt.Sprintf("📝 Tech debt %s logged: %s", id, what)
+	if b.over() {
+		d.view.Message += " ⚠️  " + b.warning()
+	}
+}
+
+// complete asks to finish the task with the criteria as ticked
+func (d *dashSession) complete() {
+	if stage := d.view.Data.State.CurrentStage; stage != "green" {
+		d.view.Message = fmt.Sprintf("❌ can only complete tasks in GREEN LIGHT. Current stage: %s", stage)
+		return
+	}
+
+	criteria := d.view.Criteria()
+	met := 0
+	for _, c := range criteria {
+		if c.Checked {
+			met++
+		}
+	}
+	question := "Complete the task? (y/n)"
+	if met < len(criteria) {
+		question = fmt.Sprintf("Only %d/%d success criteria met. Complete anyway? (y/n)", met, len(criteria))
+	}
+	d.view.Message = question
+	d.confirm = func() { d.done = true }
+}
+
+func init() {
+	rootCmd.AddCommand(dashCmd)
+}
@@ -0,0 +1,548 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/backlog"
+	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/debt"
+	"github.com/faisalahmedsifat/yo/internal/output"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/timer"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	debtListAll     bool
+	debtListDue     bool
+	debtPromoteTo   string
+	debtReportWeeks int
+)
+
+var debtCmd = &cobra.Command{
+	Use:   "debt",
+	Short: "List, resolve and promote tech debt",
+	Long: `Work with the tech debt logged by 'yo defer'.
+
+Entries live in .yo/tech_debt_log.md. Each has a short ID (a unique
+prefix is enough) and a status: open, promoted to the backlog, or
+resolved.
+
+Examples:
+  yo debt list
+  yo debt show a1b2c3
+  yo debt resolve a1b2 --note "Added retries with backoff"
+  yo debt promote a1b2c3 --priority P1
+  yo debt report
+  yo debt scan`,
+}
+
+var debtListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List outstanding tech debt",
+	Long: `List open and promoted tech debt, oldest first.
+
+Entries marked 🔔 are due: a trigger in their "come back when" has
+fired. Use --due to list only those, --all to include resolved entries,
+and --output json or yaml for scripts.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		l, err := debt.Load()
+		if err != nil {
+			return err
+		}
+
+		due := map[string]bool{}
+		for _, d := range dueDebt() {
+			due[d.Entry.ID] = true
+		}
+
+		entries := l.Outstanding()
+		switch {
+		case debtListDue:
+			entries = nil
+			for _, e := range l.Entries {
+				if due[e.ID] {
+					entries = append(entries, e)
+				}
+			}
+		case debtListAll:
+			entries = l.Entries
+		}
+
+		if structured() {
+			list := &output.DebtList{Items: []output.DebtEntry{}, Total: len(l.Entries)}
+			for _, e := range entries {
+				list.Items = append(list.Items, output.NewDebtEntry(e))
+			}
+			return printOutput(list)
+		}
+
+		if len(entries) == 0 && debtListDue {
+			fmt.Println("✨ No tech debt due")
+			return nil
+		}
+		if len(entries) == 0 {
+			fmt.Println("✨ No outstanding tech debt")
+			fmt.Println("   Log a conscious shortcut with: yo defer \"what you're skipping\"")
+			return nil
+		}
+
+		now := time.Now()
+		hours := 0.0
+		fmt.Println("📝 Tech Debt")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		for _, e := range entries {
+			mark := debtMark(e)
+			if due[e.ID] {
+				mark = "🔔 "
+			}
+			fmt.Printf("  %s  %-5s %s%s\n", e.ID, e.Estimate, mark, e.What)
+			fmt.Printf("          %s · %s", e.Task, debtAge(e, now))
+			if e.When != "" {
+				fmt.Printf(" · come back: %s", e.When)
+			}
+			fmt.Println()
+			if e.Outstanding() {
+				hours += e.EstimateHours
+			}
+		}
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("  %d outstanding", len(l.Outstanding()))
+		if hours > 0 {
+			fmt.Printf(" · %s to fix", timer.FormatHours(hours))
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+var debtShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a tech debt entry",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, e, err := loadDebtEntry(args[0])
+		if err != nil {
+			return err
+		}
+
+		if structured() {
+			return printOutput(output.NewDebtEntry(e))
+		}
+
+		fmt.Printf("📝 Tech Debt %s\n", e.ID)
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("  What:       %s\n", e.What)
+		fmt.Printf("  Why:        %s\n", e.Why)
+		fmt.Printf("  Come back:  %s\n", e.When)
+		if _, invalid := debt.ParseTriggers(e.When); len(invalid) > 0 {
+			fmt.Printf("  ⚠️  Can't read trigger: %s\n", strings.Join(invalid, ", "))
+		}
+		for _, d := range dueDebt() {
+			if d.Entry.ID == e.ID {
+				fmt.Printf("  Due:        🔔 %s\n", strings.Join(d.Reasons, "; "))
+			}
+		}
+		fmt.Printf("  Estimate:   %s\n", e.Estimate)
+		fmt.Printf("  Task:       %s\n", e.Task)
+		if e.Location != "" {
+			fmt.Printf("  Location:   %s\n", e.Location)
+		}
+		if e.Marker != "" {
+			fmt.Printf("  Marker:     %s\n", e.Marker)
+		}
+		fmt.Printf("  Deferred:   %s (%s)\n", e.Created.Format("2006-01-02"), debtAge(e, time.Now()))
+		fmt.Printf("  Status:     %s\n", e.Status)
+		if e.BacklogID != "" {
+			fmt.Printf("  Backlog:    %s\n", e.BacklogID)
+		}
+		if e.Status == debt.Resolved {
+			if !e.Resolved.IsZero() {
+				fmt.Printf("  Resolved:   %s\n", e.Resolved.Format("2006-01-02"))
+			}
+			if e.Note != "" {
+				fmt.Printf("  Resolution: %s\n", e.Note)
+			}
+		}
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+		switch e.Status {
+		case debt.Open:
+			fmt.Printf("  Next: yo debt resolve %s --note \"how it was fixed\"\n", e.ID)
+			fmt.Printf("        yo debt promote %s  (add it to the backlog)\n", e.ID)
+		case debt.Promoted:
+			fmt.Printf("  Next: yo debt resolve %s --note \"how it was fixed\"\n", e.ID)
+		}
+		return nil
+	},
+}
+
+var debtResolveCmd = &cobra.Command{
+	Use:   "resolve <id>",
+	Short: "Mark tech debt as paid back",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l, e, err := loadDebtEntry(args[0])
+		if err != nil {
+			return err
+		}
+
+		note, err := ask.Ask("note", fmt.Sprintf("How was \"%s\" fixed?\n> ", e.What))
+		if err != nil {
+			return err
+		}
+		if note == "" {
+			return fmt.Errorf("a note is required: say how the debt was paid back")
+		}
+
+		if err := l.Resolve(e.ID, note, time.Now()); err != nil {
+			return err
+		}
+
+		fmt.Println()
+		fmt.Printf("✅ Tech debt resolved: %s\n", e.What)
+		fmt.Printf("   Note: %s\n", note)
+		return nil
+	},
+}
+
+var debtPromoteCmd = &cobra.Command{
+	Use:   "promote <id>",
+	Short: "Turn tech debt into a backlog item",
+	Long: `Add a tech debt entry to the backlog, tagged #debt and with its
+estimate, so it gets picked up by 'yo next'. Finishing the task started
+from that item with 'yo done' resolves the debt.
+
+Examples:
+  yo debt promote a1b2c3
+  yo debt promote a1b2c3 --priority P1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l, e, err := loadDebtEntry(args[0])
+		if err != nil {
+			return err
+		}
+		if e.Status != debt.Open {
+			return fmt.Errorf("tech debt %s is already %s", e.ID, e.Status)
+		}
+
+		priority, err := backlog.ParsePriority(debtPromoteTo)
+		if err != nil {
+			return err
+		}
+
+		b, err := backlog.Load()
+		if err != nil {
+			return err
+		}
+		text := e.What + " #debt"
+		if e.EstimateHours > 0 {
+			text += fmt.Sprintf(" ~%gh", e.EstimateHours)
+		}
+		backlogID, err := b.Add(text, priority)
+		if err != nil {
+			return err
+		}
+
+		if err := l.Promote(e.ID, backlogID); err != nil {
+			// Don't leave a backlog item the debt doesn't point to
+			if item, ok := b.Find(backlogID); ok {
+				if rmErr := b.Remove(item); rmErr != nil {
+					fmt.Printf("⚠️  Failed to remove backlog item %s: %v\n", backlogID, rmErr)
+				}
+			}
+			return err
+		}
+
+		fmt.Println()
+		fmt.Printf("✅ Promoted to %s: %s\n", priority, text)
+		fmt.Printf("   Backlog ID: %s\n", backlogID)
+		fmt.Println("   Pick it up with: yo next")
+		return nil
+	},
+}
+
+var debtReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Sum up outstanding tech debt and how fast it's paid back",
+	Long: `Show the estimated hours to fix all outstanding tech debt, how old it
+is, which tasks deferred it, and the debt created against debt resolved
+for each recent week.
+
+Set a budget with 'yo config set debt_budget_hours 20' to have 'yo defer'
+warn when the outstanding total goes over it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+		if debtReportWeeks < 1 {
+			return usageError{fmt.Errorf("--weeks must be at least 1")}
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		l, err := debt.Load()
+		if err != nil {
+			return err
+		}
+		r := l.Report(time.Now(), debtReportWeeks)
+
+		if structured() {
+			return printOutput(output.NewDebtReport(r, cfg.DebtBudgetHours))
+		}
+
+		fmt.Println("📊 Tech Debt Report")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("  Outstanding: %d", r.Outstanding)
+		if r.Hours > 0 {
+			fmt.Printf(" · %s to fix", timer.FormatHours(r.Hours))
+		}
+		if r.Unestimated > 0 {
+			fmt.Printf(" (+%d unestimated)", r.Unestimated)
+		}
+		fmt.Println()
+		if cfg.DebtBudgetHours > 0 {
+			fmt.Printf("  Budget:      %s", timer.FormatHours(cfg.DebtBudgetHours))
+			if r.Hours > cfg.DebtBudgetHours {
+				fmt.Printf(" ⚠️  over by %s", timer.FormatHours(r.Hours-cfg.DebtBudgetHours))
+			}
+			fmt.Println()
+		}
+
+		if r.Outstanding > 0 {
+			fmt.Println()
+			fmt.Println("  Age:")
+			for _, a := range r.Ages {
+				fmt.Printf("    %-14s %3d  %s\n", a.Label, a.Count, timer.FormatHours(a.Hours))
+			}
+
+			fmt.Println()
+			fmt.Println("  By task:")
+			for _, t := range r.Tasks {
+				fmt.Printf("    %-32s %3d  %s\n", t.Task, t.Count, timer.FormatHours(t.Hours))
+			}
+		}
+
+		fmt.Println()
+		fmt.Println("  Created : resolved per week:")
+		for _, w := range r.Weeks {
+			fmt.Printf("    %-8s %d:%d", w.Start.Format("Jan 2"), w.Created, w.Resolved)
+			if w.Created > 0 && w.Resolved == 0 {
+				fmt.Print("  (nothing paid back)")
+			}
+			fmt.Println()
+		}
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		return nil
+	},
+}
+
+var debtScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Reconcile yo:debt markers in the code with the log",
+	Long: `Walk the project, skipping what .gitignore ignores, for comments that
+mark tech debt:
+
+  // yo:debt a1b2c3   the shortcut logged as a1b2c3 is here
+  // TODO(yo): ...    a shortcut that isn't logged yet
+
+Where each yo:debt marker was found is recorded on its entry. Entries whose
+marker has since disappeared are flagged as likely fixed, and markers with
+no entry in the log are listed so they can be logged with 'yo defer --at'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		projectDir, err := state.GetProjectDir()
+		if err != nil {
+			return err
+		}
+		markers, err := debt.ScanMarkers(projectDir)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s: %w", projectDir, err)
+		}
+
+		l, err := debt.Load()
+		if err != nil {
+			return err
+		}
+		r := l.Reconcile(markers)
+		if err := l.RecordMarkers(r); err != nil {
+			return err
+		}
+
+		if structured() {
+			return printOutput(output.NewDebtScan(r))
+		}
+
+		fmt.Println("🔍 Tech Debt Scan")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("  %d marker(s) linked to outstanding debt\n", len(r.Linked))
+
+		if len(r.Gone) > 0 {
+			fmt.Println()
+			fmt.Println("  ✓ Marker gone, likely fixed:")
+			for _, e := range r.Gone {
+				fmt.Printf("    %s  %s  (was at %s)\n", e.ID, e.What, e.Marker)
+			}
+			fmt.Println("    Close with: yo debt resolve <id> --note \"...\"")
+		}
+
+		if len(r.Orphans) > 0 {
+			fmt.Println()
+			fmt.Println("  ? Markers with no log entry:")
+			for _, m := range r.Orphans {
+				fmt.Printf("    %s  %s\n", m.Location(), m.Text)
+			}
+			fmt.Println("    Log with: yo defer \"what\" --at <file:line>")
+		}
+
+		if len(r.Leftover) > 0 {
+			fmt.Println()
+			fmt.Println("  ⚠️  Markers of resolved debt, safe to remove:")
+			for _, m := range r.Leftover {
+				fmt.Printf("    %s  %s\n", m.Location(), m.ID)
+			}
+		}
+
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		if len(r.Gone) == 0 && len(r.Orphans) == 0 && len(r.Leftover) == 0 {
+			fmt.Println("  ✨ Markers and log agree")
+		}
+		return nil
+	},
+}
+
+// loadDebtEntry loads the log and finds the entry ref names
+func loadDebtEntry(ref string) (*debt.Log, debt.Entry, error) {
+	if !workspace.IsInitialized() {
+		return nil, debt.Entry{}, workspace.ErrNotInitialized
+	}
+
+	l, err := debt.Load()
+	if err != nil {
+		return nil, debt.Entry{}, err
+	}
+
+	e, err := l.Lookup(ref)
+	if err != nil {
+		return nil, debt.Entry{}, err
+	}
+	return l, e, nil
+}
+
+// resolvePromotedDebt resolves the debt promoted to a finished backlog item
+func resolvePromotedDebt(backlogID, taskID string) []debt.Entry {
+	l, err := debt.Load()
+	if err != nil {
+		return nil
+	}
+	var resolved []debt.Entry
+	for _, e := range l.PromotedTo(backlogID) {
+		if err := l.Resolve(e.ID, "Fixed in task "+taskID, time.Now()); err != nil {
+			fmt.Printf("⚠️  Failed to resolve tech debt %s: %v\n", e.ID, err)
+			continue
+		}
+		resolved = append(resolved, e)
+	}
+	return resolved
+}
+
+// dueDebt returns the open tech debt whose triggers have fired
+func dueDebt() []debt.Due {
+	l, err := debt.Load()
+	if err != nil || !l.HasTriggers() {
+		return nil
+	}
+	now := time.Now()
+	entries, err := activity.Query(l.Oldest(), now)
+	if err != nil {
+		return nil
+	}
+	return l.Due(entries, now)
+}
+
+// fireTagTriggers records the tag triggers a new problem fires and
+// returns the entries they belong to
+func fireTagTriggers(problem string) []debt.Due {
+	l, err := debt.Load()
+	if err != nil {
+		return nil
+	}
+	var fired []debt.Due
+	for _, d := range l.MatchTags(problem) {
+		if err := l.MarkFired(d, time.Now()); err != nil {
+			fmt.Printf("⚠️  Failed to record trigger on tech debt %s: %v\n", d.Entry.ID, err)
+			continue
+		}
+		fired = append(fired, d)
+	}
+	return fired
+}
+
+// printDueDebt lists due tech debt under the given heading
+func printDueDebt(heading string, due []debt.Due) {
+	if len(due) == 0 {
+		return
+	}
+	fmt.Printf("🔔 %s\n", heading)
+	for _, d := range due {
+		fmt.Printf("  %s  %s\n", d.Entry.ID, d.Entry.What)
+		for _, reason := range d.Reasons {
+			fmt.Printf("          %s\n", reason)
+		}
+	}
+	fmt.Println("  Pay it back: yo debt promote <id>  or  yo debt resolve <id>")
+}
+
+func debtMark(e debt.Entry) string {
+	switch e.Status {
+	case debt.Promoted:
+		return "→ "
+	case debt.Resolved:
+		return "✓ "
+	}
+	return ""
+}
+
+func debtAge(e debt.Entry, now time.Time) string {
+	days := int(now.Sub(e.Created).Hours() / 24)
+	switch {
+	case e.Created.IsZero():
+		return "undated"
+	case days <= 0:
+		return "today"
+	case days == 1:
+		return "1 day old"
+	}
+	return fmt.Sprintf("%d days old", days)
+}
+
+func init() {
+	debtListCmd.Flags().BoolVarP(&debtListAll, "all", "a", false, "Include resolved entries")
+	debtListCmd.Flags().BoolVar(&debtListDue, "due", false, "List only entries whose trigger fired")
+	debtResolveCmd.Flags().String("note", "", "How the debt was paid back")
+	debtPromoteCmd.Flags().StringVarP(&debtPromoteTo, "priority", "p", "P2", "Backlog priority")
+	debtCmd.AddCommand(debtListCmd)
+	debtCmd.AddCommand(debtShowCmd)
+	debtCmd.AddCommand(debtResolveCmd)
+	debtReportCmd.Flags().IntVar(&debtReportWeeks, "weeks", 8, "Weeks of created and resolved counts")
+	debtCmd.AddCommand(debtPromoteCmd)
+	debtCmd.AddCommand(debtReportCmd)
+	debtCmd.AddCommand(debtScanCmd)
+	rootCmd.AddCommand(debtCmd)
+}
@@ -1,18 +1,26 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/debt"
 	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
+	"github.com/faisalahmedsifat/yo/internal/timer"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
-var deferInteractive bool
+var (
+	deferInteractive bool
+	deferAt          string
+)
 
 var deferCmd = &cobra.Command{
 	Use:   "defer [description]",
@@ -24,12 +32,26 @@ It's when you say: "I know the proper way, but I'm doing the quick way."
 
 Examples:
   yo defer "No retry button - users can click deploy again"
+  yo defer "No rate limiting" --why "10 users" --when "Before launch" --estimate 3h
+  yo defer "Sessions kept in memory" --when "tasks:5 or touch:internal/auth/"
+  yo defer "N+1 query in the feed" --at internal/feed/query.go:120
   yo defer -i                    # Interactive mode with guided prompts
 
-This logs to .yo/tech_debt_log.md for future reference.`,
+"Come back when" can hold triggers that make the debt due, surfaced by
+'yo status', 'yo next' and 'yo red':
+  after:2026-12-01   the date has come
+  tasks:5            five more tasks were completed
+  touch:src/auth/    the watcher saw the file or directory change again
+  tag:auth           a new RED LIGHT problem mentions auth or #auth
+
+--at records where the shortcut is. Mark the spot with a "yo:debt <id>"
+comment and 'yo debt scan' will notice when it disappears.
+
+This logs to .yo/tech_debt_log.md for future reference. List, resolve
+and promote entries with 'yo debt'.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -43,26 +65,38 @@ This logs to .yo/tech_debt_log.md for future reference.`,
 			fmt.Println()
 		}
 
+		at, err := debtLocation(deferAt)
+		if err != nil {
+			return usageError{err}
+		}
+
 		if deferInteractive || len(args) == 0 {
-			return runDeferInteractive(s)
+			return runDeferInteractive(s, strings.Join(args, " "), at)
 		}
 
 		// Quick mode
 		what := strings.Join(args, " ")
-		if err := logTechDebt(s.CurrentTaskID, what, "Deferred for faster shipping", "When needed", "TBD"); err != nil {
+		why := answerOr("why", defaultDebtWhy)
+		when := answerOr("when", defaultDebtWhen)
+		estimate := answerOr("estimate", defaultDebtEstimate)
+		if ok, err := checkDebtBudget(estimate); err != nil || !ok {
+			return err
+		}
+		id, err := logTechDebt(s.CurrentTaskID, what, why, when, estimate, at)
+		if err != nil {
 			return err
 		}
 		fmt.Println()
 		fmt.Println("✅ Tech debt logged!")
+		fmt.Printf("   ID:   %s\n", id)
 		fmt.Printf("   What: %s\n", what)
-		fmt.Println("   View with: cat .yo/tech_debt_log.md")
+		printDebtLocation(id, at)
+		fmt.Println("   View with: yo debt list")
 		return nil
 	},
 }
 
-func runDeferInteractive(s *state.State) error {
-	reader := bufio.NewReader(os.Stdin)
-
+func runDeferInteractive(s *state.State, what, at string) error {
 	fmt.Println("📝 Log Tech Debt")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
@@ -70,51 +104,57 @@ func runDeferInteractive(s *state.State) error {
 	fmt.Println()
 
 	// What
-	fmt.Print("What are you deferring?\n> ")
-	what, _ := reader.ReadString('\n')
-	what = strings.TrimSpace(what)
 	if what == "" {
-		return fmt.Errorf("description cannot be empty")
+		var err error
+		if what, err = ask.Ask("what", "What are you deferring?\n> "); err != nil {
+			return err
+		}
+		if what == "" {
+			return fmt.Errorf("description cannot be empty")
+		}
 	}
 
 	// Why
-	fmt.Print("\nWhy are you skipping it? (what's the tradeoff)\n> ")
-	why, _ := reader.ReadString('\n')
-	why = strings.TrimSpace(why)
+	why, err := ask.Ask("why", "\nWhy are you skipping it? (what's the tradeoff)\n> ")
+	if err != nil {
+		return err
+	}
 	if why == "" {
 		why = "Ship faster for MVP"
 	}
 
 	// When to fix
-	fmt.Print("\nWhen should you come back to fix it?\n")
-	fmt.Println("  Examples: 'After 50 users', 'Before public launch', 'When user complains'")
-	fmt.Print("> ")
-	when, _ := reader.ReadString('\n')
-	when = strings.TrimSpace(when)
+	when, err := ask.Ask("when", "\nWhen should you come back to fix it?\n  Examples: 'Before public launch', 'after:2026-12-01', 'tasks:5', 'touch:src/auth/', 'tag:auth'\n> ")
+	if err != nil {
+		return err
+	}
 	if when == "" {
-		when = "When needed"
+		when = defaultDebtWhen
 	}
 
 	// Time estimate
-	fmt.Print("\nEstimated time to fix later? (e.g., 2h, 4h)\n> ")
-	estimate, _ := reader.ReadString('\n')
-	estimate = strings.TrimSpace(estimate)
+	estimate, err := ask.Ask("estimate", "\nEstimated time to fix later? (e.g., 2h, 4h)\n> ")
+	if err != nil {
+		return err
+	}
 	if estimate == "" {
-		estimate = "TBD"
+		estimate = defaultDebtEstimate
 	}
 
-	taskID := s.CurrentTaskID
-	if taskID == "" {
-		taskID = "General"
+	if ok, err := checkDebtBudget(estimate); err != nil || !ok {
+		return err
 	}
 
-	if err := logTechDebt(taskID, what, why, when, estimate); err != nil {
+	id, err := logTechDebt(s.CurrentTaskID, what, why, when, estimate, at)
+	if err != nil {
 		return err
 	}
 
 	fmt.Println()
 	fmt.Println("✅ Tech debt logged!")
-	fmt.Println("   View with: cat .yo/tech_debt_log.md")
+	fmt.Printf("   ID: %s\n", id)
+	printDebtLocation(id, at)
+	fmt.Println("   View with: yo debt list")
 	fmt.Println()
 	fmt.Println("   Remember: This is a CONSCIOUS choice, not bad code.")
 	fmt.Println("   You'll fix it when the time is right. 👍")
@@ -122,42 +162,164 @@ func runDeferInteractive(s *state.State) error {
 	return nil
 }
 
-func logTechDebt(taskID, what, why, when, estimate string) error {
-	techDebtPath, err := workspace.GetTechDebtPath()
+// Defaults for the parts of a tech debt entry left unanswered
+const (
+	defaultDebtWhy      = "Deferred for faster shipping"
+	defaultDebtWhen     = "When needed"
+	defaultDebtEstimate = "TBD"
+)
+
+// debtBudget is the outstanding tech debt once an estimate is added,
+// against debt_budget_hours
+type debtBudget struct {
+	total   float64
+	budget  float64
+	confirm bool // debt_budget_confirm: ask before going over
+}
+
+func (b debtBudget) over() bool {
+	return b.budget > 0 && b.total > b.budget
+}
+
+func (b debtBudget) warning() string {
+	return fmt.Sprintf("Over the tech debt budget: %s outstanding with this, budget %s",
+		timer.FormatHours(b.total), timer.FormatHours(b.budget))
+}
+
+// loadDebtBudget works out the budget with estimate added to what's
+// outstanding. Without a budget it is never over.
+func loadDebtBudget(estimate string) (debtBudget, error) {
+	cfg, err := config.Load()
+	if err != nil || cfg.DebtBudgetHours <= 0 {
+		return debtBudget{}, err
+	}
+	l, err := debt.Load()
 	if err != nil {
-		return err
+		return debtBudget{}, err
+	}
+
+	hours, _ := task.ParseEstimate(estimate)
+	return debtBudget{
+		total:   l.OutstandingHours() + hours,
+		budget:  cfg.DebtBudgetHours,
+		confirm: cfg.DebtBudgetConfirm,
+	}, nil
+}
+
+// checkDebtBudget warns when logging debt with this estimate takes the
+// outstanding total over debt_budget_hours, and with debt_budget_confirm
+// asks whether to go ahead
+func checkDebtBudget(estimate string) (bool, error) {
+	b, err := loadDebtBudget(estimate)
+	if err != nil {
+		return false, err
+	}
+	if !b.over() {
+		return true, nil
 	}
 
-	entry := fmt.Sprintf(`
-## Deferred on %s
-**Task:** %s
+	fmt.Println("⚠️  " + b.warning())
+	if !b.confirm {
+		fmt.Println("   Pay some back: yo debt report")
+		return true, nil
+	}
 
-**What:** %s
-**Why skipped:** %s
-**Come back when:** %s
-**Estimated fix time:** %s
+	ok, err := ask.Confirm("over-budget", "Log it anyway?", false)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		fmt.Println("   Not logged. Pay some back first: yo debt report")
+	}
+	return ok, nil
+}
 
----
-`,
-		time.Now().Format("2006-01-02"),
-		taskID,
-		what,
-		why,
-		when,
-		estimate,
-	)
+// debtLocation checks a file or file:line given to --at and returns it
+// relative to the project
+func debtLocation(at string) (string, error) {
+	if at == "" {
+		return "", nil
+	}
+
+	file, line := at, ""
+	if i := strings.LastIndex(at, ":"); i > 0 {
+		if _, err := strconv.Atoi(at[i+1:]); err == nil {
+			file, line = at[:i], at[i+1:]
+		}
+	}
+	if n, _ := strconv.Atoi(line); line != "" && n < 1 {
+		return "", fmt.Errorf("invalid line in --at %q", at)
+	}
 
-	f, err := os.OpenFile(techDebtPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	abs, err := filepath.Abs(file)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if info, err := os.Stat(abs); err != nil || info.IsDir() {
+		return "", fmt.Errorf("--at %q: no such file", file)
+	}
+	projectDir, err := state.GetProjectDir()
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(projectDir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("--at %q is outside the project", file)
+	}
+
+	location := filepath.ToSlash(rel)
+	if line != "" {
+		location += ":" + line
+	}
+	return location, nil
+}
+
+// printDebtLocation shows where the debt is and how to mark it
+func printDebtLocation(id, at string) {
+	if at == "" {
+		return
+	}
+	fmt.Printf("   At:   %s\n", at)
+	fmt.Printf("   Mark the spot with a comment: yo:debt %s\n", id)
+}
+
+// logTechDebt adds an open entry to the tech debt log and returns its ID
+func logTechDebt(taskID, what, why, when, estimate, at string) (string, error) {
+	if taskID == "" {
+		taskID = "General"
+	}
+	if err := checkDebtTriggers(when); err != nil {
+		return "", err
 	}
-	defer f.Close()
 
-	_, err = f.WriteString(entry)
-	return err
+	l, err := debt.Load()
+	if err != nil {
+		return "", err
+	}
+	return l.Add(debt.Entry{
+		Task:     taskID,
+		What:     what,
+		Why:      why,
+		When:     when,
+		Estimate: estimate,
+		Location: at,
+	}, time.Now())
+}
+
+// checkDebtTriggers rejects a "Come back when" with a malformed trigger
+func checkDebtTriggers(when string) error {
+	if _, invalid := debt.ParseTriggers(when); len(invalid) > 0 {
+		return fmt.Errorf("invalid trigger %q: use after:YYYY-MM-DD, tasks:N, touch:path or tag:word", invalid[0])
+	}
+	return nil
 }
 
 func init() {
 	deferCmd.Flags().BoolVarP(&deferInteractive, "interactive", "i", false, "Interactive mode with guided prompts")
+	deferCmd.Flags().String("why", "", "Why you're skipping it (the tradeoff)")
+	deferCmd.Flags().String("when", "", "When to come back and fix it")
+	deferCmd.Flags().String("estimate", "", "Estimated time to fix later (e.g. 2h)")
+	deferCmd.Flags().StringVar(&deferAt, "at", "", "Where in the code, as path/to/file.go or path/to/file.go:120")
+	deferCmd.Flags().Bool("over-budget", false, "Log it even if it takes the debt over debt_budget_hours")
 	rootCmd.AddCommand(deferCmd)
 }
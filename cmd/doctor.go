@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/faisalahmedsifat/yo/internal/doctor"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the workspace for problems and repair them",
+	Long: `Cross-check state.json, config.json, the task files in tasks/,
+backlog.md, activity.jsonl and the watcher PID file for inconsistencies.
+
+Each problem is reported as an error, warning or info. With --fix every
+problem that can be repaired safely is fixed; broken files are kept
+alongside (e.g. state.json.corrupt) rather than deleted.
+
+Examples:
+  yo doctor
+  yo doctor --fix`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		yoDir, err := state.GetYoDir()
+		if err != nil {
+			return err
+		}
+
+		report, err := doctor.Run(yoDir)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		fmt.Println("🩺 Workspace Check")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("  %s\n", relativeToCwd(yoDir))
+		fmt.Println()
+
+		if len(report.Findings) == 0 {
+			fmt.Println("✅ No problems found")
+			return nil
+		}
+
+		printFindings(report)
+
+		if !doctorFix {
+			if n := report.Fixable(); n > 0 {
+				fmt.Printf("  %d can be fixed with: yo doctor --fix\n", n)
+			}
+			return nil
+		}
+
+		fmt.Println()
+		fmt.Println("🔧 Fixing")
+		for _, f := range report.Findings {
+			if !f.Fixable() {
+				continue
+			}
+			if err := f.Fix(); err != nil {
+				fmt.Printf("  ❌ %s: %v\n", f.FixHint, err)
+				continue
+			}
+			fmt.Printf("  ✅ %s\n", f.FixHint)
+		}
+		fmt.Println()
+
+		after, err := doctor.Run(yoDir)
+		if err != nil {
+			return err
+		}
+		if len(after.Findings) == 0 {
+			fmt.Println("✅ No problems left")
+			return nil
+		}
+
+		fmt.Println("Still left:")
+		fmt.Println()
+		printFindings(after)
+		return nil
+	},
+}
+
+// printFindings lists findings with their fix hints and a summary line
+func printFindings(report *doctor.Report) {
+	for _, f := range report.Findings {
+		fmt.Printf("  %s [%s] %s\n", severityIcon(f.Severity), f.Area, f.Message)
+		if f.Fixable() {
+			fmt.Printf("     fix: %s\n", f.FixHint)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("  %d error(s), %d warning(s), %d info\n",
+		report.Count(doctor.Error), report.Count(doctor.Warning), report.Count(doctor.Info))
+}
+
+func severityIcon(sev doctor.Severity) string {
+	switch sev {
+	case doctor.Error:
+		return "❌"
+	case doctor.Warning:
+		return "⚠️ "
+	default:
+		return "ℹ️ "
+	}
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair every problem that can be fixed safely")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -1,22 +1,26 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/backlog"
+	"github.com/faisalahmedsifat/yo/internal/debt"
 	"github.com/faisalahmedsifat/yo/internal/state"
 	"github.com/faisalahmedsifat/yo/internal/task"
-	"github.com/faisalahmedsifat/yo/internal/templates"
 	"github.com/faisalahmedsifat/yo/internal/timer"
+	"github.com/faisalahmedsifat/yo/internal/verify"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
+var doneSkipCommands bool
+
 var doneCmd = &cobra.Command{
 	Use:   "done",
 	Short: "Complete the current task",
@@ -25,11 +29,22 @@ This will:
   - Verify success criteria
   - Stop the timer
   - Calculate accuracy (actual vs estimated)
-  - Archive the task to done/
-  - Clear current_task.md`,
+  - Archive the task to done/ and remove its file from tasks/
+
+Success criteria with a command (` + "`cmd: go test ./...`" + `) are run first, as by
+'yo verify green', and ticked if they pass; --skip-commands leaves them as
+they are. Their output is attached to the archived task.
+
+Criteria already ticked count as met; only the rest are asked about.
+Answer them without prompts with --criteria-met (all, none, or the
+numbers of the met criteria like 1,3).
+
+With criteria unmet, 'Continue working?' defaults to yes and so does --yes;
+--continue-working=false completes the task anyway. --yes never ticks a
+criterion whose command just failed.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -41,103 +56,201 @@ This will:
 			return fmt.Errorf("can only complete tasks in GREEN LIGHT. Current stage: %s", s.CurrentStage)
 		}
 
-		taskPath, err := workspace.GetCurrentTaskPath()
+		taskPath, err := workspace.GetCurrentTaskPath(s)
 		if err != nil {
 			return err
 		}
 
-		// Get success criteria
-		criteria, err := task.GetSuccessCriteria(taskPath)
+		// Get success criteria; completeTask reports a file it can't load
+		t, err := task.Load(taskPath)
 		if err != nil {
-			fmt.Println("⚠️  Could not load success criteria")
-			criteria = []string{}
+			return completeTask(nil, nil)
+		}
+
+		// Run the criteria backed by commands
+		var results map[int]verify.Result
+		if !doneSkipCommands {
+			if results, err = runDoneCommands(t, s.CurrentTaskID, taskPath); err != nil {
+				return err
+			}
 		}
 
 		// Verify success criteria
-		if len(criteria) > 0 {
+		var met []bool
+		if len(t.Yellow.Criteria) > 0 {
 			fmt.Println("📋 Verify Success Criteria")
 			fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 			fmt.Println()
 
-			reader := bufio.NewReader(os.Stdin)
+			if met, err = askCriteriaMet(t.Yellow.Criteria, results); err != nil {
+				return err
+			}
 			allMet := true
-
-			for _, c := range criteria {
-				fmt.Printf("  [?] %s (y/n): ", c)
-				response, _ := reader.ReadString('\n')
-				response = strings.TrimSpace(strings.ToLower(response))
-				if response != "y" && response != "yes" {
-					allMet = false
-				}
+			for _, m := range met {
+				allMet = allMet && m
 			}
 
 			if !allMet {
 				fmt.Println()
 				fmt.Println("❌ Not all success criteria met.")
-				fmt.Print("   Continue working? (y/n): ")
-				response, _ := reader.ReadString('\n')
-				if strings.TrimSpace(strings.ToLower(response)) != "n" {
+				keepWorking, err := ask.Confirm("continue-working", "   Continue working?", true)
+				if err != nil {
+					return err
+				}
+				if keepWorking {
 					fmt.Println("   Keep working! You've got this. 💪")
 					return nil
 				}
 			}
 		}
 
-		// Calculate time
-		elapsed := s.GetElapsed()
-		actualHours := elapsed.Hours()
-		estimatedHours := s.Timer.EstimatedHours
-		accuracy := (estimatedHours / actualHours) * 100
-		if actualHours == 0 {
-			accuracy = 100
+		return completeTask(met, results)
+	},
+}
+
+// runDoneCommands runs the commands behind the task's success criteria
+// before it is completed and saves the ticks they leave
+func runDoneCommands(t *task.Task, taskID, taskPath string) (map[int]verify.Result, error) {
+	if !hasCriteriaCommands(t) {
+		return nil, nil
+	}
+	fmt.Println("🧪 Running Criteria Commands")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	results, err := runCriteriaCommands(t, taskID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Save(taskPath); err != nil {
+		return nil, err
+	}
+	fmt.Println()
+	return results, nil
+}
+
+// completeTask finishes the GREEN LIGHT task: it ticks the criteria marked
+// in met, archives the task with its criteria command results, ticks the
+// backlog item it came from and resets state. done and dash both end here.
+func completeTask(met []bool, results map[int]verify.Result) error {
+	s, err := state.Load()
+	if err != nil {
+		return err
+	}
+	if s.CurrentStage != "green" {
+		return fmt.Errorf("can only complete tasks in GREEN LIGHT. Current stage: %s", s.CurrentStage)
+	}
+
+	taskPath, err := workspace.GetCurrentTaskPath(s)
+	if err != nil {
+		return err
+	}
+	t, loadErr := task.Load(taskPath)
+	if loadErr != nil {
+		fmt.Println("⚠️  Could not load success criteria")
+		t = task.Parse("")
+	}
+
+	now := time.Now()
+	for i, c := range t.Yellow.Criteria {
+		if i < len(met) && met[i] && !c.Checked {
+			t.CheckCriterion(i, true, now)
+			activity.LogCriterion(s.CurrentTaskID, c.Text, true)
 		}
+	}
 
-		// Archive task
-		if err := archiveTask(s, taskPath); err != nil {
-			fmt.Printf("⚠️  Failed to archive task: %v\n", err)
+	// Calculate time
+	elapsed := s.GetElapsed()
+	actualHours := elapsed.Hours()
+	estimatedHours := s.Timer.EstimatedHours
+	accuracy := (estimatedHours / actualHours) * 100
+	if actualHours == 0 {
+		accuracy = 100
+	}
+
+	// Record the outcome in the task file before archiving it
+	if loadErr == nil {
+		t.Completion.ActualTime = timer.FormatDuration(elapsed)
+		t.Completion.Accuracy = fmt.Sprintf("%.0f%%", accuracy)
+		if err := t.Save(taskPath); err != nil {
+			fmt.Printf("⚠️  Failed to update task file: %v\n", err)
 		}
+	}
 
-		// Log completion
-		activity.LogTaskComplete(s.CurrentTaskID, actualHours, estimatedHours)
+	// Archive task
+	if err := archiveTask(s, t, results, taskPath); err != nil {
+		fmt.Printf("⚠️  Failed to archive task: %v\n", err)
+	}
+
+	// Log completion
+	activity.LogTaskComplete(s.CurrentTaskID, actualHours, estimatedHours)
 
-		// Reset state
-		taskID := s.CurrentTaskID
+	// Tick the backlog item the task came from
+	backlogItem := ""
+	var resolvedDebt []debt.Entry
+	if s.CurrentBacklogID != "" {
+		if bl, err := backlog.Load(); err == nil {
+			if item, ok := bl.Find(s.CurrentBacklogID); ok {
+				if err := bl.Complete(item.ID, time.Now()); err != nil {
+					fmt.Printf("⚠️  Failed to update backlog: %v\n", err)
+				} else {
+					backlogItem = item.Text
+					resolvedDebt = resolvePromotedDebt(item.ID, s.CurrentTaskID)
+				}
+			}
+		}
+	}
+
+	// Reset state
+	taskID := s.CurrentTaskID
+	if s, err = state.Update(func(s *state.State) error {
+		if err := checkSameTask(s, taskID, "green"); err != nil {
+			return err
+		}
 		s.SetStage("none")
 		s.StopTimer()
 		s.CurrentTaskID = ""
 		s.CurrentTaskRepo = ""
-		if err := s.Save(); err != nil {
-			return err
-		}
+		s.CurrentBacklogID = ""
+		return nil
+	}); err != nil {
+		return err
+	}
 
-		// Reset current_task.md using templates package
-		resetCurrentTask()
+	// The archive in done/ replaces the task file
+	removeTaskFile(taskID)
 
-		fmt.Println()
-		fmt.Println("✅ Task Complete!")
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Printf("  Task:      %s\n", taskID)
-		fmt.Printf("  Actual:    %s\n", timer.FormatDuration(elapsed))
-		fmt.Printf("  Estimated: %s\n", timer.FormatHours(estimatedHours))
-		fmt.Printf("  Accuracy:  %.0f%%\n", accuracy)
-		fmt.Println()
+	fmt.Println()
+	fmt.Println("✅ Task Complete!")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("  Task:      %s\n", taskID)
+	fmt.Printf("  Actual:    %s\n", timer.FormatDuration(elapsed))
+	fmt.Printf("  Estimated: %s\n", timer.FormatHours(estimatedHours))
+	fmt.Printf("  Accuracy:  %.0f%%\n", accuracy)
+	if backlogItem != "" {
+		fmt.Printf("  Backlog:   ✓ %s\n", backlogItem)
+	}
+	for _, e := range resolvedDebt {
+		fmt.Printf("  Debt:      ✓ %s (%s)\n", e.What, e.ID)
+	}
+	fmt.Println()
 
-		if accuracy >= 80 && accuracy <= 120 {
-			fmt.Println("  🎯 Great estimation!")
-		} else if actualHours < estimatedHours {
-			fmt.Println("  ⚡ Faster than expected!")
-		} else {
-			fmt.Println("  📝 Consider adding buffer to future estimates")
-		}
+	if accuracy >= 80 && accuracy <= 120 {
+		fmt.Println("  🎯 Great estimation!")
+	} else if actualHours < estimatedHours {
+		fmt.Println("  ⚡ Faster than expected!")
+	} else {
+		fmt.Println("  📝 Consider adding buffer to future estimates")
+	}
 
-		fmt.Println()
-		fmt.Println("  Next: yo next  (pick another task)")
-		fmt.Println("        yo off   (end session)")
-		return nil
-	},
+	fmt.Println()
+	fmt.Println("  Next: yo next  (pick another task)")
+	for _, id := range s.ParkedTaskIDs() {
+		fmt.Printf("        yo switch %s  (parked)\n", id)
+	}
+	fmt.Println("        yo off   (end session)")
+	return nil
 }
 
-func archiveTask(s *state.State, taskPath string) error {
+func archiveTask(s *state.State, t *task.Task, results map[int]verify.Result, taskPath string) error {
 	yoDir, err := state.GetYoDir()
 	if err != nil {
 		return err
@@ -145,12 +258,16 @@ func archiveTask(s *state.State, taskPath string) error {
 
 	// Create archive filename
 	date := time.Now().Format("2006-01-02")
-	archiveName := fmt.Sprintf("%s_%s.md", date, s.CurrentTaskID)
-	archivePath := filepath.Join(yoDir, "done", archiveName)
+	archivePath := filepath.Join(yoDir, "done", fmt.Sprintf("%s_%s.md", date, s.CurrentTaskID))
+
+	// Never overwrite an earlier archive of a task with the same ID
+	for n := 2; fileExists(archivePath); n++ {
+		archivePath = filepath.Join(yoDir, "done", fmt.Sprintf("%s_%s_%d.md", date, s.CurrentTaskID, n))
+	}
 
-	// Copy file
+	// Copy file; a missing task file still leaves the completion record
 	content, err := os.ReadFile(taskPath)
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
@@ -160,21 +277,121 @@ func archiveTask(s *state.State, taskPath string) error {
 		timer.FormatDuration(s.GetElapsed()),
 		timer.FormatHours(s.Timer.EstimatedHours))
 
+	if len(s.Timer.Extensions) > 0 {
+		metadata += fmt.Sprintf("- Extensions: %d (+%s)\n", len(s.Timer.Extensions), timer.FormatHours(s.TotalExtensionHours()))
+		for _, ext := range s.Timer.Extensions {
+			metadata += fmt.Sprintf("  - %s +%s: %s\n", ext.At.Format("2006-01-02 15:04"), timer.FormatHours(ext.Hours), ext.Reason)
+		}
+	}
+
+	if len(t.Yellow.Criteria) > 0 {
+		metadata += fmt.Sprintf("- Criteria Met: %d/%d\n", t.CriteriaMet(), len(t.Yellow.Criteria))
+		for _, c := range t.Yellow.Criteria {
+			switch {
+			case !c.CheckedAt.IsZero():
+				metadata += fmt.Sprintf("  - %s ✓ %s\n", c.CheckedAt.Local().Format("2006-01-02 15:04"), c.Text)
+			case c.Checked:
+				metadata += fmt.Sprintf("  - ✓ %s\n", c.Text)
+			default:
+				metadata += fmt.Sprintf("  - ✗ %s\n", c.Text)
+			}
+		}
+	}
+
+	if len(results) > 0 {
+		metadata += "\n## Criteria Checks\n"
+		for i, c := range t.Yellow.Criteria {
+			r, ok := results[i]
+			if !ok {
+				continue
+			}
+			mark := "❌"
+			if r.Passed {
+				mark = "✅"
+			}
+			metadata += fmt.Sprintf("\n### %s %s\n`%s` %s\n", mark, c.Text, r.Command, r.Summary())
+			if r.Output != "" {
+				metadata += "\n```text\n" + r.Tail(criteriaOutputLines) + "\n```\n"
+			}
+		}
+	}
+
 	content = append(content, []byte(metadata)...)
 
 	return os.WriteFile(archivePath, content, 0644)
 }
 
-func resetCurrentTask() {
-	taskPath, err := workspace.GetCurrentTaskPath()
-	if err != nil {
-		return
+// criteriaOutputLines is how much of a criterion command's output is
+// kept in the archive
+const criteriaOutputLines = 40
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// askCriteriaMet asks which success criteria are met. Ticked ones count as
+// met without asking. --criteria-met answers the rest at once: all, none,
+// or the numbers of the met ones. --yes never counts a criterion whose
+// command just failed as met.
+func askCriteriaMet(criteria []task.Criterion, results map[int]verify.Result) ([]bool, error) {
+	met := make([]bool, len(criteria))
+	for i, c := range criteria {
+		met[i] = c.Checked
+	}
+
+	if ask.Answered("criteria-met") || (ask.NoInput && !ask.Yes) {
+		answer, err := ask.Ask("criteria-met", "")
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(answer) {
+		case "all":
+			for i := range met {
+				met[i] = true
+			}
+		case "none", "":
+		default:
+			for _, part := range strings.Split(answer, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(part))
+				if err != nil || n < 1 || n > len(criteria) {
+					return nil, fmt.Errorf("invalid --criteria-met %q (use all, none or numbers 1-%d)", answer, len(criteria))
+				}
+				met[n-1] = true
+			}
+		}
+		for i, c := range criteria {
+			box := "[ ]"
+			if met[i] {
+				box = "[x]"
+			}
+			fmt.Printf("  %s %s\n", box, c.Text)
+		}
+		return met, nil
 	}
 
-	// Use templates package instead of embedded template
-	os.WriteFile(taskPath, []byte(templates.CurrentTask), 0644)
+	for i, c := range criteria {
+		if c.Checked {
+			fmt.Printf("  [x] %s\n", c.Text)
+			continue
+		}
+		key := fmt.Sprintf("criterion-%d", i+1)
+		if r, ok := results[i]; ok && !r.Passed && ask.Yes && !ask.Answered(key) {
+			fmt.Printf("  [ ] %s (command failed)\n", c.Text)
+			continue
+		}
+		answer, err := ask.Confirm(key, "  [?] "+c.Text, false)
+		if err != nil {
+			return nil, err
+		}
+		met[i] = answer
+	}
+	return met, nil
 }
 
 func init() {
+	doneCmd.Flags().String("criteria-met", "", "Success criteria met: all, none or numbers like 1,3")
+	doneCmd.Flags().Bool("continue-working", true, "Keep working when criteria are unmet; =false completes the task anyway")
+	doneCmd.Flags().BoolVar(&doneSkipCommands, "skip-commands", false, "Don't run the success criteria commands")
 	rootCmd.AddCommand(doneCmd)
 }
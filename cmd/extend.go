@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/timer"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var extendReason string
+
+var extendCmd = &cobra.Command{
+	Use:   "extend <duration>",
+	Short: "Add time to the GREEN LIGHT timer",
+	Long: `Extend the timer threshold when the task needs more time.
+
+Every extension needs a reason, and extensions are limited per task
+(see max_extensions and max_extension_hours in 'yo config list').
+If you keep extending, stop and re-plan instead.
+
+Examples:
+  yo extend 30m --reason "API pagination more complex than expected"
+  yo extend 1h -r "flaky integration tests"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		reason := strings.TrimSpace(extendReason)
+		if reason == "" {
+			return fmt.Errorf("a reason is required: yo extend %s --reason \"why\"", args[0])
+		}
+
+		hours, err := timer.ParseDuration(args[0])
+		if err != nil || hours <= 0 {
+			return fmt.Errorf("invalid time format: %s (use format like 2h, 1.5h, 30m)", args[0])
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		s, err := extendTimer(hours, reason, cfg)
+		var limit *extensionLimitError
+		if errors.As(err, &limit) {
+			fmt.Printf("❌ %s\n", limit.message)
+			for _, hint := range limit.hints {
+				fmt.Printf("   %s\n", hint)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		status := timer.GetStatus(s)
+
+		fmt.Println()
+		fmt.Println("⏱️  Timer extended")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("   Added:      %s\n", timer.FormatHours(hours))
+		fmt.Printf("   Reason:     %s\n", reason)
+		fmt.Printf("   Threshold:  %s (was %s)\n",
+			timer.FormatHours(s.Timer.ThresholdHours), timer.FormatHours(s.Timer.ThresholdHours-hours))
+		fmt.Printf("   Progress:   %.0f%%\n", status.Progress)
+		fmt.Printf("   Extensions: %d/%d\n", len(s.Timer.Extensions), cfg.MaxExtensions)
+		return nil
+	},
+}
+
+// extensionLimitError is an extension refused by the per-task limits
+type extensionLimitError struct {
+	err     string
+	message string   // what was refused, for the user
+	hints   []string // what to do instead
+}
+
+func (e *extensionLimitError) Error() string {
+	return e.err
+}
+
+// extendTimer adds hours to the GREEN timer within the per-task limits in
+// cfg and logs it. Limits are checked under the state lock so the timer
+// monitor can't fire a milestone in between.
+func extendTimer(hours float64, reason string, cfg *config.Config) (*state.State, error) {
+	s, err := state.Update(func(s *state.State) error {
+		if s.CurrentStage != "green" || s.Timer.StartedAt.IsZero() {
+			return fmt.Errorf("can only extend the timer in GREEN LIGHT. Current stage: %s", s.CurrentStage)
+		}
+
+		// Enforce limits
+		if len(s.Timer.Extensions) >= cfg.MaxExtensions {
+			return &extensionLimitError{
+				err:     "extension limit reached",
+				message: fmt.Sprintf("Already extended %d times (max %d per task)", len(s.Timer.Extensions), cfg.MaxExtensions),
+				hints: []string{
+					"Time to re-plan: finish a smaller scope with 'yo done'",
+					"and log the rest with 'yo defer'.",
+				},
+			}
+		}
+
+		total := s.TotalExtensionHours()
+		if total+hours > cfg.MaxExtensionHours {
+			limit := &extensionLimitError{
+				err: "extension hours limit reached",
+				message: fmt.Sprintf("Extensions would total %s (max %s per task)",
+					timer.FormatHours(total+hours), timer.FormatHours(cfg.MaxExtensionHours)),
+			}
+			if remaining := cfg.MaxExtensionHours - total; remaining > 0 {
+				limit.hints = []string{fmt.Sprintf("At most %s left to extend.", timer.FormatHours(remaining))}
+			}
+			return limit
+		}
+
+		s.ExtendTimer(hours, reason)
+		timer.ResetMilestones(s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.LogTimerExtension(s.CurrentTaskID, hours, reason)
+	return s, nil
+}
+
+func init() {
+	extendCmd.Flags().StringVarP(&extendReason, "reason", "r", "", "Why more time is needed (required)")
+	rootCmd.AddCommand(extendCmd)
+}
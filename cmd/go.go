@@ -1,10 +1,7 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/faisalahmedsifat/yo/internal/activity"
@@ -32,7 +29,7 @@ This command:
 Use --time to override the estimated time.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -40,6 +37,10 @@ Use --time to override the estimated time.`,
 			return err
 		}
 
+		if err := checkBypass(s); err != nil {
+			return err
+		}
+
 		// Check stage
 		if s.CurrentStage == "green" {
 			elapsed := s.GetElapsed()
@@ -52,16 +53,19 @@ Use --time to override the estimated time.`,
 			return fmt.Errorf("complete RED and YELLOW first. Run 'yo red'")
 		}
 
-		taskPath, err := workspace.GetCurrentTaskPath()
+		taskID := s.CurrentTaskID
+		taskPath, err := workspace.GetCurrentTaskPath(s)
 		if err != nil {
 			return err
 		}
 
-		// Validate RED
-		redResult, err := task.ValidateRed(taskPath)
+		t, err := task.Load(taskPath)
 		if err != nil {
 			return err
 		}
+
+		// Validate RED
+		redResult := t.ValidateRed()
 		if !redResult.Valid {
 			fmt.Println("❌ RED LIGHT incomplete:")
 			for _, e := range redResult.Errors {
@@ -71,10 +75,7 @@ Use --time to override the estimated time.`,
 		}
 
 		// Validate YELLOW
-		yellowResult, err := task.ValidateYellow(taskPath)
-		if err != nil {
-			return err
-		}
+		yellowResult := t.ValidateYellow()
 		if !yellowResult.Valid {
 			fmt.Println("❌ YELLOW LIGHT incomplete:")
 			for _, e := range yellowResult.Errors {
@@ -91,13 +92,14 @@ Use --time to override the estimated time.`,
 				return fmt.Errorf("invalid time format: %s (use format like 2h, 1.5h, 30m)", goTimeOverride)
 			}
 		} else {
+			printWarnings(yellowResult)
 			estimatedHours, err = task.GetTimeEstimate(taskPath)
 			if err != nil {
-				fmt.Println("⚠️  No time estimate found in task.")
-				fmt.Print("   Enter time estimate (e.g., 2h, 1.5h): ")
-				reader := bufio.NewReader(os.Stdin)
-				timeStr, _ := reader.ReadString('\n')
-				timeStr = strings.TrimSpace(timeStr)
+				fmt.Printf("⚠️  %s\n", err)
+				timeStr, err := ask.Ask("time", "   Enter time estimate (e.g., 2h, 1.5h): ")
+				if err != nil {
+					return err
+				}
 				estimatedHours, err = timer.ParseDuration(timeStr)
 				if err != nil || estimatedHours == 0 {
 					return fmt.Errorf("invalid time format")
@@ -106,29 +108,27 @@ Use --time to override the estimated time.`,
 		}
 
 		// Update task file with green light info
-		content, err := os.ReadFile(taskPath)
-		if err != nil {
-			return err
-		}
-		text := string(content)
 		now := time.Now()
-		text = strings.Replace(text, "### Timer Started:", fmt.Sprintf("### Timer Started: %s", now.Format("2006-01-02 15:04")), 1)
-		text = strings.Replace(text, "### Estimated Time:", fmt.Sprintf("### Estimated Time: %.1fh", estimatedHours), 1)
-		if err := os.WriteFile(taskPath, []byte(text), 0644); err != nil {
+		t.Green.TimerStarted = now.Format("2006-01-02 15:04")
+		t.Green.EstimatedTime = fmt.Sprintf("%.1fh", estimatedHours)
+		if err := t.Save(taskPath); err != nil {
 			return err
 		}
 
 		// Update state
 		oldStage := s.CurrentStage
-		s.SetStage("green")
-		s.StartTimer(estimatedHours)
-		s.StartSession()
-
-		// Get current repo
-		cwd, _ := os.Getwd()
-		s.CurrentTaskRepo = cwd
-
-		if err := s.Save(); err != nil {
+		repo, _ := state.GetProjectDir()
+		s, err = state.Update(func(s *state.State) error {
+			if err := checkSameTask(s, taskID, oldStage); err != nil {
+				return err
+			}
+			s.SetStage("green")
+			s.StartTimer(estimatedHours)
+			s.StartSession()
+			s.CurrentTaskRepo = repo
+			return nil
+		})
+		if err != nil {
 			return err
 		}
 
@@ -139,6 +139,9 @@ Use --time to override the estimated time.`,
 		fmt.Println("🟢 GREEN LIGHT - Execution Started!")
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 		fmt.Printf("   Task:      %s\n", s.CurrentTaskID)
+		if opt := t.Chosen(); opt != nil && goTimeOverride == "" {
+			fmt.Printf("   Option:    %s\n", opt.Label)
+		}
 		fmt.Printf("   Threshold: %s\n", timer.FormatHours(estimatedHours))
 		fmt.Printf("   Started:   %s\n", now.Format("15:04"))
 		fmt.Println()
@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/faisalahmedsifat/yo/internal/registry"
+	"github.com/faisalahmedsifat/yo/internal/state"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -11,7 +13,7 @@ var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize a yo workspace in the current directory",
 	Long: `Creates a .yo/ directory structure with:
-  - current_task.md: The RED/YELLOW/GREEN task template
+  - tasks/: Your RED/YELLOW/GREEN tasks, one <id>.md each
   - backlog.md: Your prioritized task backlog
   - tech_debt_log.md: Deferred decisions and shortcuts
   - AGENTS.md: Instructions for AI agents
@@ -22,10 +24,18 @@ var initCmd = &cobra.Command{
 		if err := workspace.Init(); err != nil {
 			return err
 		}
+
+		// Record the workspace so 'yo status --all' and the watcher find it
+		if projectDir, err := state.GetProjectDir(); err == nil {
+			if err := registry.Register(projectDir); err != nil {
+				fmt.Printf("⚠️  Could not register workspace: %v\n", err)
+			}
+		}
+
 		fmt.Println("✅ Workspace initialized!")
 		fmt.Println("")
 		fmt.Println("Created .yo/ with:")
-		fmt.Println("  📄 current_task.md  - Your RED/YELLOW/GREEN task")
+		fmt.Println("  📄 tasks/           - Your RED/YELLOW/GREEN tasks")
 		fmt.Println("  📋 backlog.md       - Prioritized task list")
 		fmt.Println("  📝 tech_debt_log.md - Deferred decisions")
 		fmt.Println("  🤖 AGENTS.md        - AI agent instructions")
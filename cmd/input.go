@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/faisalahmedsifat/yo/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptNoInput bool
+	promptYes     bool
+	promptAnswers string
+)
+
+// ask answers the questions commands would otherwise read from stdin
+var ask = prompt.New(os.Stdin, os.Stdout)
+
+// setupPrompter points ask at the running command's flags, the answers
+// file and the --no-input/--yes switches
+func setupPrompter(cmd *cobra.Command) error {
+	ask.Flags = cobraFlags{cmd}
+	ask.NoInput = promptNoInput
+	ask.Yes = promptYes
+
+	if promptAnswers != "" {
+		answers, err := prompt.LoadAnswers(promptAnswers)
+		if err != nil {
+			return usageError{err}
+		}
+		ask.Answers = answers
+	}
+	return nil
+}
+
+// answerOr returns the flag or answers-file answer for key without
+// asking, or def when there is none
+func answerOr(key, def string) string {
+	if !ask.Answered(key) {
+		return def
+	}
+	answer, _ := ask.Ask(key, "")
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+// offer asks a yes/no question that only offers an extra step once the
+// command's work is done. Under --no-input without an answer the offer is
+// declined rather than failing a command that already succeeded.
+func offer(key, question string) (bool, error) {
+	if ask.NoInput && !ask.Yes && !ask.Answered(key) {
+		return false, nil
+	}
+	return ask.Confirm(key, question, false)
+}
+
+// cobraFlags lets the prompter read answers given as command flags
+type cobraFlags struct {
+	cmd *cobra.Command
+}
+
+func (f cobraFlags) Lookup(key string) ([]string, bool) {
+	flag := f.cmd.Flags().Lookup(key)
+	if flag == nil || !flag.Changed {
+		return nil, false
+	}
+	if slice, ok := flag.Value.(interface{ GetSlice() []string }); ok {
+		return slice.GetSlice(), true
+	}
+	return []string{flag.Value.String()}, true
+}
+
+func (f cobraFlags) Has(key string) bool {
+	return f.cmd.Flags().Lookup(key) != nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&promptNoInput, "no-input", false, "Never read stdin: fail on questions not answered by flags or --answers")
+	rootCmd.PersistentFlags().BoolVarP(&promptYes, "yes", "y", false, "Answer yes to every confirmation")
+	rootCmd.PersistentFlags().StringVar(&promptAnswers, "answers", "", "JSON or YAML file of answers to prompts, keyed like the flags")
+}
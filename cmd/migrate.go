@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var migrateDryRun bool
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the workspace to this version of yo",
+	Long: `Upgrade an older .yo workspace (state.json, config.json and the
+directory layout) to the schema this version of yo uses.
+
+The original state.json and config.json are copied to .yo/backups/ first.
+Other commands run this automatically; use --dry-run to see what would
+change without touching anything.
+
+Examples:
+  yo migrate --dry-run
+  yo migrate`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		yoDir, err := state.GetYoDir()
+		if err != nil {
+			return err
+		}
+
+		result, err := state.Migrate(yoDir, migrateDryRun)
+		if err != nil {
+			return err
+		}
+
+		if !result.Pending() {
+			fmt.Printf("✅ Workspace is up to date (schema %s)\n", result.To)
+			return nil
+		}
+
+		fmt.Println()
+		fmt.Println("🔧 Workspace Migration")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("  Schema: %s → %s\n", result.From, result.To)
+		fmt.Println()
+		for _, m := range result.Steps {
+			fmt.Printf("  %s → %s: %s\n", m.From, m.To, m.Description)
+		}
+		fmt.Println()
+		fmt.Println("  Changes:")
+		for _, change := range result.Changes {
+			fmt.Printf("    • %s\n", change)
+		}
+		fmt.Println()
+
+		if migrateDryRun {
+			fmt.Println("  Dry run: nothing was changed. Apply with: yo migrate")
+			return nil
+		}
+
+		fmt.Printf("✅ Migrated. Originals backed up to %s\n", relativeToCwd(result.BackupDir))
+		return nil
+	},
+}
+
+// migrateIfNeeded upgrades an older workspace before a command runs, and
+// refuses to run against one written by a newer yo
+func migrateIfNeeded(cmd *cobra.Command) error {
+	switch cmd.Name() {
+	case "init", "migrate", "doctor", "prompt", "version", "help":
+		return nil
+	}
+
+	if !workspace.IsInitialized() {
+		return nil
+	}
+
+	yoDir, err := state.GetYoDir()
+	if err != nil {
+		return err
+	}
+
+	needed, err := state.NeedsMigration(yoDir)
+	if err != nil || !needed {
+		return err
+	}
+
+	result, err := state.Migrate(yoDir, false)
+	if err != nil {
+		return fmt.Errorf("workspace migration failed: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "🔧 Migrated workspace %s → %s (backup: %s)\n", result.From, result.To, relativeToCwd(result.BackupDir))
+	return nil
+}
+
+// relativeToCwd shortens a path for display when it is below the current
+// directory
+func relativeToCwd(path string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+func init() {
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show what would change without writing anything")
+	rootCmd.AddCommand(migrateCmd)
+}
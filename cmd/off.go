@@ -1,12 +1,10 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/faisalahmedsifat/yo/internal/activity"
@@ -25,10 +23,13 @@ This will:
   - Show activity summary
   - Calculate focus score
   - Save session to sessions/
-  - Optionally pause or complete current task`,
+  - Optionally pause or complete current task
+
+A task still in GREEN LIGHT rolls to tomorrow with its timer paused;
+--roll=false leaves the timer running.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -41,7 +42,6 @@ This will:
 		}
 
 		sessionDuration := time.Since(s.Session.StartedAt)
-		reader := bufio.NewReader(os.Stdin)
 
 		fmt.Println()
 		fmt.Println("📊 Session Summary")
@@ -58,20 +58,45 @@ This will:
 		fmt.Printf("  Focus:     %.0f%%\n", focusScore)
 
 		// Handle current task
+		roll := false
 		if s.CurrentStage == "green" {
 			fmt.Println()
 			fmt.Printf("  Current task: %s (IN PROGRESS)\n", s.CurrentTaskID)
-			elapsed := s.GetElapsed()
-			fmt.Printf("  Time on task: %s\n", timer.FormatDuration(elapsed))
+			fmt.Printf("  Time on task: %s\n", timer.FormatDuration(s.GetElapsed()))
 			fmt.Println()
 
-			fmt.Print("  Task is incomplete. Roll to tomorrow? (y/n): ")
-			response, _ := reader.ReadString('\n')
-			if strings.TrimSpace(strings.ToLower(response)) == "n" {
+			if roll, err = ask.Confirm("roll", "  Task is incomplete. Roll to tomorrow?", true); err != nil {
+				return err
+			}
+			if !roll {
 				fmt.Println("  Task left in progress. Continue tomorrow with 'yo status'")
 			}
 		}
 
+		// End session but keep task state. Don't count the night as
+		// task time when the task rolls over.
+		sessionStart := s.Session.StartedAt
+		paused := false
+		var elapsed float64
+		ended, err := state.Update(func(s *state.State) error {
+			if !s.Session.Active || !s.Session.StartedAt.Equal(sessionStart) {
+				return fmt.Errorf("the session was already ended")
+			}
+			if roll && s.CurrentStage == "green" && !s.Timer.Paused {
+				elapsed = s.GetElapsed().Hours()
+				paused = s.PauseTimer() == nil
+			}
+			s.EndSession()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if paused {
+			activity.LogTimerPause(ended.CurrentTaskID, elapsed, "session ended")
+			fmt.Println("  ⏸️  Timer paused. Pick it up with 'yo resume'")
+		}
+
 		// Save session
 		if err := saveSession(s, sessionDuration, focusScore); err != nil {
 			fmt.Printf("⚠️  Failed to save session: %v\n", err)
@@ -80,20 +105,16 @@ This will:
 		// Log session end
 		activity.LogSessionEnd(int(sessionDuration.Minutes()), s.CurrentTaskRepo, focusScore)
 
-		// End session but keep task state
-		s.EndSession()
-		if err := s.Save(); err != nil {
-			return err
-		}
-
 		fmt.Println()
 		fmt.Println("👋 Session ended. Great work today!")
 		fmt.Println()
 
 		// Ask about backlog
-		fmt.Print("  Any new issues for backlog? (y/n): ")
-		response, _ := reader.ReadString('\n')
-		if strings.TrimSpace(strings.ToLower(response)) == "y" {
+		newIssues, err := offer("new-issues", "  Any new issues for backlog?")
+		if err != nil {
+			return err
+		}
+		if newIssues {
 			fmt.Println("  Add them with: yo add \"issue description\"")
 		}
 
@@ -108,7 +129,7 @@ func calculateFocusScore(entries []activity.Entry, currentRepo string) float64 {
 
 	var onTask, total int
 	for _, e := range entries {
-		if e.Type == activity.TypeFileChange {
+		if e.IsFileEvent() {
 			total++
 			if e.Repo == currentRepo || !e.Untracked {
 				onTask++
@@ -165,5 +186,6 @@ func saveSession(s *state.State, duration time.Duration, focusScore float64) err
 }
 
 func init() {
+	offCmd.Flags().Bool("roll", true, "Roll an unfinished task to tomorrow, pausing its timer")
 	rootCmd.AddCommand(offCmd)
 }
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/faisalahmedsifat/yo/internal/output"
+	"github.com/faisalahmedsifat/yo/internal/prompt"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputFlag   string
+	outputFormat = output.Text
+)
+
+// usageError marks errors caused by bad flags or arguments
+type usageError struct{ err error }
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+// setOutputFormat validates --output
+func setOutputFormat(cmd *cobra.Command) error {
+	f, err := output.ParseFormat(outputFlag)
+	if err != nil {
+		return usageError{err}
+	}
+	outputFormat = f
+	return nil
+}
+
+// presetOutputFormat reads --output from the raw arguments before cobra
+// parses them, so flag errors can be reported as error objects too.
+// Structured formats silence cobra's own error and usage printing.
+func presetOutputFormat(args []string) {
+	for i, arg := range args {
+		value := ""
+		switch {
+		case arg == "--":
+			return
+		case arg == "-o" || arg == "--output":
+			if i+1 < len(args) {
+				value = args[i+1]
+			}
+		case strings.HasPrefix(arg, "--output="):
+			value = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "-o") && len(arg) > 2:
+			value = strings.TrimPrefix(strings.TrimPrefix(arg, "-o"), "=")
+		default:
+			continue
+		}
+
+		if f, err := output.ParseFormat(value); err == nil {
+			outputFormat = f
+		}
+	}
+
+	if structured() {
+		rootCmd.SilenceErrors = true
+		rootCmd.SilenceUsage = true
+	}
+}
+
+// structured reports whether the command should print JSON or YAML
+func structured() bool {
+	return outputFormat.Structured()
+}
+
+// printOutput prints v in the --output format
+func printOutput(v interface{}) error {
+	return output.Write(os.Stdout, outputFormat, v)
+}
+
+// Exit codes by error kind, shared by text and structured output
+const (
+	exitError          = 1
+	exitUsage          = 2
+	exitNotInitialized = 3
+	exitInputRequired  = 4
+)
+
+// describeError classifies a failed command's error
+func describeError(err error) output.Error {
+	e := output.Error{Code: "error", Message: err.Error(), ExitCode: exitError}
+	var usage usageError
+	var missing *prompt.MissingError
+	switch {
+	case errors.Is(err, workspace.ErrNotInitialized):
+		e.Code, e.ExitCode = "not_initialized", exitNotInitialized
+	case errors.As(err, &usage):
+		e.Code, e.ExitCode = "usage", exitUsage
+	case errors.As(err, &missing):
+		e.Code, e.ExitCode = "input_required", exitInputRequired
+	}
+	return e
+}
+
+// printError prints a failed command's error object
+func printError(e output.Error) {
+	output.WriteError(os.Stdout, outputFormat, e)
+}
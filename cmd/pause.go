@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/timer"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause [reason]",
+	Short: "Pause the GREEN LIGHT timer",
+	Long: `Pause the timer while you step away (lunch, meetings, interruptions).
+
+Paused time does not count towards the task, so your estimation
+accuracy stays honest. Resume with 'yo resume'.
+
+Examples:
+  yo pause
+  yo pause "standup"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		reason := strings.Join(args, " ")
+		s, err := pauseTimer(reason)
+		if err != nil {
+			return err
+		}
+		elapsed := s.GetElapsed()
+
+		fmt.Println()
+		fmt.Println("⏸️  Timer paused")
+		fmt.Printf("   Task:   %s\n", s.CurrentTaskID)
+		fmt.Printf("   Active: %s\n", timer.FormatDuration(elapsed))
+		if reason != "" {
+			fmt.Printf("   Reason: %s\n", reason)
+		}
+		fmt.Println()
+		fmt.Println("   Resume with: yo resume")
+		return nil
+	},
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume the paused GREEN LIGHT timer",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		s, paused, err := resumeTimer()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		fmt.Println("▶️  Timer resumed")
+		fmt.Printf("   Task:   %s\n", s.CurrentTaskID)
+		fmt.Printf("   Paused: %s\n", timer.FormatDuration(paused))
+		fmt.Printf("   Active: %s\n", timer.FormatDuration(s.GetElapsed()))
+		return nil
+	},
+}
+
+// pauseTimer pauses the GREEN timer and logs it
+func pauseTimer(reason string) (*state.State, error) {
+	s, err := state.Update(func(s *state.State) error {
+		if s.CurrentStage != "green" {
+			return fmt.Errorf("timer only runs in GREEN LIGHT. Current stage: %s", s.CurrentStage)
+		}
+		return s.PauseTimer()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity.LogTimerPause(s.CurrentTaskID, s.GetElapsed().Hours(), reason)
+	return s, nil
+}
+
+// resumeTimer resumes the paused GREEN timer and logs how long it was paused
+func resumeTimer() (*state.State, time.Duration, error) {
+	var pausedSince time.Time
+	s, err := state.Update(func(s *state.State) error {
+		if s.CurrentStage != "green" {
+			return fmt.Errorf("timer only runs in GREEN LIGHT. Current stage: %s", s.CurrentStage)
+		}
+		pausedSince = s.PausedSince()
+		return s.ResumeTimer()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var paused time.Duration
+	if !pausedSince.IsZero() {
+		paused = time.Since(pausedSince)
+	}
+	activity.LogTimerResume(s.CurrentTaskID, int(paused.Minutes()))
+	return s, paused, nil
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeCmd)
+}
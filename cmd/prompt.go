@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/statusline"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptFormat string
+	promptInit   string
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a compact stage/timer segment for shell prompts",
+	Long: `Print the current stage and timer in one short line, e.g.
+
+  🟢 fix_login 42m/2h
+
+Made to run on every prompt: it reads only state.json (through a small
+cache next to it), never migrates or writes the workspace, and prints
+nothing outside a workspace.
+
+Change the segment with --format or $YO_PROMPT_FORMAT. Placeholders:
+  {icon} {stage} {task} {id} {elapsed} {estimate} {progress} {paused} {timer}
+
+  yo prompt --format "{stage}:{task} {progress}"
+
+Print a ready-made snippet for your shell or tmux with --init:
+
+  yo prompt --init bash >> ~/.bashrc
+  yo prompt --init zsh  >> ~/.zshrc
+  yo prompt --init fish >> ~/.config/fish/config.fish
+  yo prompt --init tmux >> ~/.tmux.conf`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if promptInit != "" {
+			snippet, err := statusline.Snippet(promptInit)
+			if err != nil {
+				return usageError{err}
+			}
+			fmt.Print(snippet)
+			return nil
+		}
+
+		yoDir, err := state.GetYoDir()
+		if err != nil {
+			return err
+		}
+		snap, err := statusline.Load(yoDir)
+		if errors.Is(err, state.ErrNotInitialized) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		format := promptFormat
+		if format == "" {
+			format = os.Getenv(statusline.FormatEnv)
+		}
+		if format == "" {
+			format = statusline.DefaultFormat
+		}
+
+		if line := snap.Render(format, time.Now()); line != "" {
+			fmt.Println(line)
+		}
+		return nil
+	},
+}
+
+func init() {
+	promptCmd.Flags().StringVar(&promptFormat, "format", "", "Segment format (default \""+statusline.DefaultFormat+"\")")
+	promptCmd.Flags().StringVar(&promptInit, "init", "", "Print the setup snippet for "+strings.Join(statusline.Shells(), ", "))
+	rootCmd.AddCommand(promptCmd)
+}
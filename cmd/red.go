@@ -1,13 +1,14 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/debt"
 	"github.com/faisalahmedsifat/yo/internal/state"
 	"github.com/faisalahmedsifat/yo/internal/task"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
@@ -16,6 +17,7 @@ import (
 
 var (
 	redEdit bool
+	redPark bool
 )
 
 var redCmd = &cobra.Command{
@@ -28,10 +30,15 @@ Before writing any code, you must clearly define:
   - What is the severity (P0-P3)?
 
 By default, runs in interactive mode with guided prompts.
-Use -e to open the editor directly.`,
+Use -e to open the editor directly, or answer every prompt with flags:
+
+  yo red --problem "Login fails for SSO users" --impact users,frustration --severity P1
+
+--impact takes numbers (1-5) or names: launch, users, frustration, debt,
+other. If a task is already underway, --park sets it aside first.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -39,178 +46,250 @@ Use -e to open the editor directly.`,
 			return err
 		}
 
-		taskPath, err := workspace.GetCurrentTaskPath()
-		if err != nil {
+		if err := checkBypass(s); err != nil {
 			return err
 		}
 
 		// Check if already in RED stage (e.g., from yo next)
 		if s.CurrentStage == "red" {
-			fmt.Printf("⚠️  Already in RED stage for: %s\n", s.CurrentTaskID)
-			fmt.Println()
-			fmt.Println("  [1] Continue - Fill in missing impact/severity")
-			fmt.Println("  [2] Start new - Begin fresh RED LIGHT")
-			fmt.Println("  [q] Cancel")
-			fmt.Print("> ")
-
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			response = strings.TrimSpace(response)
-
-			switch response {
-			case "1":
+			choice, err := redExistingChoice(cmd, s)
+			if err != nil {
+				return err
+			}
+
+			switch choice {
+			case "continue":
+				taskPath, err := workspace.GetCurrentTaskPath(s)
+				if err != nil {
+					return err
+				}
+
 				// Get existing problem from task file
-				content, _ := os.ReadFile(taskPath)
-				problem := extractProblem(string(content))
+				problem := ""
+				if t, err := task.Load(taskPath); err == nil {
+					problem = t.Red.Problem
+				}
 				if problem == "" {
 					problem = s.CurrentTaskID
 				}
 				return runRedContinue(taskPath, s, problem)
-			case "2":
+			case "new":
 				// Start fresh
-				return runRedInteractive(taskPath, s)
+				return runRedInteractive(s, false)
+			case "park":
+				return runRedInteractive(s, true)
 			default:
 				return nil
 			}
 		}
 
 		// Check if in other stages
+		park := false
 		if s.CurrentStage != "none" && s.CurrentStage != "" {
 			fmt.Printf("⚠️  Already in %s stage.\n", strings.ToUpper(s.CurrentStage))
-			if !promptConfirm("Start a new RED LIGHT anyway?") {
-				return nil
+			if s.HasTask() {
+				if park, err = ask.Confirm("park", fmt.Sprintf("Park %s and start a new task?", s.CurrentTaskID), false); err != nil {
+					return err
+				}
+			}
+			if !park {
+				if start, err := ask.Confirm("start-new", "Start a new RED LIGHT anyway?", false); err != nil {
+					return err
+				} else if !start {
+					fmt.Println("   Cancelled. Use --park or --yes to start a new task.")
+					return nil
+				}
 			}
 		}
 
 		if redEdit {
-			// Open editor
-			fmt.Println("🔴 Opening current_task.md for RED LIGHT...")
-			fmt.Println("   Fill in the problem definition, then save and close.")
-			fmt.Println()
+			return runRedEditor(s, park)
+		}
 
-			if err := openEditor(taskPath); err != nil {
-				return fmt.Errorf("failed to open editor: %w", err)
-			}
+		// Default: Interactive mode
+		return runRedInteractive(s, park)
+	},
+}
 
-			// Update state
-			oldStage := s.CurrentStage
-			s.SetStage("red")
-			if err := s.Save(); err != nil {
-				return err
-			}
+// runRedEditor starts a new task in the editor. The file is created as
+// task_<suffix> and renamed after the problem once it is filled in. With
+// park the active task is parked, otherwise it is replaced.
+func runRedEditor(s *state.State, park bool) error {
+	taskID := task.MakeID("", "")
+	taskPath, err := workspace.CreateTask(taskID)
+	if err != nil {
+		return err
+	}
 
-			// Log stage change
-			activity.LogStageChange(oldStage, "red", s.CurrentTaskID)
+	fmt.Printf("🔴 Opening tasks/%s.md for RED LIGHT...\n", taskID)
+	fmt.Println("   Fill in the problem definition, then save and close.")
+	fmt.Println()
+
+	if err := openEditor(taskPath); err != nil {
+		os.Remove(taskPath)
+		return fmt.Errorf("failed to open editor: %w", err)
+	}
 
-			fmt.Println()
-			fmt.Println("✅ RED LIGHT started!")
-			fmt.Println("   Next: yo yellow  (analyze and plan)")
-			return nil
+	problem := ""
+	if t, err := task.Load(taskPath); err == nil {
+		problem = t.Red.Problem
+	}
+	if problem != "" {
+		named := task.MakeID(problem, task.IDSuffix(taskID))
+		if path, err := workspace.GetTaskPath(named); err == nil && !fileExists(path) && os.Rename(taskPath, path) == nil {
+			taskID = named
 		}
+	}
 
-		// Default: Interactive mode
-		return runRedInteractive(taskPath, s)
-	},
+	if err := startRedTask(s, taskID, park); err != nil {
+		if path, perr := workspace.GetTaskPath(taskID); perr == nil {
+			fmt.Printf("   Your edits are kept in %s\n", path)
+		}
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("✅ RED LIGHT started!")
+	fmt.Printf("   Task ID: %s\n", taskID)
+	fmt.Println("   Next: yo yellow  (analyze and plan)")
+	if problem != "" {
+		printRedDebt(problem)
+	}
+	return nil
 }
 
-func runRedInteractive(taskPath string, s *state.State) error {
+// runRedInteractive asks for the problem, impact and severity of a new
+// task. With park the active task is parked, otherwise it is replaced.
+func runRedInteractive(s *state.State, park bool) error {
 	fmt.Println("🔴 RED LIGHT - Interactive Mode")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
 	// Get problem description
-	fmt.Print("What's the problem?\n> ")
-	problem := readLine()
+	problem, err := ask.Ask("problem", "What's the problem?\n> ")
+	if err != nil {
+		return err
+	}
 	if problem == "" {
 		return fmt.Errorf("problem description cannot be empty")
 	}
 
 	// Get impact (multi-select simulation)
-	fmt.Println()
-	fmt.Println("What's the impact? (enter numbers, comma-separated)")
-	fmt.Println("  1. Blocks launch")
-	fmt.Println("  2. Blocks paying users")
-	fmt.Println("  3. Causes user frustration")
-	fmt.Println("  4. Tech debt accumulation")
-	fmt.Println("  5. Other")
-	fmt.Print("> ")
-	impactStr := readLine()
-	impacts := parseNumbers(impactStr)
+	impactStr, err := ask.Ask("impact", "\n"+impactMenu)
+	if err != nil {
+		return err
+	}
+	impacts, err := parseImpacts(impactStr)
+	if err != nil {
+		return err
+	}
 	if len(impacts) == 0 {
 		return fmt.Errorf("at least one impact must be selected")
 	}
 
 	// Get severity
-	fmt.Println()
-	fmt.Println("Severity?")
-	fmt.Println("  0. P0 - Launch blocker")
-	fmt.Println("  1. P1 - Paying user blocker")
-	fmt.Println("  2. P2 - Nice to have")
-	fmt.Println("  3. P3 - Future improvement")
-	fmt.Print("> ")
-	severityStr := readLine()
-	severity := 0
-	fmt.Sscanf(severityStr, "%d", &severity)
-	if severity < 0 || severity > 3 {
-		return fmt.Errorf("invalid severity (0-3)")
-	}
-
-	// Read current template
-	content, err := os.ReadFile(taskPath)
+	severityStr, err := ask.Ask("severity", "\n"+severityMenu)
 	if err != nil {
 		return err
 	}
-
-	// Update template with answers
-	text := string(content)
-
-	// Replace problem placeholder
-	text = strings.Replace(text, "<!-- Describe the problem clearly -->", problem, 1)
-
-	// Check impact boxes
-	impactMap := map[int]string{
-		1: "Blocks launch",
-		2: "Blocks paying users",
-		3: "Causes user frustration",
-		4: "Tech debt accumulation",
-		5: "Other",
-	}
-	for _, i := range impacts {
-		if label, ok := impactMap[i]; ok {
-			text = strings.Replace(text, "- [ ] "+label, "- [x] "+label, 1)
+	severity := 0
+	if severityStr != "" {
+		if severity, err = parseSeverity(severityStr); err != nil {
+			return err
 		}
 	}
 
-	// Check severity box
-	severityLabels := []string{
-		"P0 - Launch blocker",
-		"P1 - Paying user blocker",
-		"P2 - Nice to have",
-		"P3 - Future improvement",
+	// Write the answers to a new task file
+	taskID := task.MakeID(problem, "")
+	taskPath, err := workspace.CreateTask(taskID)
+	if err != nil {
+		return err
 	}
-	text = strings.Replace(text, "- [ ] "+severityLabels[severity], "- [x] "+severityLabels[severity], 1)
-
-	// Write updated content
-	if err := os.WriteFile(taskPath, []byte(text), 0644); err != nil {
+	t, err := task.Load(taskPath)
+	if err != nil {
 		return err
 	}
+	t.Red.Problem = problem
+	markImpacts(t, impacts)
+	markSeverity(t, severity)
 
-	// Update state
-	oldStage := s.CurrentStage
-	s.SetStage("red")
-	taskID := sanitizeTaskID(problem)
-	s.CurrentTaskID = taskID
-	if err := s.Save(); err != nil {
+	if err := t.Save(taskPath); err != nil {
+		os.Remove(taskPath)
 		return err
 	}
 
-	// Log stage change
-	activity.LogStageChange(oldStage, "red", taskID)
+	if err := startRedTask(s, taskID, park); err != nil {
+		os.Remove(taskPath)
+		return err
+	}
 
 	fmt.Println()
 	fmt.Println("✅ RED LIGHT complete!")
 	fmt.Printf("   Task ID: %s\n", taskID)
 	fmt.Println("   Next: yo yellow  (analyze and plan)")
+	printRedDebt(problem)
+	return nil
+}
+
+// printRedDebt shows the tech debt a new problem fires by tag and any
+// other debt that has come due
+func printRedDebt(problem string) {
+	fired := fireTagTriggers(problem)
+	shown := map[string]bool{}
+	if len(fired) > 0 {
+		fmt.Println()
+		printDueDebt("This problem touches deferred tech debt:", fired)
+		for _, d := range fired {
+			shown[d.Entry.ID] = true
+		}
+	}
+
+	var others []debt.Due
+	for _, d := range dueDebt() {
+		if !shown[d.Entry.ID] {
+			others = append(others, d)
+		}
+	}
+	if len(others) > 0 {
+		fmt.Println()
+		printDueDebt("Tech debt due:", others)
+	}
+}
+
+// startRedTask makes taskID the active task in RED. The task that was
+// active when s was loaded is parked, or without park replaced and its
+// file removed; if another command changed it meanwhile nothing happens.
+func startRedTask(s *state.State, taskID string, park bool) error {
+	from, fromStage := s.CurrentTaskID, s.CurrentStage
+	var parked taskMove
+	ns, err := state.Update(func(s *state.State) error {
+		if err := checkSameTask(s, from, fromStage); err != nil {
+			return err
+		}
+		if park && s.HasTask() {
+			var err error
+			if parked, err = parkCurrentTask(s); err != nil {
+				return err
+			}
+			fromStage = "none"
+		}
+		s.SetStage("red")
+		s.CurrentTaskID = taskID
+		s.CurrentBacklogID = ""
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	parked.log()
+	if parked.id != "" {
+		fmt.Printf("\n🅿️  Parked %s (yo switch %s to come back)\n", parked.id, parked.id)
+	} else if _, shared := ns.Tasks[from]; from != "" && from != taskID && !shared {
+		// Replaced without parking
+		removeTaskFile(from)
+	}
+	activity.LogStageChange(fromStage, "red", taskID)
 	return nil
 }
 
@@ -230,57 +309,119 @@ func openEditor(filepath string) error {
 	return cmd.Run()
 }
 
-func readLine() string {
-	reader := bufio.NewReader(os.Stdin)
-	line, _ := reader.ReadString('\n')
-	return strings.TrimSpace(line)
-}
+// redExistingChoice decides what to do when RED is already underway:
+// continue, new, park or cancel. Flags imply the answer: --park parks,
+// --problem starts fresh and --impact/--severity fill in the current task.
+func redExistingChoice(cmd *cobra.Command, s *state.State) (string, error) {
+	switch {
+	case redPark:
+		return "park", nil
+	case cmd.Flags().Changed("problem"):
+		return "new", nil
+	case cmd.Flags().Changed("impact") || cmd.Flags().Changed("severity"):
+		return "continue", nil
+	}
 
-func parseNumbers(s string) []int {
-	var nums []int
-	parts := strings.Split(s, ",")
-	for _, p := range parts {
-		p = strings.TrimSpace(p)
-		var n int
-		if _, err := fmt.Sscanf(p, "%d", &n); err == nil {
-			nums = append(nums, n)
-		}
+	answer, err := ask.Ask("existing", fmt.Sprintf(`⚠️  Already in RED stage for: %s
+
+  [1] Continue - Fill in missing impact/severity
+  [2] Start new - Begin fresh RED LIGHT
+  [3] Park it - Keep this task for later, start a new one
+  [q] Cancel
+> `, s.CurrentTaskID))
+	if err != nil {
+		return "", err
+	}
+
+	switch strings.ToLower(answer) {
+	case "1", "continue":
+		return "continue", nil
+	case "2", "new":
+		return "new", nil
+	case "3", "park":
+		return "park", nil
 	}
-	return nums
+	return "cancel", nil
+}
+
+// impactLabels are the impact checkboxes in menu order (1-based)
+var impactLabels = []string{
+	"Blocks launch",
+	"Blocks paying users",
+	"Causes user frustration",
+	"Tech debt accumulation",
+	"Other",
 }
 
-func sanitizeTaskID(s string) string {
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "_")
-	// Keep only alphanumeric and underscores
-	var result strings.Builder
-	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
-			result.WriteRune(r)
+// impactNames are the short names --impact accepts, in menu order
+var impactNames = []string{"launch", "users", "frustration", "debt", "other"}
+
+// severityLabels are the severity checkboxes in menu order (P0-P3)
+var severityLabels = []string{
+	"P0 - Launch blocker",
+	"P1 - Paying user blocker",
+	"P2 - Nice to have",
+	"P3 - Future improvement",
+}
+
+const impactMenu = `What's the impact? (enter numbers, comma-separated)
+  1. Blocks launch
+  2. Blocks paying users
+  3. Causes user frustration
+  4. Tech debt accumulation
+  5. Other
+> `
+
+const severityMenu = `Severity?
+  0. P0 - Launch blocker
+  1. P1 - Paying user blocker
+  2. P2 - Nice to have
+  3. P3 - Future improvement
+> `
+
+// parseImpacts reads a comma-separated list of impact numbers (1-5) or
+// names (launch, users, frustration, debt, other)
+func parseImpacts(s string) ([]int, error) {
+	var impacts []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
 		}
+		n := 0
+		for i, name := range impactNames {
+			if part == name || part == strconv.Itoa(i+1) {
+				n = i + 1
+			}
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("invalid impact %q (use 1-5 or %s)", part, strings.Join(impactNames, ", "))
+		}
+		impacts = append(impacts, n)
 	}
-	id := result.String()
-	if len(id) > 30 {
-		id = id[:30]
-	}
-	return id
+	return impacts, nil
 }
 
-func promptConfirm(question string) bool {
-	return task.PromptConfirm(question)
+// parseSeverity reads a severity as 0-3 or P0-P3
+func parseSeverity(s string) (int, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "P")
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 3 {
+		return 0, fmt.Errorf("invalid severity (0-3 or P0-P3)")
+	}
+	return n, nil
 }
 
-// extractProblem extracts the problem description from task file
-func extractProblem(text string) string {
-	if idx := strings.Index(text, "### What's the Problem?"); idx != -1 {
-		endIdx := strings.Index(text[idx:], "### Impact")
-		if endIdx != -1 {
-			problem := strings.TrimSpace(text[idx+len("### What's the Problem?") : idx+endIdx])
-			problem = strings.TrimPrefix(problem, "<!-- Describe the problem clearly -->")
-			return strings.TrimSpace(problem)
+func markImpacts(t *task.Task, impacts []int) {
+	for _, i := range impacts {
+		if i >= 1 && i <= len(impactLabels) {
+			t.CheckImpact(impactLabels[i-1])
 		}
 	}
-	return ""
+}
+
+func markSeverity(t *task.Task, severity int) {
+	t.SetSeverity(severityLabels[severity])
 }
 
 // runRedContinue fills in impact/severity for an existing problem (from yo next)
@@ -291,64 +432,39 @@ func runRedContinue(taskPath string, s *state.State, problem string) error {
 	fmt.Printf("  Problem: %s\n", problem)
 	fmt.Println()
 
-	reader := bufio.NewReader(os.Stdin)
-
 	// Impact
-	fmt.Println("What's the impact? (enter numbers, comma-separated)")
-	fmt.Println("  1. Blocks launch")
-	fmt.Println("  2. Blocks paying users")
-	fmt.Println("  3. Causes user frustration")
-	fmt.Println("  4. Tech debt accumulation")
-	fmt.Println("  5. Other")
-	fmt.Print("> ")
-	impactStr, _ := reader.ReadString('\n')
-	impacts := parseNumbers(strings.TrimSpace(impactStr))
-
-	// Severity
-	fmt.Println()
-	fmt.Println("Severity?")
-	fmt.Println("  0. P0 - Launch blocker")
-	fmt.Println("  1. P1 - Paying user blocker")
-	fmt.Println("  2. P2 - Nice to have")
-	fmt.Println("  3. P3 - Future improvement")
-	fmt.Print("> ")
-	severityStr, _ := reader.ReadString('\n')
-	var severity int
-	fmt.Sscanf(strings.TrimSpace(severityStr), "%d", &severity)
-
-	// Read and update current task file
-	content, err := os.ReadFile(taskPath)
+	impactStr, err := ask.Ask("impact", impactMenu)
+	if err != nil {
+		return err
+	}
+	impacts, err := parseImpacts(impactStr)
 	if err != nil {
 		return err
 	}
-	text := string(content)
 
-	// Update impact checkboxes
-	impactMap := map[int]string{
-		1: "Blocks launch",
-		2: "Blocks paying users",
-		3: "Causes user frustration",
-		4: "Tech debt accumulation",
-		5: "Other",
+	// Severity (left as is when skipped)
+	severityStr, err := ask.Ask("severity", "\n"+severityMenu)
+	if err != nil {
+		return err
 	}
-	for _, i := range impacts {
-		if label, ok := impactMap[i]; ok {
-			text = strings.Replace(text, "- [ ] "+label, "- [x] "+label, 1)
+	severity := -1
+	if severityStr != "" {
+		if severity, err = parseSeverity(severityStr); err != nil {
+			return err
 		}
 	}
 
-	// Update severity
-	severities := []string{
-		"P0 - Launch blocker",
-		"P1 - Paying user blocker",
-		"P2 - Nice to have",
-		"P3 - Future improvement",
+	// Update the task file
+	t, err := task.Load(taskPath)
+	if err != nil {
+		return err
 	}
-	if severity >= 0 && severity < len(severities) {
-		text = strings.Replace(text, "- [ ] "+severities[severity], "- [x] "+severities[severity], 1)
+	markImpacts(t, impacts)
+	if severity >= 0 && severity <= 3 {
+		markSeverity(t, severity)
 	}
 
-	if err := os.WriteFile(taskPath, []byte(text), 0644); err != nil {
+	if err := t.Save(taskPath); err != nil {
 		return err
 	}
 
@@ -365,5 +481,9 @@ func runRedContinue(taskPath string, s *state.State, problem string) error {
 
 func init() {
 	redCmd.Flags().BoolVarP(&redEdit, "edit", "e", false, "Open in editor instead of interactive mode")
+	redCmd.Flags().String("problem", "", "What's the problem")
+	redCmd.Flags().StringSlice("impact", nil, "Impact: launch, users, frustration, debt, other (or 1-5)")
+	redCmd.Flags().String("severity", "", "Severity: P0-P3")
+	redCmd.Flags().BoolVar(&redPark, "park", false, "Park the active task and start a new one")
 	rootCmd.AddCommand(redCmd)
 }
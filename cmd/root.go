@@ -26,17 +26,36 @@ var rootCmd = &cobra.Command{
 
 yo tracks your progress, monitors file changes, and provides helpful
 nudges to keep you on track.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := setOutputFormat(cmd); err != nil {
+			return err
+		}
+		if err := setupPrompter(cmd); err != nil {
+			return err
+		}
+		return migrateIfNeeded(cmd)
+	},
 }
 
 // Execute runs the root command
 func Execute() {
+	presetOutputFormat(os.Args[1:])
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		e := describeError(err)
+		if structured() {
+			printError(e)
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(e.ExitCode)
 	}
 }
 
 func init() {
 	// Global flags can be added here
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "text", "Output format for read commands: text, json or yaml")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
 }
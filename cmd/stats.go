@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/faisalahmedsifat/yo/internal/output"
 	"github.com/faisalahmedsifat/yo/internal/stats"
 	"github.com/faisalahmedsifat/yo/internal/timer"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
@@ -25,7 +26,7 @@ Examples:
   yo stats --week 2024-12-20  - Specific week`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		var weekStart, weekEnd = stats.GetCurrentWeekRange()
@@ -44,6 +45,14 @@ Examples:
 			return err
 		}
 
+		if structured() {
+			report := &output.Stats{WeekStats: weekStats, Insights: []string{}}
+			if insights := stats.GenerateInsights(weekStats); len(insights.Messages) > 0 {
+				report.Insights = insights.Messages
+			}
+			return printOutput(report)
+		}
+
 		// Display
 		fmt.Println()
 		fmt.Printf("📊 Week of %s - %s\n", weekStart.Format("Jan 2"), weekEnd.Format("Jan 2"))
@@ -56,6 +65,9 @@ Examples:
 			fmt.Print(" 🚨")
 		}
 		fmt.Println()
+		if weekStats.Extensions > 0 {
+			fmt.Printf("  Timer extensions:   %d (+%s)\n", weekStats.Extensions, timer.FormatHours(weekStats.ExtensionHours))
+		}
 		fmt.Println()
 
 		if weekStats.TasksCompleted > 0 {
@@ -71,6 +83,17 @@ Examples:
 			fmt.Println()
 		}
 
+		if d := weekStats.Debt; d.Created > 0 || d.Resolved > 0 || d.Outstanding > 0 {
+			fmt.Println("  Tech debt:")
+			fmt.Printf("    Deferred: %d, Resolved: %d\n", d.Created, d.Resolved)
+			fmt.Printf("    Outstanding: %d", d.Outstanding)
+			if d.OutstandingHours > 0 {
+				fmt.Printf(" (%s to fix)", timer.FormatHours(d.OutstandingHours))
+			}
+			fmt.Println()
+			fmt.Println()
+		}
+
 		fmt.Printf("  Focus score: %.0f%%\n", weekStats.FocusScore)
 		if weekStats.FocusScore >= 80 {
 			fmt.Println("  🌟 Excellent focus!")
@@ -5,19 +5,34 @@ import (
 	"strings"
 	"time"
 
+	"github.com/faisalahmedsifat/yo/internal/output"
+	"github.com/faisalahmedsifat/yo/internal/registry"
 	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
 	"github.com/faisalahmedsifat/yo/internal/timer"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
+var statusAll bool
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show current yo status",
-	Long:  `Display the current stage, task, timer, and session information.`,
+	Long: `Display the current stage, task, timer, and session information.
+
+The workspace is found by walking up from the current directory to the
+nearest .yo, or taken from $YO_DIR.
+
+Use --all to summarise every workspace registered by 'yo init'.
+Use --output json or yaml for scripts and prompts.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if statusAll {
+			return printAllStatus()
+		}
+
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -25,6 +40,20 @@ var statusCmd = &cobra.Command{
 			return err
 		}
 
+		if structured() {
+			projectDir, err := state.GetProjectDir()
+			if err != nil {
+				return err
+			}
+			status := output.NewStatus(s, projectDir)
+			status.TaskFile, _ = workspace.GetCurrentTaskPath(s)
+			if t := currentTask(s); t != nil {
+				status.Criteria = &output.CriteriaMet{Met: t.CriteriaMet(), Total: len(t.Yellow.Criteria)}
+			}
+			status.DebtDue = output.NewDebtDue(dueDebt())
+			return printOutput(status)
+		}
+
 		printStatus(s)
 		return nil
 	},
@@ -37,24 +66,20 @@ func printStatus(s *state.State) {
 	fmt.Println()
 
 	// Stage with emoji
-	stageEmoji := map[string]string{
-		"none":   "⚪",
-		"red":    "🔴",
-		"yellow": "🟡",
-		"green":  "🟢",
-	}
-	emoji := stageEmoji[s.CurrentStage]
-	if emoji == "" {
-		emoji = "⚪"
-	}
-	fmt.Printf("  Stage: %s %s\n", emoji, strings.ToUpper(s.CurrentStage))
+	fmt.Printf("  Stage: %s %s\n", stageEmoji(s.CurrentStage), strings.ToUpper(s.CurrentStage))
 
 	// Task
 	if s.CurrentTaskID != "" {
 		fmt.Printf("  Task:  %s\n", s.CurrentTaskID)
+		if taskPath, err := workspace.GetCurrentTaskPath(s); err == nil {
+			fmt.Printf("  File:  %s\n", taskPath)
+		}
 		if s.CurrentTaskRepo != "" {
 			fmt.Printf("  Repo:  %s\n", s.CurrentTaskRepo)
 		}
+		if t := currentTask(s); t != nil && len(t.Yellow.Criteria) > 0 {
+			fmt.Printf("  Criteria: %d/%d met\n", t.CriteriaMet(), len(t.Yellow.Criteria))
+		}
 	} else {
 		fmt.Println("  Task:  (none)")
 	}
@@ -68,12 +93,39 @@ func printStatus(s *state.State) {
 		fmt.Printf("    Elapsed:   %s\n", timer.FormatDuration(status.Elapsed))
 		fmt.Printf("    Threshold: %s\n", timer.FormatHours(status.ThresholdHours))
 		fmt.Printf("    Progress:  %.0f%%\n", status.Progress)
+		if status.Paused {
+			fmt.Printf("    Paused:    since %s\n", s.PausedSince().Format("15:04"))
+		}
 
 		if status.Extensions > 0 {
 			fmt.Printf("    Extensions: %d\n", status.Extensions)
 		}
 	}
 
+	// Bypass
+	if s.CurrentStage == "bypass" {
+		fmt.Println("  Bypass:")
+		fmt.Printf("    Reason:    %s\n", s.Bypass.Reason)
+		if s.BypassExpired() {
+			fmt.Println("    Remaining: ⏰ expired - document what you fixed")
+		} else {
+			fmt.Printf("    Remaining: %s (until %s)\n", timer.FormatDuration(s.BypassRemaining()), s.BypassExpiresAt().Format("15:04"))
+		}
+		if s.Bypass.PreviousStage != "" && s.Bypass.PreviousStage != "none" {
+			fmt.Printf("    Returns to: %s\n", strings.ToUpper(s.Bypass.PreviousStage))
+		}
+	}
+
+	// Parked tasks
+	if parked := s.ParkedTaskIDs(); len(parked) > 0 {
+		fmt.Println()
+		fmt.Println("  Parked:")
+		for _, id := range parked {
+			t := s.Tasks[id]
+			fmt.Printf("    %s %s (%s)\n", stageEmoji(t.Stage), id, strings.ToUpper(t.Stage))
+		}
+	}
+
 	// Session
 	if s.Session.Active {
 		sessionDuration := time.Since(s.Session.StartedAt)
@@ -91,6 +143,12 @@ func printStatus(s *state.State) {
 			s.EmergencyBypasses.Today, s.EmergencyBypasses.ThisWeek)
 	}
 
+	// Tech debt whose "come back when" trigger fired
+	if due := dueDebt(); len(due) > 0 {
+		fmt.Println()
+		printDueDebt("Tech debt due:", due)
+	}
+
 	fmt.Println()
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
@@ -98,18 +156,140 @@ func printStatus(s *state.State) {
 	switch s.CurrentStage {
 	case "none":
 		fmt.Println("  Next: yo red  (define a problem)")
+		if len(s.Tasks) > 0 {
+			fmt.Println("        yo switch <id>  (resume a parked task)")
+		}
 	case "red":
 		fmt.Println("  Next: yo verify red  (validate problem definition)")
 		fmt.Println("        yo yellow   (analyze and plan)")
 	case "yellow":
 		fmt.Println("  Next: yo verify yellow  (validate plan)")
 		fmt.Println("        yo go            (start execution)")
+	case "bypass":
+		fmt.Println("  Next: yo bypass --note \"what you fixed\"  (close the bypass)")
 	case "green":
 		fmt.Println("  Next: yo done    (mark complete)")
 		fmt.Println("        yo extend  (extend timer)")
+		if s.Timer.Paused {
+			fmt.Println("        yo resume  (resume timer)")
+		} else {
+			fmt.Println("        yo pause   (pause timer)")
+		}
+	}
+}
+
+// printAllStatus prints one line per registered workspace
+func printAllStatus() error {
+	r, err := registry.Load()
+	if err != nil {
+		return err
+	}
+
+	if structured() {
+		return printOutput(allStatus(r))
+	}
+
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("  yo status --all\n")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println()
+
+	if len(r.Workspaces) == 0 {
+		fmt.Println("  (no workspaces registered)")
+		fmt.Println()
+		fmt.Println("  Workspaces are registered by: yo init")
+		return nil
+	}
+
+	for _, w := range r.Workspaces {
+		if !w.Exists() {
+			fmt.Printf("  ⚪ %-20s (missing: %s)\n", w.Name(), w.Path)
+			continue
+		}
+
+		s, err := state.LoadFrom(w.YoDir())
+		if err != nil {
+			fmt.Printf("  ⚪ %-20s (%v)\n", w.Name(), err)
+			continue
+		}
+
+		line := fmt.Sprintf("  %s %-20s %-7s", stageEmoji(s.CurrentStage), w.Name(), strings.ToUpper(s.CurrentStage))
+		if s.CurrentTaskID != "" {
+			line += " " + s.CurrentTaskID
+		}
+		if s.CurrentStage == "green" && !s.Timer.StartedAt.IsZero() {
+			status := timer.GetStatus(s)
+			line += fmt.Sprintf(" [%s / %s, %.0f%%", timer.FormatDuration(status.Elapsed), timer.FormatHours(status.ThresholdHours), status.Progress)
+			if status.Paused {
+				line += ", paused"
+			}
+			line += "]"
+		}
+		if parked := len(s.Tasks); parked > 0 {
+			line += fmt.Sprintf(" +%d parked", parked)
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	return nil
+}
+
+// allStatus builds the --output form of 'yo status --all'
+func allStatus(r *registry.Registry) *output.AllStatus {
+	all := &output.AllStatus{Workspaces: []output.WorkspaceStatus{}}
+	for _, w := range r.Workspaces {
+		ws := output.WorkspaceStatus{Name: w.Name(), Path: w.Path}
+		if !w.Exists() {
+			ws.Error = "missing"
+			all.Workspaces = append(all.Workspaces, ws)
+			continue
+		}
+
+		s, err := state.LoadFrom(w.YoDir())
+		if err != nil {
+			ws.Error = err.Error()
+			all.Workspaces = append(all.Workspaces, ws)
+			continue
+		}
+
+		status := output.NewStatus(s, w.Path)
+		ws.Stage, ws.TaskID, ws.Timer, ws.Parked = status.Stage, status.TaskID, status.Timer, len(status.Parked)
+		all.Workspaces = append(all.Workspaces, ws)
+	}
+	return all
+}
+
+// currentTask loads the active task's file, or returns nil without a task
+func currentTask(s *state.State) *task.Task {
+	taskPath, err := workspace.GetCurrentTaskPath(s)
+	if err != nil {
+		return nil
+	}
+	t, err := task.Load(taskPath)
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+// stageEmoji returns the traffic light emoji for a stage
+func stageEmoji(stage string) string {
+	switch stage {
+	case "red":
+		return "🔴"
+	case "yellow":
+		return "🟡"
+	case "green":
+		return "🟢"
+	case "bypass":
+		return "🚨"
+	default:
+		return "⚪"
 	}
 }
 
 func init() {
+	statusCmd.Flags().BoolVar(&statusAll, "all", false, "Summarise every registered workspace")
 	rootCmd.AddCommand(statusCmd)
 }
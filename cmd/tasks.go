@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/timer"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "List the active and parked tasks",
+	Long: `List your tasks: the active one and any parked ones, each with its
+own stage and timer. Every task's file is .yo/tasks/<id>.md.
+
+Switch between them with 'yo switch <id>'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		s, err := state.Load()
+		if err != nil {
+			return err
+		}
+
+		parked := s.ParkedTaskIDs()
+
+		fmt.Println()
+		fmt.Println("📋 Tasks")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+		if !s.HasTask() && len(parked) == 0 {
+			fmt.Println("   No tasks. Start one with: yo red")
+			return nil
+		}
+
+		if s.HasTask() {
+			fmt.Printf(" * %-30s %s %-7s %s\n", s.CurrentTaskID, stageEmoji(s.CurrentStage),
+				strings.ToUpper(s.CurrentStage), taskTimerSummary(s.CurrentStage, s.Timer))
+		}
+		for _, id := range parked {
+			t := s.Tasks[id]
+			fmt.Printf("   %-30s %s %-7s %s (parked %s)\n", id, stageEmoji(t.Stage),
+				strings.ToUpper(t.Stage), taskTimerSummary(t.Stage, t.Timer), formatParkedAt(t.ParkedAt))
+		}
+
+		fmt.Println()
+		if len(parked) > 0 {
+			fmt.Println("   Switch with: yo switch <id>")
+		}
+		return nil
+	},
+}
+
+var parkCmd = &cobra.Command{
+	Use:   "park",
+	Short: "Set the active task aside (its timer pauses)",
+	Long: `Park the active task to deal with an interruption.
+
+The task's stage and timer are saved and its file stays in
+.yo/tasks/<id>.md. A running GREEN LIGHT timer is paused until you
+switch back with 'yo switch <id>'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		s, err := state.Load()
+		if err != nil {
+			return err
+		}
+
+		if err := checkBypass(s); err != nil {
+			return err
+		}
+
+		var parked taskMove
+		s, err = state.Update(func(s *state.State) error {
+			parked, err = parkCurrentTask(s)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		parked.log()
+
+		id := parked.id
+		fmt.Println()
+		fmt.Println("🅿️  Task parked")
+		fmt.Printf("   Task:  %s\n", id)
+		fmt.Printf("   Stage: %s\n", strings.ToUpper(parked.stage))
+		if s.Tasks[id].PausedTimer {
+			fmt.Println("   Timer: paused")
+		}
+		fmt.Println()
+		fmt.Println("   Start something else with: yo red")
+		fmt.Printf("   Come back with:            yo switch %s\n", id)
+		return nil
+	},
+}
+
+var switchCmd = &cobra.Command{
+	Use:   "switch <id>",
+	Short: "Switch to a parked task",
+	Long: `Make a parked task the active one.
+
+The currently active task (if any) is parked first, so nothing is lost.
+See the task IDs with 'yo tasks'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !workspace.IsInitialized() {
+			return workspace.ErrNotInitialized
+		}
+
+		s, err := state.Load()
+		if err != nil {
+			return err
+		}
+
+		if err := checkBypass(s); err != nil {
+			return err
+		}
+
+		id := args[0]
+		if id == s.CurrentTaskID && s.HasTask() {
+			fmt.Printf("Already working on %s\n", id)
+			return nil
+		}
+
+		var parked, unparked taskMove
+		s, err = state.Update(func(s *state.State) error {
+			if _, ok := s.Tasks[id]; !ok {
+				if ids := s.ParkedTaskIDs(); len(ids) > 0 {
+					return fmt.Errorf("no parked task %q. Parked: %s", id, strings.Join(ids, ", "))
+				}
+				return fmt.Errorf("no parked task %q", id)
+			}
+
+			if s.HasTask() {
+				if parked, err = parkCurrentTask(s); err != nil {
+					return err
+				}
+			}
+			unparked, err = unparkTask(s, id)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		parked.log()
+		unparked.log()
+
+		from := parked.id
+		activity.LogTaskSwitch(from, id, s.CurrentStage)
+
+		fmt.Println()
+		fmt.Printf("%s Switched to %s\n", stageEmoji(s.CurrentStage), id)
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("   Stage: %s\n", strings.ToUpper(s.CurrentStage))
+		if s.CurrentStage == "green" {
+			fmt.Printf("   Timer: %s\n", taskTimerSummary(s.CurrentStage, s.Timer))
+		}
+		if from != "" {
+			fmt.Printf("   Parked: %s\n", from)
+		}
+		return nil
+	},
+}
+
+// taskMove is a task parked or unparked in state, logged once the state
+// is saved
+type taskMove struct {
+	id       string
+	stage    string
+	parked   bool
+	paused   bool      // a parked task's timer was paused
+	elapsed  float64   // hours on the timer when it was paused
+	resumed  bool      // an unparked task's timer was resumed
+	pausedAt time.Time // when the unparked task was parked
+}
+
+// log records the move in the activity log
+func (m taskMove) log() {
+	switch {
+	case m.id == "":
+	case m.parked:
+		if m.paused {
+			activity.LogTimerPause(m.id, m.elapsed, "parked")
+		}
+		activity.LogTaskPark(m.id, m.stage)
+	case m.resumed:
+		activity.LogTimerResume(m.id, int(time.Since(m.pausedAt).Minutes()))
+	}
+}
+
+// parkCurrentTask parks the active task in state. Its file stays in
+// tasks/<id>.md. Call log on the result once state is saved.
+func parkCurrentTask(s *state.State) (taskMove, error) {
+	m := taskMove{id: s.CurrentTaskID, stage: s.CurrentStage, parked: true, elapsed: s.GetElapsed().Hours()}
+	if err := s.ParkTask(); err != nil {
+		return taskMove{}, err
+	}
+	m.paused = s.Tasks[m.id].PausedTimer
+	return m, nil
+}
+
+// unparkTask makes a parked task active. Call log on the result once
+// state is saved.
+func unparkTask(s *state.State, id string) (taskMove, error) {
+	m := taskMove{id: id, stage: s.Tasks[id].Stage, resumed: s.Tasks[id].PausedTimer, pausedAt: s.Tasks[id].ParkedAt}
+	if err := s.UnparkTask(id); err != nil {
+		return taskMove{}, err
+	}
+	return m, nil
+}
+
+// checkSameTask refuses to apply answers collected for task id in stage
+// when another command changed the active task in the meantime
+func checkSameTask(s *state.State, id, stage string) error {
+	if s.CurrentTaskID == id && s.CurrentStage == stage {
+		return nil
+	}
+	now := "no task"
+	if s.CurrentTaskID != "" {
+		now = fmt.Sprintf("%s in %s", s.CurrentTaskID, strings.ToUpper(s.CurrentStage))
+	}
+	return fmt.Errorf("the active task changed while you were answering (now %s). Run the command again", now)
+}
+
+// removeTaskFile deletes a task's file once it is finished or abandoned
+func removeTaskFile(id string) {
+	if id == "" {
+		return
+	}
+	if path, err := workspace.GetTaskPath(id); err == nil {
+		os.Remove(path)
+	}
+}
+
+// taskTimerSummary describes a task's timer for listings
+func taskTimerSummary(stage string, t state.Timer) string {
+	if stage != "green" || t.StartedAt.IsZero() {
+		return ""
+	}
+	summary := fmt.Sprintf("%s / %s", timer.FormatDuration(t.Elapsed()), timer.FormatHours(t.ThresholdHours))
+	if t.Paused {
+		summary += " ⏸️"
+	}
+	return summary
+}
+
+func formatParkedAt(t time.Time) string {
+	if t.Format("2006-01-02") == time.Now().Format("2006-01-02") {
+		return t.Format("15:04")
+	}
+	return t.Format("Jan 2 15:04")
+}
+
+func init() {
+	rootCmd.AddCommand(tasksCmd)
+	rootCmd.AddCommand(parkCmd)
+	rootCmd.AddCommand(switchCmd)
+}
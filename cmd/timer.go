@@ -7,6 +7,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/faisalahmedsifat/yo/internal/output"
 	"github.com/faisalahmedsifat/yo/internal/state"
 	"github.com/faisalahmedsifat/yo/internal/timer"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
@@ -20,10 +21,11 @@ var timerCmd = &cobra.Command{
 	Short: "Show the current timer",
 	Long: `Display the elapsed time, estimate, and progress percentage.
 
-Use -w or --watch for a live updating timer.`,
+Use -w or --watch for a live updating timer, or --output json for a
+single machine-readable reading.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -31,6 +33,16 @@ Use -w or --watch for a live updating timer.`,
 			return err
 		}
 
+		// A reading is always the schema; timer is null when none runs
+		if structured() {
+			return printOutput(output.NewTimerReport(s))
+		}
+
+		if s.CurrentStage == "bypass" {
+			printBypassTimer(s)
+			return nil
+		}
+
 		if s.CurrentStage != "green" {
 			return fmt.Errorf("timer only runs in GREEN LIGHT. Current stage: %s", s.CurrentStage)
 		}
@@ -70,6 +82,10 @@ func runLiveTimer(s *state.State) error {
 			fmt.Println("  Use 'yo done' when complete.")
 			return nil
 		case <-ticker.C:
+			// Reload so pauses from other terminals show up
+			if fresh, err := state.Load(); err == nil {
+				s = fresh
+			}
 			drawLiveTimer(s)
 		}
 	}
@@ -94,6 +110,9 @@ func drawLiveTimer(s *state.State) {
 	fmt.Println()
 	fmt.Printf("  Elapsed:   %s\n", timer.FormatDurationWithSeconds(status.Elapsed))
 	fmt.Printf("  Estimate:  %s\n", timer.FormatHours(status.ThresholdHours))
+	if status.Paused {
+		fmt.Println("  ⏸️  Paused (yo resume to continue)")
+	}
 
 	if status.Overtime > 0 {
 		fmt.Println()
@@ -120,6 +139,9 @@ func printTimer(s *state.State) {
 	fmt.Println()
 	fmt.Printf("  Elapsed:   %s\n", timer.FormatDuration(status.Elapsed))
 	fmt.Printf("  Estimate:  %s\n", timer.FormatHours(status.ThresholdHours))
+	if status.Paused {
+		fmt.Printf("  Paused:    since %s\n", s.PausedSince().Format("15:04"))
+	}
 
 	if status.Overtime > 0 {
 		fmt.Println()
@@ -129,10 +151,40 @@ func printTimer(s *state.State) {
 	fmt.Println()
 	fmt.Println("  Commands:")
 	fmt.Println("    yo timer -w  - Live timer")
+	if status.Paused {
+		fmt.Println("    yo resume    - Resume timer")
+	} else {
+		fmt.Println("    yo pause     - Pause timer")
+	}
 	fmt.Println("    yo done      - Complete task")
 	fmt.Println()
 }
 
+func printBypassTimer(s *state.State) {
+	limit := time.Duration(s.Bypass.DurationMinutes) * time.Minute
+	used := time.Since(s.Bypass.StartedAt)
+	progress := 0.0
+	if limit > 0 {
+		progress = float64(used) / float64(limit) * 100
+	}
+
+	fmt.Println()
+	fmt.Println("🚨 Bypass Timer")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println()
+	fmt.Printf("  %s [%s] %.0f%%\n", timer.ProgressIndicator(progress), timer.ProgressBar(progress, 30), progress)
+	fmt.Println()
+	fmt.Printf("  Reason:    %s\n", s.Bypass.Reason)
+	if s.BypassExpired() {
+		fmt.Printf("  Expired:   %s ago\n", timer.FormatDuration(used-limit))
+	} else {
+		fmt.Printf("  Remaining: %s\n", timer.FormatDuration(s.BypassRemaining()))
+	}
+	fmt.Println()
+	fmt.Println("  Close it with: yo bypass --note \"what you fixed\"")
+	fmt.Println()
+}
+
 func init() {
 	timerCmd.Flags().BoolVarP(&timerWatch, "watch", "w", false, "Live updating timer")
 	rootCmd.AddCommand(timerCmd)
@@ -2,28 +2,49 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/state"
 	"github.com/faisalahmedsifat/yo/internal/task"
+	"github.com/faisalahmedsifat/yo/internal/timer"
+	"github.com/faisalahmedsifat/yo/internal/verify"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
+var verifyTimeout time.Duration
+
 var verifyCmd = &cobra.Command{
-	Use:   "verify [red|yellow]",
+	Use:   "verify [red|yellow|green]",
 	Short: "Verify that a phase is complete",
-	Long: `Verify validates that a phase (RED or YELLOW) is properly completed.
+	Long: `Verify validates that a phase (RED, YELLOW or GREEN) is properly completed.
+
+GREEN runs the commands behind success criteria written like
+  - [ ] tests pass ` + "`cmd: go test ./...`" + `
+from the project directory, ticks the ones that pass and unticks the ones
+that fail. Each command is killed after criteria_timeout seconds
+('yo config set criteria_timeout 600') or --timeout.
 
 Examples:
   yo verify red     - Validate RED LIGHT is complete
-  yo verify yellow  - Validate YELLOW LIGHT is complete`,
+  yo verify yellow  - Validate YELLOW LIGHT is complete
+  yo verify green   - Run the criteria commands and check every criterion is met`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
+		}
+
+		s, err := state.Load()
+		if err != nil {
+			return err
 		}
 
 		phase := args[0]
-		taskPath, err := workspace.GetCurrentTaskPath()
+		taskPath, err := workspace.GetCurrentTaskPath(s)
 		if err != nil {
 			return err
 		}
@@ -33,8 +54,10 @@ Examples:
 			return verifyRed(taskPath)
 		case "yellow":
 			return verifyYellow(taskPath)
+		case "green":
+			return verifyGreen(taskPath)
 		default:
-			return fmt.Errorf("unknown phase: %s (use 'red' or 'yellow')", phase)
+			return fmt.Errorf("unknown phase: %s (use 'red', 'yellow' or 'green')", phase)
 		}
 	},
 }
@@ -61,13 +84,16 @@ func verifyRed(taskPath string) error {
 }
 
 func verifyYellow(taskPath string) error {
-	result, err := task.ValidateYellow(taskPath)
+	t, err := task.Load(taskPath)
 	if err != nil {
 		return err
 	}
+	result := t.ValidateYellow()
 
 	if result.Valid {
 		fmt.Println("✅ YELLOW LIGHT is complete!")
+		printChosenOption(t)
+		printWarnings(result)
 		fmt.Println("   Next: yo go  (start execution)")
 		return nil
 	}
@@ -76,11 +102,140 @@ func verifyYellow(taskPath string) error {
 	for _, e := range result.Errors {
 		fmt.Printf("   - %s: %s\n", e.Field, e.Message)
 	}
+	printWarnings(result)
 	fmt.Println()
 	fmt.Println("   Fix these issues with: yo yellow")
 	return fmt.Errorf("validation failed")
 }
 
+func verifyGreen(taskPath string) error {
+	s, err := state.Load()
+	if err != nil {
+		return err
+	}
+	if s.CurrentStage != "green" {
+		return fmt.Errorf("can only verify GREEN LIGHT in GREEN LIGHT. Current stage: %s", s.CurrentStage)
+	}
+
+	t, err := task.Load(taskPath)
+	if err != nil {
+		return err
+	}
+	if len(t.Yellow.Criteria) == 0 {
+		fmt.Println("❌ GREEN LIGHT has no success criteria to verify.")
+		fmt.Println("   Plan them with: yo yellow")
+		return fmt.Errorf("validation failed")
+	}
+
+	if hasCriteriaCommands(t) {
+		fmt.Println("🧪 Running Criteria Commands")
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		if _, err := runCriteriaCommands(t, s.CurrentTaskID, verifyTimeout); err != nil {
+			return err
+		}
+		if err := t.Save(taskPath); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	met := t.CriteriaMet()
+	if met == len(t.Yellow.Criteria) {
+		fmt.Println("✅ GREEN LIGHT is complete!")
+		printCriteria(t)
+		fmt.Println("   Next: yo done  (complete the task)")
+		return nil
+	}
+
+	fmt.Printf("❌ GREEN LIGHT is incomplete: %d/%d criteria met\n", met, len(t.Yellow.Criteria))
+	printCriteria(t)
+	fmt.Println()
+	fmt.Println("   Tick criteria as you meet them with: yo check <n>")
+	if !hasCriteriaCommands(t) {
+		fmt.Println("   Or let yo check one: - [ ] tests pass `cmd: go test ./...`")
+	}
+	return fmt.Errorf("validation failed")
+}
+
+// hasCriteriaCommands reports whether any success criterion has a command
+func hasCriteriaCommands(t *task.Task) bool {
+	for _, c := range t.Yellow.Criteria {
+		if c.Command != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// runCriteriaCommands runs the command behind each success criterion from
+// the project directory, ticking the ones that pass and unticking the ones
+// that fail. A zero timeout uses criteria_timeout from the config. The
+// results are keyed by criterion index; the caller saves the task.
+func runCriteriaCommands(t *task.Task, taskID string, timeout time.Duration) (map[int]verify.Result, error) {
+	projectDir, err := state.GetProjectDir()
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, err
+		}
+		timeout = time.Duration(cfg.CriteriaTimeout) * time.Second
+	}
+
+	results := make(map[int]verify.Result)
+	for i, c := range t.Yellow.Criteria {
+		if c.Command == "" {
+			continue
+		}
+		fmt.Printf("  ▶ %s\n    $ %s\n", c.Text, c.Command)
+
+		r := verify.Run(c.Command, projectDir, timeout)
+		results[i] = r
+		if r.Passed {
+			fmt.Printf("    ✅ %s\n", r.Summary())
+		} else {
+			fmt.Printf("    ❌ %s\n", r.Summary())
+			if r.Output != "" {
+				fmt.Println("    " + strings.ReplaceAll(r.Tail(10), "\n", "\n    "))
+			}
+		}
+
+		if r.Passed != c.Checked {
+			t.CheckCriterion(i, r.Passed, time.Now())
+			activity.LogCriterion(taskID, c.Text, r.Passed)
+		}
+	}
+	return results, nil
+}
+
+// printChosenOption shows which option and estimate 'yo go' will use
+func printChosenOption(t *task.Task) {
+	opt := t.Chosen()
+	if opt == nil {
+		return
+	}
+
+	if opt.Description != "" {
+		fmt.Printf("   Option:   %s - %s\n", opt.Label, opt.Description)
+	} else {
+		fmt.Printf("   Option:   %s\n", opt.Label)
+	}
+	if opt.EstimateHours > 0 {
+		fmt.Printf("   Estimate: %s (used by yo go)\n", timer.FormatHours(opt.EstimateHours))
+	} else {
+		fmt.Println("   Estimate: none (yo go will ask)")
+	}
+}
+
+func printWarnings(result *task.ValidationResult) {
+	for _, w := range result.Warnings {
+		fmt.Printf("   ⚠️  %s: %s\n", w.Field, w.Message)
+	}
+}
+
 func init() {
+	verifyCmd.Flags().DurationVar(&verifyTimeout, "timeout", 0, "Kill each criterion command after this long (default criteria_timeout)")
 	rootCmd.AddCommand(verifyCmd)
 }
@@ -5,8 +5,12 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
+	"github.com/faisalahmedsifat/yo/internal/registry"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/timer"
 	"github.com/faisalahmedsifat/yo/internal/watcher"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
@@ -22,8 +26,10 @@ var watchCmd = &cobra.Command{
 The watcher:
   - Runs as a single daemon for all projects
   - Detects file changes in git repositories
-  - Logs activity to the current active project's .yo/activity.jsonl
-  - Marks changes as "untracked" if outside the current task's repo
+  - Logs each change to the .yo/activity.jsonl of the workspace containing it
+    (workspaces are registered by 'yo init')
+  - Logs changes outside every workspace to the current project as "untracked"
+  - Sends timer milestone notifications (100%, 150%, 200%) once each
 
 Use --bg to run in background.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -36,8 +42,10 @@ Use --bg to run in background.`,
 
 		// Set current project directory
 		if workspace.IsInitialized() {
-			cwd, _ := os.Getwd()
-			watcher.SetCurrentProject(cwd)
+			if projectDir, err := state.GetProjectDir(); err == nil {
+				watcher.SetCurrentProject(projectDir)
+				registry.Register(projectDir)
+			}
 		}
 
 		if watchBackground {
@@ -98,6 +106,9 @@ func startForeground() error {
 		return err
 	}
 
+	monitor := timer.NewMonitor(timer.DefaultPollInterval, monitoredWorkspaces)
+	monitor.Start()
+
 	fmt.Println()
 	fmt.Println("🔍 File watcher started (press Ctrl+C to stop)")
 	fmt.Println()
@@ -109,11 +120,34 @@ func startForeground() error {
 
 	fmt.Println()
 	fmt.Println("👋 Stopping watcher...")
+	monitor.Stop()
 	w.Stop()
 
 	return nil
 }
 
+// monitoredWorkspaces returns the .yo directories whose timers are
+// monitored: every registered workspace, plus the current project
+func monitoredWorkspaces() []string {
+	var dirs []string
+	if r, err := registry.Load(); err == nil {
+		dirs = r.YoDirs()
+	}
+
+	cfg, err := watcher.LoadGlobalConfig()
+	if err != nil || cfg.CurrentDir == "" {
+		return dirs
+	}
+
+	current := filepath.Join(cfg.CurrentDir, ".yo")
+	for _, dir := range dirs {
+		if dir == current {
+			return dirs
+		}
+	}
+	return append(dirs, current)
+}
+
 func startBackground() error {
 	// Get the path to the yo binary
 	executable, err := os.Executable()
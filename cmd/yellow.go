@@ -1,13 +1,12 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/faisalahmedsifat/yo/internal/activity"
 	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -27,10 +26,17 @@ Before executing, you must:
   - Define success criteria
 
 By default, runs in interactive mode with guided prompts.
-Use -e to open the editor directly.`,
+Use -e to open the editor directly, or answer every prompt with flags:
+
+  yo yellow --cause-immediate "..." --cause-underlying "..." --cause-system "..." \
+    --option "Patch the cookie | 2h | quick | fragile" \
+    --option "Rewrite SSO | 2d | clean | slow" \
+    --option "Disable SSO | 30m | instant | users lose SSO" \
+    --choose A --reason "smallest change" \
+    --step "Fix cookie domain" --criterion "SSO users can log in"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if !workspace.IsInitialized() {
-			return fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return workspace.ErrNotInitialized
 		}
 
 		s, err := state.Load()
@@ -38,6 +44,10 @@ Use -e to open the editor directly.`,
 			return err
 		}
 
+		if err := checkBypass(s); err != nil {
+			return err
+		}
+
 		// Check stage
 		if s.CurrentStage == "none" || s.CurrentStage == "" {
 			return fmt.Errorf("complete RED LIGHT first. Run 'yo red'")
@@ -47,14 +57,14 @@ Use -e to open the editor directly.`,
 			return fmt.Errorf("already in GREEN LIGHT. Complete current task first with 'yo done'")
 		}
 
-		taskPath, err := workspace.GetCurrentTaskPath()
+		taskPath, err := workspace.GetCurrentTaskPath(s)
 		if err != nil {
 			return err
 		}
 
 		if yellowEdit {
 			// Open editor
-			fmt.Println("🟡 Opening current_task.md for YELLOW LIGHT...")
+			fmt.Printf("🟡 Opening tasks/%s.md for YELLOW LIGHT...\n", s.CurrentTaskID)
 			fmt.Println("   Fill in the analysis and planning section.")
 			fmt.Println()
 
@@ -62,16 +72,10 @@ Use -e to open the editor directly.`,
 				return fmt.Errorf("failed to open editor: %w", err)
 			}
 
-			// Update state
-			oldStage := s.CurrentStage
-			s.SetStage("yellow")
-			if err := s.Save(); err != nil {
+			if err := startYellow(s); err != nil {
 				return err
 			}
 
-			// Log stage change
-			activity.LogStageChange(oldStage, "yellow", s.CurrentTaskID)
-
 			fmt.Println()
 			fmt.Println("✅ YELLOW LIGHT started!")
 			fmt.Println("   Next: yo go  (start execution)")
@@ -89,198 +93,116 @@ func runYellowInteractive(taskPath string, s *state.State) error {
 	fmt.Println()
 
 	// Show RED LIGHT context
-	content, err := os.ReadFile(taskPath)
-	if err == nil {
-		text := string(content)
-
-		// Extract problem from task file
-		if idx := strings.Index(text, "### What's the Problem?"); idx != -1 {
-			endIdx := strings.Index(text[idx:], "### Impact")
-			if endIdx != -1 {
-				problem := strings.TrimSpace(text[idx+len("### What's the Problem?") : idx+endIdx])
-				problem = strings.TrimPrefix(problem, "<!-- Describe the problem clearly -->")
-				problem = strings.TrimSpace(problem)
-				if problem != "" {
-					fmt.Println("📋 From RED LIGHT:")
-					fmt.Printf("   Problem: %s\n", problem)
-					fmt.Printf("   Task ID: %s\n", s.CurrentTaskID)
-					fmt.Println()
-					fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-					fmt.Println()
-				}
-			}
-		}
+	if t, err := task.Load(taskPath); err == nil && t.Red.Problem != "" {
+		fmt.Println("📋 From RED LIGHT:")
+		fmt.Printf("   Problem: %s\n", t.Red.Problem)
+		fmt.Printf("   Task ID: %s\n", s.CurrentTaskID)
+		fmt.Println()
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Println()
 	}
 
-	reader := bufio.NewReader(os.Stdin)
-
 	// Root cause analysis
 	fmt.Println("Root Cause Analysis (dig 3 levels deep)")
 	fmt.Println()
 
-	fmt.Print("Immediate cause (what directly causes this):\n> ")
-	immediateCause, _ := reader.ReadString('\n')
-	immediateCause = strings.TrimSpace(immediateCause)
-
-	fmt.Print("\nUnderlying cause (why does the immediate cause exist):\n> ")
-	underlyingCause, _ := reader.ReadString('\n')
-	underlyingCause = strings.TrimSpace(underlyingCause)
-
-	fmt.Print("\nSystem cause (what systemic issue allows this):\n> ")
-	systemCause, _ := reader.ReadString('\n')
-	systemCause = strings.TrimSpace(systemCause)
+	immediateCause, err := ask.Ask("cause-immediate", "Immediate cause (what directly causes this):\n> ")
+	if err != nil {
+		return err
+	}
+	underlyingCause, err := ask.Ask("cause-underlying", "\nUnderlying cause (why does the immediate cause exist):\n> ")
+	if err != nil {
+		return err
+	}
+	systemCause, err := ask.Ask("cause-system", "\nSystem cause (what systemic issue allows this):\n> ")
+	if err != nil {
+		return err
+	}
 
 	// Solution options (minimum 3)
 	fmt.Println()
 	fmt.Println("Solution Options (minimum 3)")
 	fmt.Println()
 
-	type option struct {
-		desc string
-		time string
-		pros string
-		cons string
-	}
-
-	options := make([]option, 3)
 	labels := []string{"A", "B", "C"}
-
-	for i := 0; i < 3; i++ {
-		fmt.Printf("Option %s:\n", labels[i])
-
-		fmt.Print("  Description: ")
-		options[i].desc, _ = reader.ReadString('\n')
-		options[i].desc = strings.TrimSpace(options[i].desc)
-
-		fmt.Print("  Time estimate (e.g., 2h, 4h): ")
-		options[i].time, _ = reader.ReadString('\n')
-		options[i].time = strings.TrimSpace(options[i].time)
-
-		fmt.Print("  Pros: ")
-		options[i].pros, _ = reader.ReadString('\n')
-		options[i].pros = strings.TrimSpace(options[i].pros)
-
-		fmt.Print("  Cons: ")
-		options[i].cons, _ = reader.ReadString('\n')
-		options[i].cons = strings.TrimSpace(options[i].cons)
-
-		fmt.Println()
+	options, err := askOptions(labels)
+	if err != nil {
+		return err
 	}
 
 	// Decision
-	fmt.Print("Which option do you choose? (A/B/C): ")
-	chosenStr, _ := reader.ReadString('\n')
-	chosenStr = strings.TrimSpace(strings.ToUpper(chosenStr))
-	chosenIdx := 0
-	switch chosenStr {
-	case "B":
-		chosenIdx = 1
-	case "C":
-		chosenIdx = 2
+	chosenStr, err := ask.Ask("choose", "Which option do you choose? (A/B/C): ")
+	if err != nil {
+		return err
+	}
+	chosenStr = strings.ToUpper(chosenStr)
+	chosenIdx := -1
+	for i, label := range labels {
+		if chosenStr == label {
+			chosenIdx = i
+		}
+	}
+	if chosenIdx < 0 {
+		return fmt.Errorf("choose option A, B or C")
 	}
 
-	fmt.Print("\nWhy this option? ")
-	reason, _ := reader.ReadString('\n')
-	reason = strings.TrimSpace(reason)
+	reason, err := ask.Ask("reason", "\nWhy this option? ")
+	if err != nil {
+		return err
+	}
 
 	// Implementation steps
-	fmt.Println()
-	fmt.Println("Implementation Steps (enter each step, empty line to finish):")
-	var steps []string
-	for i := 1; ; i++ {
-		fmt.Printf("  %d. ", i)
-		step, _ := reader.ReadString('\n')
-		step = strings.TrimSpace(step)
-		if step == "" {
-			break
-		}
-		steps = append(steps, step)
+	steps, err := ask.AskList("step", "\nImplementation Steps (enter each step, empty line to finish):\n", numberedItem)
+	if err != nil {
+		return err
 	}
 
 	// Success criteria
-	fmt.Println()
-	fmt.Println("Success Criteria (enter each criterion, empty line to finish):")
-	var criteria []string
-	for i := 1; ; i++ {
-		fmt.Printf("  %d. ", i)
-		criterion, _ := reader.ReadString('\n')
-		criterion = strings.TrimSpace(criterion)
-		if criterion == "" {
-			break
-		}
-		criteria = append(criteria, criterion)
+	criteria, err := ask.AskList("criterion", "\nSuccess Criteria (enter each criterion, empty line to finish;\nend one with `cmd: go test ./...` to let yo check it):\n", numberedItem)
+	if err != nil {
+		return err
 	}
 
 	if len(criteria) < 1 {
 		return fmt.Errorf("need at least 1 success criterion")
 	}
 
-	// Read and update template
-	content, err = os.ReadFile(taskPath)
+	// Update the task file with answers
+	t, err := task.Load(taskPath)
 	if err != nil {
 		return err
 	}
-
-	text := string(content)
-
-	// Replace root cause placeholders
-	text = strings.Replace(text, "<!-- What directly causes this? -->", immediateCause, 1)
-	text = strings.Replace(text, "<!-- Why does the immediate cause exist? -->", underlyingCause, 1)
-	text = strings.Replace(text, "<!-- What systemic issue allows this? -->", systemCause, 1)
-
-	// Update options
+	t.Yellow.ImmediateCause = immediateCause
+	t.Yellow.UnderlyingCause = underlyingCause
+	t.Yellow.SystemCause = systemCause
+	t.Yellow.Options = nil
 	for i, opt := range options {
-		label := labels[i]
-		optSection := fmt.Sprintf(`#### Option %s:
-- Description: %s
-- Time estimate: %s
-- Pros: %s
-- Cons: %s`, label, opt.desc, opt.time, opt.pros, opt.cons)
-
-		placeholder := fmt.Sprintf(`#### Option %s:
-- Description:
-- Time estimate:
-- Pros:
-- Cons:`, label)
-
-		text = strings.Replace(text, placeholder, optSection, 1)
-	}
-
-	// Update decision
-	text = strings.Replace(text, "**Chosen option:** \n**Reason:**",
-		fmt.Sprintf("**Chosen option:** %s\n**Reason:** %s", chosenStr, reason), 1)
-
-	// Update implementation steps
-	var stepLines strings.Builder
-	for i, step := range steps {
-		stepLines.WriteString(fmt.Sprintf("%d. %s\n", i+1, step))
+		hours, _ := task.ParseEstimate(opt.time)
+		t.Yellow.Options = append(t.Yellow.Options, task.Option{
+			Label:         labels[i],
+			Description:   opt.desc,
+			Estimate:      opt.time,
+			EstimateHours: hours,
+			Pros:          opt.pros,
+			Cons:          opt.cons,
+		})
 	}
-	text = strings.Replace(text, "### Implementation Steps\n1. \n2. \n3. ",
-		"### Implementation Steps\n"+stepLines.String(), 1)
-
-	// Update success criteria
-	var criteriaLines strings.Builder
+	t.Yellow.ChosenOption = chosenStr
+	t.Yellow.Reason = reason
+	t.Yellow.Steps = steps
+	t.Yellow.Criteria = nil
 	for _, c := range criteria {
-		criteriaLines.WriteString(fmt.Sprintf("- [ ] %s\n", c))
+		t.Yellow.Criteria = append(t.Yellow.Criteria, task.ParseCriterion(c))
 	}
-	text = strings.Replace(text, "### Success Criteria\n- [ ] \n- [ ] \n- [ ] ",
-		"### Success Criteria\n"+criteriaLines.String(), 1)
 
-	// Write updated content
-	if err := os.WriteFile(taskPath, []byte(text), 0644); err != nil {
+	if err := t.Save(taskPath); err != nil {
 		return err
 	}
 
-	// Update state
-	oldStage := s.CurrentStage
-	s.SetStage("yellow")
-	if err := s.Save(); err != nil {
+	if err := startYellow(s); err != nil {
 		return err
 	}
 
-	// Log stage change
-	activity.LogStageChange(oldStage, "yellow", s.CurrentTaskID)
-
 	fmt.Println()
 	fmt.Println("✅ YELLOW LIGHT complete!")
 	fmt.Printf("   Time estimate: %s\n", options[chosenIdx].time)
@@ -288,7 +210,118 @@ func runYellowInteractive(taskPath string, s *state.State) error {
 	return nil
 }
 
+// startYellow moves the task s was loaded with to YELLOW, unless another
+// command changed the active task meanwhile
+func startYellow(s *state.State) error {
+	id, oldStage := s.CurrentTaskID, s.CurrentStage
+	if _, err := state.Update(func(s *state.State) error {
+		if err := checkSameTask(s, id, oldStage); err != nil {
+			return err
+		}
+		s.SetStage("yellow")
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	activity.LogStageChange(oldStage, "yellow", id)
+	return nil
+}
+
+// option is one solution option in YELLOW LIGHT
+type option struct {
+	desc string
+	time string
+	pros string
+	cons string
+}
+
+// askOptions reads one solution option per label: from --option flags
+// ("description | estimate | pros | cons"), from the answers file (those
+// strings, or maps with description, estimate, pros and cons), or by asking
+// for each field
+func askOptions(labels []string) ([]option, error) {
+	var options []option
+	if values, ok := ask.Flags.Lookup("option"); ok {
+		for _, v := range values {
+			options = append(options, parseOption(v))
+		}
+	} else if raw, ok := ask.Value("option"); ok {
+		list, ok := raw.([]interface{})
+		if !ok {
+			list = []interface{}{raw}
+		}
+		for _, item := range list {
+			switch v := item.(type) {
+			case string:
+				options = append(options, parseOption(v))
+			case map[string]interface{}:
+				field := func(name string) string {
+					s, _ := v[name].(string)
+					return strings.TrimSpace(s)
+				}
+				options = append(options, option{desc: field("description"), time: field("estimate"), pros: field("pros"), cons: field("cons")})
+			default:
+				return nil, fmt.Errorf("invalid option in answers: %v", item)
+			}
+		}
+	} else {
+		for _, label := range labels {
+			fmt.Printf("Option %s:\n", label)
+			var opt option
+			for _, f := range []struct {
+				prompt string
+				value  *string
+			}{
+				{"  Description: ", &opt.desc},
+				{"  Time estimate (e.g., 2h, 4h): ", &opt.time},
+				{"  Pros: ", &opt.pros},
+				{"  Cons: ", &opt.cons},
+			} {
+				answer, err := ask.Ask("option", f.prompt)
+				if err != nil {
+					return nil, err
+				}
+				*f.value = answer
+			}
+			options = append(options, opt)
+			fmt.Println()
+		}
+	}
+
+	if len(options) != len(labels) {
+		return nil, fmt.Errorf("need exactly %d solution options, got %d", len(labels), len(options))
+	}
+	return options, nil
+}
+
+// parseOption reads "description | estimate | pros | cons"
+func parseOption(s string) option {
+	fields := strings.Split(s, "|")
+	for len(fields) < 4 {
+		fields = append(fields, "")
+	}
+	return option{
+		desc: strings.TrimSpace(fields[0]),
+		time: strings.TrimSpace(fields[1]),
+		pros: strings.TrimSpace(fields[2]),
+		cons: strings.TrimSpace(fields[3]),
+	}
+}
+
+func numberedItem(n int) string {
+	return fmt.Sprintf("  %d. ", n)
+}
+
 func init() {
 	yellowCmd.Flags().BoolVarP(&yellowEdit, "edit", "e", false, "Open in editor instead of interactive mode")
+	yellowCmd.Flags().String("cause-immediate", "", "Immediate cause: what directly causes this")
+	yellowCmd.Flags().String("cause-underlying", "", "Underlying cause: why the immediate cause exists")
+	yellowCmd.Flags().String("cause-system", "", "System cause: what systemic issue allows this")
+	yellowCmd.Flags().StringArray("option", nil, "Solution option \"description | estimate | pros | cons\" (give 3)")
+	yellowCmd.Flags().String("choose", "", "Chosen option: A, B or C")
+	yellowCmd.Flags().String("reason", "", "Why this option")
+	yellowCmd.Flags().StringArray("step", nil, "Implementation step (repeatable)")
+	yellowCmd.Flags().StringArray("criterion", nil, "Success criterion (repeatable)")
 	rootCmd.AddCommand(yellowCmd)
 }
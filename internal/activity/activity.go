@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
 	"github.com/faisalahmedsifat/yo/internal/state"
 )
 
@@ -15,12 +17,23 @@ import (
 type EntryType string
 
 const (
-	TypeFileChange      EntryType = "file_change"
-	TypeStageChange     EntryType = "stage_change"
-	TypeSessionEnd      EntryType = "session_end"
-	TypeTimerMilestone  EntryType = "timer_milestone"
-	TypeEmergencyBypass EntryType = "emergency_bypass"
-	TypeTaskComplete    EntryType = "task_complete"
+	TypeFileChange       EntryType = "file_change"
+	TypeFileRename       EntryType = "file_rename"
+	TypeFileDelete       EntryType = "file_delete"
+	TypeStageChange      EntryType = "stage_change"
+	TypeSessionEnd       EntryType = "session_end"
+	TypeTimerMilestone   EntryType = "timer_milestone"
+	TypeEmergencyBypass  EntryType = "emergency_bypass"
+	TypeTaskComplete     EntryType = "task_complete"
+	TypeTimerPause       EntryType = "timer_pause"
+	TypeTimerResume      EntryType = "timer_resume"
+	TypeTimerExtension   EntryType = "timer_extension"
+	TypeBypassExpired    EntryType = "bypass_expired"
+	TypeBypassEnd        EntryType = "bypass_end"
+	TypeTaskPark         EntryType = "task_park"
+	TypeTaskSwitch       EntryType = "task_switch"
+	TypeCriterionCheck   EntryType = "criterion_check"
+	TypeCriterionUncheck EntryType = "criterion_uncheck"
 )
 
 // Entry represents a single activity log entry
@@ -28,31 +41,47 @@ type Entry struct {
 	Timestamp time.Time `json:"ts"`
 	Type      EntryType `json:"type"`
 
-	// For file_change
+	// For file_change, file_rename and file_delete
 	Repo      string `json:"repo,omitempty"`
 	File      string `json:"file,omitempty"`
 	Task      string `json:"task,omitempty"`
 	Stage     string `json:"stage,omitempty"`
 	Untracked bool   `json:"untracked,omitempty"`
 
-	// For stage_change
+	// For stage_change (and file_rename: the old file)
 	From string `json:"from,omitempty"`
 	To   string `json:"to,omitempty"`
 
-	// For session_end
+	// For session_end (and timer_resume: minutes spent paused)
 	DurationMinutes int     `json:"duration_minutes,omitempty"`
 	PrimaryRepo     string  `json:"primary_repo,omitempty"`
 	FocusPercent    float64 `json:"focus_percent,omitempty"`
 
-	// For timer_milestone
+	// For timer_milestone (and timer_pause: active hours so far)
 	Milestone      string  `json:"milestone,omitempty"`
 	ActualHours    float64 `json:"actual_hours,omitempty"`
 	EstimatedHours float64 `json:"estimated_hours,omitempty"`
 
-	// For emergency_bypass
+	// For emergency_bypass, timer_pause and timer_extension
 	Reason     string `json:"reason,omitempty"`
 	CountToday int    `json:"count_today,omitempty"`
 	CountWeek  int    `json:"count_week,omitempty"`
+
+	// For timer_extension
+	Hours float64 `json:"hours,omitempty"`
+
+	// For criterion_check and criterion_uncheck
+	Criterion string `json:"criterion,omitempty"`
+}
+
+// IsFileEvent reports whether the entry is a file changing, being renamed
+// or being deleted, as seen by the watcher
+func (e Entry) IsFileEvent() bool {
+	switch e.Type {
+	case TypeFileChange, TypeFileRename, TypeFileDelete:
+		return true
+	}
+	return false
 }
 
 // getActivityPath returns the path to activity.jsonl
@@ -66,10 +95,16 @@ func getActivityPath() (string, error) {
 
 // Append appends an activity entry to the log
 func Append(entry Entry) error {
-	activityPath, err := getActivityPath()
+	yoDir, err := state.GetYoDir()
 	if err != nil {
 		return err
 	}
+	return AppendTo(yoDir, entry)
+}
+
+// AppendTo appends an activity entry to the log in the given .yo directory
+func AppendTo(yoDir string, entry Entry) error {
+	activityPath := filepath.Join(yoDir, "activity.jsonl")
 
 	entry.Timestamp = time.Now()
 
@@ -78,13 +113,8 @@ func Append(entry Entry) error {
 		return fmt.Errorf("failed to marshal entry: %w", err)
 	}
 
-	f, err := os.OpenFile(activityPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open activity log: %w", err)
-	}
-	defer f.Close()
-
-	if _, err := f.WriteString(string(data) + "\n"); err != nil {
+	// The watcher daemon appends concurrently with the CLI
+	if err := fileutil.AppendLine(activityPath, data); err != nil {
 		return fmt.Errorf("failed to write entry: %w", err)
 	}
 
@@ -122,15 +152,78 @@ func LogSessionEnd(durationMinutes int, primaryRepo string, focusPercent float64
 }
 
 // LogTimerMilestone logs a timer milestone (100%, 150%, 200%)
-func LogTimerMilestone(milestone string, actualHours, estimatedHours float64) error {
+func LogTimerMilestone(taskID, milestone string, actualHours, estimatedHours float64) error {
 	return Append(Entry{
 		Type:           TypeTimerMilestone,
+		Task:           taskID,
 		Milestone:      milestone,
 		ActualHours:    actualHours,
 		EstimatedHours: estimatedHours,
 	})
 }
 
+// LogTimerPause logs the GREEN LIGHT timer being paused
+func LogTimerPause(taskID string, activeHours float64, reason string) error {
+	return Append(Entry{
+		Type:        TypeTimerPause,
+		Task:        taskID,
+		ActualHours: activeHours,
+		Reason:      reason,
+	})
+}
+
+// LogTimerResume logs the GREEN LIGHT timer being resumed after a pause
+func LogTimerResume(taskID string, pausedMinutes int) error {
+	return Append(Entry{
+		Type:            TypeTimerResume,
+		Task:            taskID,
+		DurationMinutes: pausedMinutes,
+	})
+}
+
+// LogTimerExtension logs hours added to the GREEN LIGHT timer
+func LogTimerExtension(taskID string, hours float64, reason string) error {
+	return Append(Entry{
+		Type:   TypeTimerExtension,
+		Task:   taskID,
+		Hours:  hours,
+		Reason: reason,
+	})
+}
+
+// LogTaskPark logs a task being set aside in the given stage
+func LogTaskPark(taskID, stage string) error {
+	return Append(Entry{
+		Type:  TypeTaskPark,
+		Task:  taskID,
+		Stage: stage,
+	})
+}
+
+// LogTaskSwitch logs switching the active task (from is empty if there was none)
+func LogTaskSwitch(from, to, stage string) error {
+	return Append(Entry{
+		Type:  TypeTaskSwitch,
+		From:  from,
+		To:    to,
+		Task:  to,
+		Stage: stage,
+	})
+}
+
+// LogCriterion logs a success criterion being ticked or unticked
+func LogCriterion(taskID, criterion string, checked bool) error {
+	entryType := TypeCriterionCheck
+	if !checked {
+		entryType = TypeCriterionUncheck
+	}
+	return Append(Entry{
+		Type:      entryType,
+		Task:      taskID,
+		Criterion: criterion,
+	})
+}
+
 // LogEmergencyBypass logs an emergency bypass
 func LogEmergencyBypass(reason string, countToday, countWeek int) error {
 	return Append(Entry{
@@ -141,6 +234,15 @@ func LogEmergencyBypass(reason string, countToday, countWeek int) error {
 	})
 }
 
+// LogBypassEnd logs the end of an emergency bypass with its post-incident note
+func LogBypassEnd(note string, durationMinutes int) error {
+	return Append(Entry{
+		Type:            TypeBypassEnd,
+		Reason:          note,
+		DurationMinutes: durationMinutes,
+	})
+}
+
 // Query returns entries matching the given time range
 func Query(start, end time.Time) ([]Entry, error) {
 	activityPath, err := getActivityPath()
@@ -204,3 +306,74 @@ func QueryThisWeek() ([]Entry, error) {
 	end := now.Add(24 * time.Hour)
 	return Query(start, end)
 }
+
+// Malformed returns the 1-based line numbers of entries in the log that
+// aren't valid JSON. Query skips them silently.
+func Malformed(yoDir string) ([]int, error) {
+	data, err := os.ReadFile(filepath.Join(yoDir, "activity.jsonl"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read activity log: %w", err)
+	}
+
+	var bad []int
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var entry Entry
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			bad = append(bad, i+1)
+		}
+	}
+	return bad, nil
+}
+
+// RemoveMalformed moves malformed entries out of the log into
+// activity.malformed.jsonl, so nothing is lost. Returns how many moved.
+func RemoveMalformed(yoDir string) (int, error) {
+	activityPath := filepath.Join(yoDir, "activity.jsonl")
+
+	unlock, err := fileutil.Lock(activityPath)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(activityPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read activity log: %w", err)
+	}
+
+	var good, bad []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var entry Entry
+		if json.Unmarshal([]byte(line), &entry) != nil {
+			bad = append(bad, line)
+		} else {
+			good = append(good, line)
+		}
+	}
+	if len(bad) == 0 {
+		return 0, nil
+	}
+
+	malformedPath := filepath.Join(yoDir, "activity.malformed.jsonl")
+	if err := fileutil.AppendLine(malformedPath, []byte(strings.Join(bad, "\n"))); err != nil {
+		return 0, fmt.Errorf("failed to save malformed entries: %w", err)
+	}
+
+	content := ""
+	if len(good) > 0 {
+		content = strings.Join(good, "\n") + "\n"
+	}
+	if err := fileutil.WriteFile(activityPath, []byte(content), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write activity log: %w", err)
+	}
+	return len(bad), nil
+}
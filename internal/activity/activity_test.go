@@ -1,8 +1,10 @@
 package activity
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -135,6 +137,35 @@ func TestLogTaskComplete(t *testing.T) {
 	}
 }
 
+func TestLogTimerPauseResume(t *testing.T) {
+	_, cleanup := setupTestWorkspace(t)
+	defer cleanup()
+
+	if err := LogTimerPause("test_task", 1.5, "lunch"); err != nil {
+		t.Fatalf("Failed to log pause: %v", err)
+	}
+	if err := LogTimerResume("test_task", 45); err != nil {
+		t.Fatalf("Failed to log resume: %v", err)
+	}
+
+	entries, err := QueryToday()
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].Type != TypeTimerPause || entries[0].Reason != "lunch" || entries[0].ActualHours != 1.5 {
+		t.Errorf("Unexpected pause entry: %+v", entries[0])
+	}
+
+	if entries[1].Type != TypeTimerResume || entries[1].DurationMinutes != 45 {
+		t.Errorf("Unexpected resume entry: %+v", entries[1])
+	}
+}
+
 func TestLogEmergencyBypass(t *testing.T) {
 	_, cleanup := setupTestWorkspace(t)
 	defer cleanup()
@@ -200,3 +231,39 @@ func TestQueryEmptyLog(t *testing.T) {
 		t.Errorf("Expected 0 entries, got %d", len(entries))
 	}
 }
+
+func TestAppendToConcurrent(t *testing.T) {
+	tmpDir, cleanup := setupTestWorkspace(t)
+	defer cleanup()
+
+	yoDir := filepath.Join(tmpDir, ".yo")
+
+	// The watcher daemon and CLI commands append at the same time
+	const workers, rounds = 10, 20
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				err := AppendTo(yoDir, Entry{
+					Type: TypeFileChange,
+					Repo: "/home/user/Dev/project",
+					File: fmt.Sprintf("worker%d/file%d.go", w, r),
+				})
+				if err != nil {
+					t.Errorf("AppendTo failed: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	entries, err := Query(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(entries) != workers*rounds {
+		t.Errorf("Expected %d entries, got %d", workers*rounds, len(entries))
+	}
+}
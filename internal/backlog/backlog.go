@@ -5,7 +5,10 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
+	"github.com/faisalahmedsifat/yo/internal/task"
 	"github.com/faisalahmedsifat/yo/internal/workspace"
 )
 
@@ -19,18 +22,48 @@ const (
 
 // Item represents a backlog item
 type Item struct {
-	Text     string
+	ID       string // stable short ID, stored as <!-- id:xxxxxx -->
+	Text     string // as written, including inline metadata
 	Priority string
 	Checked  bool
+	DoneDate string // YYYY-MM-DD, stored as done:YYYY-MM-DD
 	Line     int
+
+	// Inline metadata parsed from Text
+	Tags          []string  // #frontend
+	Estimate      string    // ~2h
+	EstimateHours float64   //
+	Due           time.Time // due:2026-11-01 (zero if none)
+	Assignee      string    // @alice
 }
 
 // Backlog holds all backlog items
 type Backlog struct {
 	Items map[string][]Item
 	Path  string
+
+	lines []string
+}
+
+// sectionMarkers are the headings Add and Move create for missing sections
+var sectionMarkers = map[string]string{
+	P0: "## P0 - Launch Blockers",
+	P1: "## P1 - Paying User Blockers",
+	P2: "## P2 - Nice to Have",
+	P3: "## P3 - Future Improvements",
 }
 
+var (
+	itemRe = regexp.MustCompile(`^\s*-\s*\[([ xX])\]\s*(.+)$`)
+	idRe   = regexp.MustCompile(`\s*<!--\s*id:([0-9a-zA-Z]+)\s*-->`)
+	doneRe = regexp.MustCompile(`\s*\bdone:(\d{4}-\d{2}-\d{2})\b`)
+
+	tagRe      = regexp.MustCompile(`(?:^|\s)#([A-Za-z][\w-]*)`)
+	estimateRe = regexp.MustCompile(`(?:^|\s)~(\d+(?:\.\d+)?\s*[a-zA-Z]*)`)
+	dueRe      = regexp.MustCompile(`(?:^|\s)due:(\d{4}-\d{2}-\d{2})\b`)
+	assigneeRe = regexp.MustCompile(`(?:^|\s)@([\w.-]+)`)
+)
+
 // Load loads the backlog from disk
 func Load() (*Backlog, error) {
 	path, err := workspace.GetBacklogPath()
@@ -51,47 +84,27 @@ func Parse(content, path string) *Backlog {
 	b := &Backlog{
 		Items: make(map[string][]Item),
 		Path:  path,
+		lines: strings.Split(content, "\n"),
 	}
 	b.Items[P0] = []Item{}
 	b.Items[P1] = []Item{}
 	b.Items[P2] = []Item{}
 	b.Items[P3] = []Item{}
 
-	lines := strings.Split(content, "\n")
 	currentPriority := ""
-	itemRe := regexp.MustCompile(`^\s*-\s*\[([ xX])\]\s*(.+)$`)
-
-	for lineNum, line := range lines {
-		trimmed := strings.TrimSpace(line)
 
+	for lineNum, line := range b.lines {
 		// Detect priority section
-		if strings.Contains(trimmed, "P0 -") || strings.Contains(trimmed, "## P0") {
-			currentPriority = P0
-			continue
-		}
-		if strings.Contains(trimmed, "P1 -") || strings.Contains(trimmed, "## P1") {
-			currentPriority = P1
-			continue
-		}
-		if strings.Contains(trimmed, "P2 -") || strings.Contains(trimmed, "## P2") {
-			currentPriority = P2
-			continue
-		}
-		if strings.Contains(trimmed, "P3 -") || strings.Contains(trimmed, "## P3") {
-			currentPriority = P3
+		if p := sectionPriority(line); p != "" {
+			currentPriority = p
 			continue
 		}
 
 		// Parse items
 		if currentPriority != "" {
-			matches := itemRe.FindStringSubmatch(line)
-			if len(matches) > 2 {
-				item := Item{
-					Text:     strings.TrimSpace(matches[2]),
-					Priority: currentPriority,
-					Checked:  matches[1] == "x" || matches[1] == "X",
-					Line:     lineNum,
-				}
+			if item, ok := parseItem(line); ok {
+				item.Priority = currentPriority
+				item.Line = lineNum
 				b.Items[currentPriority] = append(b.Items[currentPriority], item)
 			}
 		}
@@ -100,38 +113,430 @@ func Parse(content, path string) *Backlog {
 	return b
 }
 
-// Add adds an item to the backlog
-func (b *Backlog) Add(text, priority string) error {
-	content, err := os.ReadFile(b.Path)
+// sectionPriority returns the priority a section heading starts, or ""
+func sectionPriority(line string) string {
+	if itemRe.MatchString(line) {
+		return ""
+	}
+	trimmed := strings.TrimSpace(line)
+	for _, p := range []string{P0, P1, P2, P3} {
+		if strings.Contains(trimmed, p+" -") || strings.Contains(trimmed, "## "+p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// ParsePriority accepts "P0", "p0" or "0" and returns the priority level
+func ParsePriority(s string) (string, error) {
+	p := strings.ToUpper(strings.TrimSpace(s))
+	if !strings.HasPrefix(p, "P") {
+		p = "P" + p
+	}
+	switch p {
+	case P0, P1, P2, P3:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid priority %q (use P0-P3)", s)
+}
+
+// parseItem parses a "- [ ] text done:date <!-- id:xxx -->" line
+func parseItem(line string) (Item, bool) {
+	matches := itemRe.FindStringSubmatch(line)
+	if matches == nil {
+		return Item{}, false
+	}
+
+	item := Item{Checked: matches[1] == "x" || matches[1] == "X"}
+	text := matches[2]
+	if m := idRe.FindStringSubmatch(text); m != nil {
+		item.ID = m[1]
+		text = idRe.ReplaceAllString(text, "")
+	}
+	if m := doneRe.FindStringSubmatch(text); m != nil {
+		item.DoneDate = m[1]
+		text = doneRe.ReplaceAllString(text, "")
+	}
+	item.Text = strings.TrimSpace(text)
+	item.parseMetadata()
+	return item, true
+}
+
+// parseMetadata fills the tag, estimate, due date and assignee fields
+func (item *Item) parseMetadata() {
+	item.Tags = nil
+	for _, m := range tagRe.FindAllStringSubmatch(item.Text, -1) {
+		item.Tags = append(item.Tags, strings.ToLower(m[1]))
+	}
+
+	item.Estimate, item.EstimateHours = "", 0
+	if m := estimateRe.FindStringSubmatch(item.Text); m != nil {
+		if hours, err := task.ParseEstimate(m[1]); err == nil {
+			item.Estimate = strings.TrimSpace(m[1])
+			item.EstimateHours = hours
+		}
+	}
+
+	item.Due = time.Time{}
+	if m := dueRe.FindStringSubmatch(item.Text); m != nil {
+		if due, err := time.ParseInLocation("2006-01-02", m[1], time.Local); err == nil {
+			item.Due = due
+		}
+	}
+
+	item.Assignee = ""
+	if m := assigneeRe.FindStringSubmatch(item.Text); m != nil {
+		item.Assignee = m[1]
+	}
+}
+
+// Title returns the text without inline metadata
+func (item Item) Title() string {
+	text := item.Text
+	for _, re := range []*regexp.Regexp{tagRe, estimateRe, dueRe, assigneeRe} {
+		text = re.ReplaceAllString(text, "")
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// HasTag reports whether the item carries the tag (with or without '#')
+func (item Item) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	for _, t := range item.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Overdue reports whether an open item's due date is before today
+func (item Item) Overdue(now time.Time) bool {
+	if item.Checked || item.Due.IsZero() {
+		return false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return item.Due.Before(today)
+}
+
+// formatItem renders an item as a markdown checkbox line
+func formatItem(item Item) string {
+	checkbox := "[ ]"
+	if item.Checked {
+		checkbox = "[x]"
+	}
+	line := fmt.Sprintf("- %s %s", checkbox, item.Text)
+	if item.DoneDate != "" {
+		line += " done:" + item.DoneDate
+	}
+	if item.ID != "" {
+		line += fmt.Sprintf(" <!-- id:%s -->", item.ID)
+	}
+	return line
+}
+
+// Add adds an item to the backlog and returns its new ID
+func (b *Backlog) Add(text, priority string) (string, error) {
+	var id string
+	err := b.update(func() error {
+		markdown := strings.Join(b.lines, "\n")
+		id = b.newID()
+		item := formatItem(Item{ID: id, Text: text}) + "\n"
+
+		marker := sectionMarkers[priority]
+		if idx := strings.Index(markdown, marker); idx != -1 {
+			endOfLine := idx + len(marker)
+			for endOfLine < len(markdown) && markdown[endOfLine] != '\n' {
+				endOfLine++
+			}
+			if endOfLine < len(markdown) {
+				endOfLine++
+			}
+			markdown = markdown[:endOfLine] + item + markdown[endOfLine:]
+		} else {
+			markdown += fmt.Sprintf("\n%s\n%s", marker, item)
+		}
+
+		b.lines = strings.Split(markdown, "\n")
+		return nil
+	})
 	if err != nil {
-		return err
+		return "", err
 	}
+	return id, nil
+}
 
-	markdown := string(content)
-	item := fmt.Sprintf("- [ ] %s\n", text)
+// Find returns the item with the given ID
+func (b *Backlog) Find(id string) (Item, bool) {
+	if id == "" {
+		return Item{}, false
+	}
+	for _, p := range []string{P0, P1, P2, P3} {
+		for _, item := range b.Items[p] {
+			if item.ID == id {
+				return item, true
+			}
+		}
+	}
+	return Item{}, false
+}
 
-	sectionMarkers := map[string]string{
-		P0: "## P0 - Launch Blockers",
-		P1: "## P1 - Paying User Blockers",
-		P2: "## P2 - Nice to Have",
-		P3: "## P3 - Future Improvements",
+// EnsureID gives the item on the given line a stable ID if it has none,
+// writing it back to backlog.md. Returns the item's ID.
+func (b *Backlog) EnsureID(line int) (string, error) {
+	item, ok := b.itemAt(line)
+	if !ok {
+		return "", fmt.Errorf("no backlog item on line %d", line+1)
+	}
+	if item.ID != "" {
+		return item.ID, nil
 	}
 
-	marker := sectionMarkers[priority]
-	if idx := strings.Index(markdown, marker); idx != -1 {
-		endOfLine := idx + len(marker)
-		for endOfLine < len(markdown) && markdown[endOfLine] != '\n' {
-			endOfLine++
+	var id string
+	err := b.rewrite(item, func(current *Item) {
+		if current.ID == "" {
+			current.ID = b.newID()
 		}
-		if endOfLine < len(markdown) {
-			endOfLine++
+		id = current.ID
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Complete ticks the item with the given ID and records the completion date
+func (b *Backlog) Complete(id string, date time.Time) error {
+	item, ok := b.Find(id)
+	if !ok {
+		return fmt.Errorf("no backlog item with id %s", id)
+	}
+	return b.Check(item, date)
+}
+
+// Check ticks the item and records the completion date
+func (b *Backlog) Check(item Item, date time.Time) error {
+	return b.rewrite(item, func(current *Item) {
+		current.Checked = true
+		current.DoneDate = date.Format("2006-01-02")
+	})
+}
+
+// Edit replaces the item's text, keeping its ID, state and position
+func (b *Backlog) Edit(item Item, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("text cannot be empty")
+	}
+	return b.rewrite(item, func(current *Item) {
+		current.Text = text
+	})
+}
+
+// Remove deletes the item's line
+func (b *Backlog) Remove(item Item) error {
+	return b.update(func() error {
+		current, err := b.locate(item)
+		if err != nil {
+			return err
+		}
+		b.lines = append(b.lines[:current.Line], b.lines[current.Line+1:]...)
+		return nil
+	})
+}
+
+// Move moves the item to the top of another priority section
+func (b *Backlog) Move(item Item, priority string) error {
+	if item.Priority == priority {
+		return nil
+	}
+
+	return b.update(func() error {
+		current, err := b.locate(item)
+		if err != nil {
+			return err
+		}
+		if current.Priority == priority {
+			return nil
+		}
+
+		line := b.lines[current.Line]
+		b.lines = append(b.lines[:current.Line], b.lines[current.Line+1:]...)
+
+		header := -1
+		for i, l := range b.lines {
+			if sectionPriority(l) == priority {
+				header = i
+				break
+			}
+		}
+
+		if header == -1 {
+			b.lines = append(b.lines, "", sectionMarkers[priority], line)
+		} else {
+			b.lines = append(b.lines[:header+1], append([]string{line}, b.lines[header+1:]...)...)
+		}
+		return nil
+	})
+}
+
+// Reorder moves the item up (delta < 0) or down (delta > 0) one place
+// within its priority by swapping lines with its neighbour
+func (b *Backlog) Reorder(item Item, delta int) error {
+	return b.update(func() error {
+		current, err := b.locate(item)
+		if err != nil {
+			return err
+		}
+
+		items := b.Items[current.Priority]
+		for i := range items {
+			if items[i].Line != current.Line {
+				continue
+			}
+			j := i + 1
+			if delta < 0 {
+				j = i - 1
+			}
+			if j < 0 {
+				return fmt.Errorf("already at the top of %s", current.Priority)
+			}
+			if j >= len(items) {
+				return fmt.Errorf("already at the bottom of %s", current.Priority)
+			}
+			b.lines[current.Line], b.lines[items[j].Line] = b.lines[items[j].Line], b.lines[current.Line]
+			return nil
+		}
+		return fmt.Errorf("no backlog item on line %d", current.Line+1)
+	})
+}
+
+// Resolve finds an item by ID, unique ID prefix or list position ("P1.2")
+func (b *Backlog) Resolve(ref string) (Item, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return Item{}, fmt.Errorf("no item given")
+	}
+
+	if p, n, ok := strings.Cut(ref, "."); ok {
+		if priority, err := ParsePriority(p); err == nil {
+			var pos int
+			if _, err := fmt.Sscanf(n, "%d", &pos); err == nil {
+				items := b.Items[priority]
+				if pos < 1 || pos > len(items) {
+					return Item{}, fmt.Errorf("%s has %d items", priority, len(items))
+				}
+				return items[pos-1], nil
+			}
+		}
+	}
+
+	if item, ok := b.Find(ref); ok {
+		return item, nil
+	}
+
+	var matches []Item
+	for _, p := range []string{P0, P1, P2, P3} {
+		for _, item := range b.Items[p] {
+			if item.ID != "" && strings.HasPrefix(item.ID, ref) {
+				matches = append(matches, item)
+			}
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return Item{}, fmt.Errorf("no backlog item %q", ref)
+	default:
+		return Item{}, fmt.Errorf("%q matches %d items, use more characters", ref, len(matches))
+	}
+}
+
+// rewrite applies change to the item and replaces its line in place
+func (b *Backlog) rewrite(item Item, change func(*Item)) error {
+	return b.update(func() error {
+		current, err := b.locate(item)
+		if err != nil {
+			return err
+		}
+		change(&current)
+		b.replaceLine(current)
+		return nil
+	})
+}
+
+// replaceLine rewrites the item's line, keeping its indentation
+func (b *Backlog) replaceLine(item Item) {
+	line := b.lines[item.Line]
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	b.lines[item.Line] = indent + formatItem(item)
+}
+
+// locate finds the item again after update re-read the file: by ID when it
+// has one, since another command may have shifted lines, otherwise on the
+// line it was parsed from
+func (b *Backlog) locate(item Item) (Item, error) {
+	if item.ID != "" {
+		if current, ok := b.Find(item.ID); ok {
+			return current, nil
+		}
+		return Item{}, fmt.Errorf("backlog.md changed, no item with id %s", item.ID)
+	}
+	if current, ok := b.itemAt(item.Line); ok && current.ID == "" && current.Text == item.Text {
+		return current, nil
+	}
+	return Item{}, fmt.Errorf("backlog.md changed, no matching item on line %d", item.Line+1)
+}
+
+// update re-reads backlog.md while holding its lock, lets fn edit the
+// fresh lines, then writes them atomically and re-parses so items and
+// line numbers are current. Concurrent commands never lose each other's
+// edits.
+func (b *Backlog) update(fn func() error) error {
+	unlock, err := fileutil.Lock(b.Path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read backlog: %w", err)
+	}
+	*b = *Parse(string(data), b.Path)
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	content := strings.Join(b.lines, "\n")
+	if err := fileutil.WriteFile(b.Path, []byte(content), 0644); err != nil {
+		return err
+	}
+	*b = *Parse(content, b.Path)
+	return nil
+}
+
+func (b *Backlog) itemAt(line int) (Item, bool) {
+	for _, items := range b.Items {
+		for _, item := range items {
+			if item.Line == line {
+				return item, true
+			}
 		}
-		markdown = markdown[:endOfLine] + item + markdown[endOfLine:]
-	} else {
-		markdown += fmt.Sprintf("\n%s\n%s", marker, item)
 	}
+	return Item{}, false
+}
 
-	return os.WriteFile(b.Path, []byte(markdown), 0644)
+// newID returns a short ID not used by any item
+func (b *Backlog) newID() string {
+	for {
+		id := task.NewShortID()
+		if _, taken := b.Find(id); !taken {
+			return id
+		}
+	}
 }
 
 // GetUnchecked returns all unchecked items ordered by priority
@@ -190,3 +595,47 @@ func (b *Backlog) TotalUnchecked() int {
 	}
 	return total
 }
+
+// DuplicateIDs returns IDs used by more than one item
+func (b *Backlog) DuplicateIDs() []string {
+	seen := make(map[string]int)
+	var dups []string
+	for _, p := range []string{P0, P1, P2, P3} {
+		for _, item := range b.Items[p] {
+			if item.ID == "" {
+				continue
+			}
+			seen[item.ID]++
+			if seen[item.ID] == 2 {
+				dups = append(dups, item.ID)
+			}
+		}
+	}
+	return dups
+}
+
+// FixDuplicateIDs gives every item after the first that shares an ID a new
+// one. Returns how many items were renumbered.
+func (b *Backlog) FixDuplicateIDs() (int, error) {
+	fixed := 0
+	err := b.update(func() error {
+		seen := make(map[string]bool)
+		for _, p := range []string{P0, P1, P2, P3} {
+			for _, item := range b.Items[p] {
+				if item.ID == "" {
+					continue
+				}
+				if !seen[item.ID] {
+					seen[item.ID] = true
+					continue
+				}
+				item.ID = b.newID()
+				seen[item.ID] = true
+				b.replaceLine(item)
+				fixed++
+			}
+		}
+		return nil
+	})
+	return fixed, err
+}
@@ -1,7 +1,13 @@
 package backlog
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestParse(t *testing.T) {
@@ -165,3 +171,253 @@ func TestParseEmpty(t *testing.T) {
 		t.Errorf("Expected empty backlog, got %d items", b.Total())
 	}
 }
+
+func TestParseIDAndDoneDate(t *testing.T) {
+	content := `## P0 - Launch Blockers
+- [x] Fix login bug done:2024-12-27 <!-- id:a1b2c3 -->
+- [ ] Deploy button missing <!-- id:d4e5f6 -->
+- [ ] No ID yet
+`
+
+	b := Parse(content, "/test/backlog.md")
+	items := b.Items[P0]
+
+	if items[0].ID != "a1b2c3" || items[0].DoneDate != "2024-12-27" || items[0].Text != "Fix login bug" {
+		t.Errorf("Unexpected first item: %+v", items[0])
+	}
+	if items[1].ID != "d4e5f6" || items[1].Text != "Deploy button missing" {
+		t.Errorf("Unexpected second item: %+v", items[1])
+	}
+	if items[2].ID != "" {
+		t.Errorf("Expected no ID, got %q", items[2].ID)
+	}
+
+	if item, ok := b.Find("d4e5f6"); !ok || item.Line != 2 {
+		t.Errorf("Expected to find d4e5f6 on line 2, got %+v", item)
+	}
+}
+
+func TestAddEnsureIDAndComplete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backlog.md")
+	content := `# Backlog
+
+## P0 - Launch Blockers
+  - [ ] Legacy item
+
+## P2 - Nice to Have
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write backlog: %v", err)
+	}
+
+	b := Parse(content, path)
+
+	id, err := b.Add("Dark mode", P2)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if len(id) != 6 {
+		t.Errorf("Expected 6-character ID, got %q", id)
+	}
+	if item, ok := b.Find(id); !ok || item.Text != "Dark mode" || item.Priority != P2 {
+		t.Errorf("Expected added item to be found, got %+v", item)
+	}
+
+	legacy := b.Items[P0][0]
+	legacyID, err := b.EnsureID(legacy.Line)
+	if err != nil {
+		t.Fatalf("EnsureID failed: %v", err)
+	}
+	if again, _ := b.EnsureID(legacy.Line); again != legacyID {
+		t.Errorf("Expected EnsureID to be stable, got %q then %q", legacyID, again)
+	}
+
+	date := time.Date(2024, 12, 28, 10, 0, 0, 0, time.Local)
+	if err := b.Complete(legacyID, date); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "  - [x] Legacy item done:2024-12-28 <!-- id:" + legacyID + " -->"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("Expected backlog to contain %q, got:\n%s", want, data)
+	}
+
+	reloaded := Parse(string(data), path)
+	if item, _ := reloaded.Find(legacyID); !item.Checked || item.DoneDate != "2024-12-28" {
+		t.Errorf("Expected completed item after reload, got %+v", item)
+	}
+
+	if err := b.Complete("zzzzzz", date); err == nil {
+		t.Error("Expected error completing unknown ID")
+	}
+}
+
+func TestEditingKeepsOtherLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backlog.md")
+	content := `# Backlog
+
+Some notes the user keeps at the top.
+
+## P0 - Launch Blockers
+- [ ] First <!-- id:aaa111 -->
+- [ ] Second <!-- id:bbb222 -->
+  - sub-note about second
+
+## P1 - Paying User Blockers
+- [ ] Third <!-- id:ccc333 -->
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write backlog: %v", err)
+	}
+	b := Parse(content, path)
+
+	// Reorder within P0
+	second, err := b.Resolve("bbb")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if err := b.Reorder(second, -1); err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+	if b.Items[P0][0].ID != "bbb222" {
+		t.Errorf("Expected bbb222 first in P0, got %+v", b.Items[P0])
+	}
+	if err := b.Reorder(b.Items[P0][0], -1); err == nil {
+		t.Error("Expected error moving the top item up")
+	}
+
+	// Move to another priority
+	third, _ := b.Resolve("P1.1")
+	if err := b.Move(third, P0); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if len(b.Items[P1]) != 0 || b.Items[P0][0].ID != "ccc333" {
+		t.Errorf("Expected ccc333 at the top of P0, got P0=%+v P1=%+v", b.Items[P0], b.Items[P1])
+	}
+
+	// Edit and remove
+	first, _ := b.Resolve("aaa111")
+	if err := b.Edit(first, "First, reworded"); err != nil {
+		t.Fatalf("Edit failed: %v", err)
+	}
+	if item, _ := b.Find("aaa111"); item.Text != "First, reworded" {
+		t.Errorf("Expected edited text, got %q", item.Text)
+	}
+	edited, _ := b.Find("aaa111")
+	if err := b.Remove(edited); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, ok := b.Find("aaa111"); ok {
+		t.Error("Expected aaa111 to be removed")
+	}
+
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{
+		"Some notes the user keeps at the top.",
+		"  - sub-note about second",
+		"## P1 - Paying User Blockers",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected backlog to keep %q, got:\n%s", want, data)
+		}
+	}
+
+	if _, err := b.Resolve("zzz"); err == nil {
+		t.Error("Expected error resolving unknown ID")
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for input, want := range map[string]string{"P0": P0, "p1": P1, "2": P2, " P3 ": P3} {
+		if got, err := ParsePriority(input); err != nil || got != want {
+			t.Errorf("ParsePriority(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParsePriority("P4"); err == nil {
+		t.Error("Expected error for P4")
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	content := `## P1 - Paying User Blockers
+- [ ] Fix checkout #frontend #Payments ~2h due:2026-11-01 @alice <!-- id:abc123 -->
+- [ ] Plain item
+- [ ] Fix #123 login redirect #auth
+`
+
+	b := Parse(content, "/test/backlog.md")
+	item := b.Items[P1][0]
+
+	if len(item.Tags) != 2 || !item.HasTag("#frontend") || !item.HasTag("payments") {
+		t.Errorf("Unexpected tags: %v", item.Tags)
+	}
+	if item.Estimate != "2h" || item.EstimateHours != 2 {
+		t.Errorf("Unexpected estimate: %q (%v)", item.Estimate, item.EstimateHours)
+	}
+	if item.Due.Format("2006-01-02") != "2026-11-01" {
+		t.Errorf("Unexpected due date: %v", item.Due)
+	}
+	if item.Assignee != "alice" {
+		t.Errorf("Unexpected assignee: %q", item.Assignee)
+	}
+	if item.Title() != "Fix checkout" {
+		t.Errorf("Unexpected title: %q", item.Title())
+	}
+
+	plain := b.Items[P1][1]
+	if len(plain.Tags) != 0 || !plain.Due.IsZero() || plain.Assignee != "" || plain.Title() != "Plain item" {
+		t.Errorf("Expected no metadata on plain item, got %+v", plain)
+	}
+
+	// Issue references aren't tags
+	issue := b.Items[P1][2]
+	if len(issue.Tags) != 1 || !issue.HasTag("auth") || issue.HasTag("123") {
+		t.Errorf("Expected only the auth tag, got %v", issue.Tags)
+	}
+	if issue.Title() != "Fix #123 login redirect" {
+		t.Errorf("Expected the issue reference kept in the title, got %q", issue.Title())
+	}
+}
+
+func TestAddConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backlog.md")
+	content := "# Backlog\n\n## P0 - Launch Blockers\n\n## P2 - Nice to Have\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write backlog: %v", err)
+	}
+
+	// Each worker holds its own stale copy, like separate yo processes
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b := Parse(content, path)
+			if _, err := b.Add(fmt.Sprintf("Item %d", i), P2); err != nil {
+				t.Errorf("Add failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	data, _ := os.ReadFile(path)
+	b := Parse(string(data), path)
+	if len(b.Items[P2]) != workers {
+		t.Fatalf("Expected %d items, got %d (lost updates)", workers, len(b.Items[P2]))
+	}
+
+	// Ticking an item by ID still works after other lines have shifted
+	stale := Parse(string(data), path)
+	target := stale.Items[P2][workers-1]
+	if _, err := b.Add("Pushes every line down", P0); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := stale.Check(target, time.Now()); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if item, _ := stale.Find(target.ID); !item.Checked || item.Text != target.Text {
+		t.Errorf("Expected %q to be checked, got %+v", target.Text, item)
+	}
+}
@@ -0,0 +1,113 @@
+package backlog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter selects backlog items by their inline metadata
+type Filter struct {
+	Tag      string    // match items tagged #Tag
+	Assignee string    // match items assigned @Assignee
+	DueBy    time.Time // match items due on or before this day
+	Overdue  bool      // match only overdue items
+}
+
+// Empty reports whether the filter matches everything
+func (f Filter) Empty() bool {
+	return f.Tag == "" && f.Assignee == "" && f.DueBy.IsZero() && !f.Overdue
+}
+
+// Match reports whether the item passes the filter
+func (f Filter) Match(item Item, now time.Time) bool {
+	if f.Tag != "" && !item.HasTag(f.Tag) {
+		return false
+	}
+	if f.Assignee != "" && !strings.EqualFold(item.Assignee, strings.TrimPrefix(f.Assignee, "@")) {
+		return false
+	}
+	if f.Overdue && !item.Overdue(now) {
+		return false
+	}
+	if !f.DueBy.IsZero() && (item.Due.IsZero() || item.Due.After(f.DueBy)) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the items that pass the filter
+func (f Filter) Apply(items []Item, now time.Time) []Item {
+	matched := []Item{}
+	for _, item := range items {
+		if f.Match(item, now) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// ParseDueWindow turns a --due value into a filter: "overdue", "today",
+// "week", a number of days like "3d", or a date (YYYY-MM-DD)
+func ParseDueWindow(s string, now time.Time) (Filter, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case "overdue":
+		return Filter{Overdue: true}, nil
+	case "today":
+		return Filter{DueBy: today}, nil
+	case "week":
+		return Filter{DueBy: today.AddDate(0, 0, 7)}, nil
+	}
+
+	if strings.HasSuffix(s, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && days >= 0 {
+			return Filter{DueBy: today.AddDate(0, 0, days)}, nil
+		}
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return Filter{DueBy: date}, nil
+	}
+
+	return Filter{}, fmt.Errorf("invalid due window %q (use overdue, today, week, 3d or YYYY-MM-DD)", s)
+}
+
+// SortByDue orders items by due date, soonest first; items without a due
+// date keep their order at the end
+func SortByDue(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Due, items[j].Due
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+}
+
+// GetNextCandidates returns unchecked items ordered by priority, with
+// overdue items first within each priority
+func (b *Backlog) GetNextCandidates(now time.Time) []Item {
+	var items []Item
+	for _, p := range []string{P0, P1, P2, P3} {
+		var overdue, rest []Item
+		for _, item := range b.Items[p] {
+			if item.Checked {
+				continue
+			}
+			if item.Overdue(now) {
+				overdue = append(overdue, item)
+			} else {
+				rest = append(rest, item)
+			}
+		}
+		SortByDue(overdue)
+		items = append(items, overdue...)
+		items = append(items, rest...)
+	}
+	return items
+}
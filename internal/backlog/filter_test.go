@@ -0,0 +1,92 @@
+package backlog
+
+import (
+	"testing"
+	"time"
+)
+
+const metadataBacklog = `## P0 - Launch Blockers
+- [ ] Later #api due:2026-12-01
+- [ ] Overdue one #frontend due:2026-10-01 @alice
+- [ ] No date @bob
+
+## P1 - Paying User Blockers
+- [ ] Soon #frontend due:2026-10-20
+- [ ] Overdue P1 due:2026-10-10
+- [x] Done overdue due:2026-09-01
+`
+
+func TestFilter(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)
+	b := Parse(metadataBacklog, "/test/backlog.md")
+	all := append(append([]Item{}, b.Items[P0]...), b.Items[P1]...)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"empty", Filter{}, 6},
+		{"tag", Filter{Tag: "frontend"}, 2},
+		{"assignee", Filter{Assignee: "@alice"}, 1},
+		{"overdue", Filter{Overdue: true}, 2},
+		{"tag and overdue", Filter{Tag: "frontend", Overdue: true}, 1},
+	}
+
+	for _, tt := range tests {
+		if got := tt.filter.Apply(all, now); len(got) != tt.want {
+			t.Errorf("%s: expected %d items, got %d", tt.name, tt.want, len(got))
+		}
+	}
+
+	week, err := ParseDueWindow("week", now)
+	if err != nil {
+		t.Fatalf("ParseDueWindow failed: %v", err)
+	}
+	// Everything dated up to Oct 24, including overdue and done items
+	if got := week.Apply(all, now); len(got) != 4 {
+		t.Errorf("Expected 4 items due within a week, got %d", len(got))
+	}
+
+	for _, window := range []string{"overdue", "today", "3d", "2026-11-01"} {
+		if _, err := ParseDueWindow(window, now); err != nil {
+			t.Errorf("ParseDueWindow(%q) failed: %v", window, err)
+		}
+	}
+	if _, err := ParseDueWindow("someday", now); err == nil {
+		t.Error("Expected error for invalid window")
+	}
+}
+
+func TestSortByDue(t *testing.T) {
+	b := Parse(metadataBacklog, "/test/backlog.md")
+	items := append([]Item{}, b.Items[P0]...)
+
+	SortByDue(items)
+
+	if items[0].Title() != "Overdue one" || items[1].Title() != "Later" || items[2].Title() != "No date" {
+		t.Errorf("Unexpected order: %q, %q, %q", items[0].Title(), items[1].Title(), items[2].Title())
+	}
+}
+
+func TestGetNextCandidates(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)
+	b := Parse(metadataBacklog, "/test/backlog.md")
+
+	items := b.GetNextCandidates(now)
+
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.Title())
+	}
+	want := []string{"Overdue one", "Later", "No date", "Overdue P1", "Soon"}
+	if len(titles) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, titles)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, titles)
+			break
+		}
+	}
+}
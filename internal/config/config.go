@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
 	"github.com/faisalahmedsifat/yo/internal/state"
 )
 
@@ -16,6 +18,19 @@ type Config struct {
 	Editor        string   `json:"editor"`
 	MaxBypassDay  int      `json:"max_bypass_day"`
 	MaxBypassWeek int      `json:"max_bypass_week"`
+	BypassMinutes int      `json:"bypass_minutes"`
+
+	// Timer extension limits per task
+	MaxExtensions     int     `json:"max_extensions"`
+	MaxExtensionHours float64 `json:"max_extension_hours"`
+
+	// Seconds a success criterion's command may run
+	CriteriaTimeout int `json:"criteria_timeout"`
+
+	// Outstanding tech debt, in estimated fix hours, before yo defer warns
+	// (0 for no budget), and whether it asks before logging more
+	DebtBudgetHours   float64 `json:"debt_budget_hours"`
+	DebtBudgetConfirm bool    `json:"debt_budget_confirm"`
 }
 
 // Default returns the default configuration
@@ -27,6 +42,12 @@ func Default() *Config {
 		Editor:        os.Getenv("EDITOR"),
 		MaxBypassDay:  1,
 		MaxBypassWeek: 5,
+		BypassMinutes: 30,
+
+		MaxExtensions:     2,
+		MaxExtensionHours: 4,
+
+		CriteriaTimeout: 300,
 	}
 }
 
@@ -45,7 +66,15 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	return load(configPath)
+}
 
+// LoadFrom loads configuration from the given .yo directory
+func LoadFrom(yoDir string) (*Config, error) {
+	return load(filepath.Join(yoDir, "config.json"))
+}
+
+func load(configPath string) (*Config, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -68,19 +97,67 @@ func (c *Config) Save() error {
 	if err != nil {
 		return err
 	}
+	return c.save(configPath)
+}
 
+// SaveTo saves configuration to the given .yo directory
+func (c *Config) SaveTo(yoDir string) error {
+	return c.save(filepath.Join(yoDir, "config.json"))
+}
+
+func (c *Config) save(configPath string) error {
+	unlock, err := fileutil.Lock(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return c.write(configPath)
+}
+
+// write atomically replaces config.json; the caller holds the lock
+func (c *Config) write(configPath string) error {
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := fileutil.WriteFile(configPath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
 	return nil
 }
 
+// Update loads the configuration, applies fn and saves the result while
+// holding the config lock
+func Update(fn func(*Config) error) (*Config, error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := fileutil.Lock(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	cfg, err := load(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.write(configPath); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 // Set sets a configuration value
 func (c *Config) Set(key, value string) error {
 	switch key {
@@ -88,6 +165,38 @@ func (c *Config) Set(key, value string) error {
 		c.Notifications = value == "on" || value == "true"
 	case "editor":
 		c.Editor = value
+	case "bypass_minutes":
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("bypass_minutes must be a positive integer")
+		}
+		c.BypassMinutes = n
+	case "max_extensions":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("max_extensions must be a non-negative integer")
+		}
+		c.MaxExtensions = n
+	case "max_extension_hours":
+		h, err := strconv.ParseFloat(value, 64)
+		if err != nil || h < 0 {
+			return fmt.Errorf("max_extension_hours must be a non-negative number")
+		}
+		c.MaxExtensionHours = h
+	case "criteria_timeout":
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("criteria_timeout must be a positive number of seconds")
+		}
+		c.CriteriaTimeout = n
+	case "debt_budget_hours":
+		h, err := strconv.ParseFloat(value, 64)
+		if err != nil || h < 0 {
+			return fmt.Errorf("debt_budget_hours must be a non-negative number (0 for no budget)")
+		}
+		c.DebtBudgetHours = h
+	case "debt_budget_confirm":
+		c.DebtBudgetConfirm = value == "on" || value == "true"
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
@@ -107,6 +216,21 @@ func (c *Config) Get(key string) (string, error) {
 	case "watch_dirs":
 		data, _ := json.Marshal(c.WatchDirs)
 		return string(data), nil
+	case "bypass_minutes":
+		return strconv.Itoa(c.BypassMinutes), nil
+	case "max_extensions":
+		return strconv.Itoa(c.MaxExtensions), nil
+	case "max_extension_hours":
+		return strconv.FormatFloat(c.MaxExtensionHours, 'f', -1, 64), nil
+	case "criteria_timeout":
+		return strconv.Itoa(c.CriteriaTimeout), nil
+	case "debt_budget_hours":
+		return strconv.FormatFloat(c.DebtBudgetHours, 'f', -1, 64), nil
+	case "debt_budget_confirm":
+		if c.DebtBudgetConfirm {
+			return "on", nil
+		}
+		return "off", nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
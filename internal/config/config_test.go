@@ -48,6 +48,14 @@ func TestDefault(t *testing.T) {
 		t.Errorf("Expected MaxBypassWeek=5, got %d", cfg.MaxBypassWeek)
 	}
 
+	if cfg.MaxExtensions != 2 {
+		t.Errorf("Expected MaxExtensions=2, got %d", cfg.MaxExtensions)
+	}
+
+	if cfg.MaxExtensionHours != 4 {
+		t.Errorf("Expected MaxExtensionHours=4, got %f", cfg.MaxExtensionHours)
+	}
+
 	if len(cfg.WatchDirs) == 0 {
 		t.Error("Expected at least one watch dir")
 	}
@@ -107,6 +115,48 @@ func TestConfigSet(t *testing.T) {
 		t.Errorf("Expected editor 'nano', got '%s'", cfg.Editor)
 	}
 
+	// Test setting extension limits
+	if err := cfg.Set("max_extensions", "3"); err != nil {
+		t.Fatalf("Failed to set max_extensions: %v", err)
+	}
+	if cfg.MaxExtensions != 3 {
+		t.Errorf("Expected MaxExtensions=3, got %d", cfg.MaxExtensions)
+	}
+	if err := cfg.Set("max_extension_hours", "1.5"); err != nil {
+		t.Fatalf("Failed to set max_extension_hours: %v", err)
+	}
+	if cfg.MaxExtensionHours != 1.5 {
+		t.Errorf("Expected MaxExtensionHours=1.5, got %f", cfg.MaxExtensionHours)
+	}
+	if err := cfg.Set("max_extensions", "lots"); err == nil {
+		t.Error("Expected error for non-numeric max_extensions")
+	}
+
+	// Test setting the criteria command timeout
+	if err := cfg.Set("criteria_timeout", "60"); err != nil {
+		t.Fatalf("Failed to set criteria_timeout: %v", err)
+	}
+	if cfg.CriteriaTimeout != 60 {
+		t.Errorf("Expected CriteriaTimeout=60, got %d", cfg.CriteriaTimeout)
+	}
+	if err := cfg.Set("criteria_timeout", "0"); err == nil {
+		t.Error("Expected error for a zero criteria_timeout")
+	}
+
+	// Test setting the tech debt budget
+	if err := cfg.Set("debt_budget_hours", "12.5"); err != nil {
+		t.Fatalf("Failed to set debt_budget_hours: %v", err)
+	}
+	if cfg.DebtBudgetHours != 12.5 {
+		t.Errorf("Expected DebtBudgetHours=12.5, got %f", cfg.DebtBudgetHours)
+	}
+	if err := cfg.Set("debt_budget_hours", "-1"); err == nil {
+		t.Error("Expected error for a negative debt_budget_hours")
+	}
+	if err := cfg.Set("debt_budget_confirm", "on"); err != nil || !cfg.DebtBudgetConfirm {
+		t.Errorf("Expected debt_budget_confirm on, got %v (%v)", cfg.DebtBudgetConfirm, err)
+	}
+
 	// Test unknown key
 	if err := cfg.Set("unknown_key", "value"); err == nil {
 		t.Error("Expected error for unknown key")
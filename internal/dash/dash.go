@@ -0,0 +1,390 @@
+// Package dash is the full-screen dashboard behind 'yo dash': the data it
+// shows, how it is laid out, and the raw terminal it draws on.
+package dash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/backlog"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
+	"github.com/faisalahmedsifat/yo/internal/timer"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+)
+
+// Data is everything the dashboard shows
+type Data struct {
+	State    *state.State
+	Task     *task.Task // nil without an active task or when its file can't be read
+	Activity []activity.Entry
+	Backlog  map[string]int // open items by priority
+}
+
+// Loader reloads Data, re-reading the activity log and backlog only when
+// they change so a long log isn't parsed every second
+type Loader struct {
+	data     Data
+	activity fileStamp
+	backlog  fileStamp
+	day      int
+}
+
+type fileStamp struct {
+	mod  time.Time
+	size int64
+}
+
+func stamp(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{info.ModTime(), info.Size()}
+}
+
+// Load reads the current state and task, and the activity and backlog if
+// they changed since the last call
+func (l *Loader) Load() (*Data, error) {
+	s, err := state.Load()
+	if err != nil {
+		return nil, err
+	}
+	l.data.State = s
+
+	l.data.Task = nil
+	if taskPath, err := workspace.GetCurrentTaskPath(s); err == nil {
+		if t, err := task.Load(taskPath); err == nil {
+			l.data.Task = t
+		}
+	}
+
+	yoDir, err := state.GetYoDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// The feed is today's, so a new day re-reads it too
+	now := time.Now()
+	if st := stamp(filepath.Join(yoDir, "activity.jsonl")); st != l.activity || now.YearDay() != l.day {
+		entries, err := activity.QueryToday()
+		if err != nil {
+			return nil, err
+		}
+		l.data.Activity = entries
+		l.activity = st
+		l.day = now.YearDay()
+	}
+
+	if st := stamp(filepath.Join(yoDir, "backlog.md")); st != l.backlog || l.data.Backlog == nil {
+		b, err := backlog.Load()
+		if err != nil {
+			return nil, err
+		}
+		l.data.Backlog = b.CountUnchecked()
+		l.backlog = st
+	}
+
+	data := l.data
+	return &data, nil
+}
+
+// View is the dashboard's screen state on top of the data
+type View struct {
+	Data    *Data
+	Cursor  int    // selected success criterion
+	Message string // result of the last action
+	Input   *Input // line being typed, or nil
+	Now     time.Time
+}
+
+// Criteria returns the current task's success criteria
+func (v *View) Criteria() []task.Criterion {
+	if v.Data == nil || v.Data.Task == nil {
+		return nil
+	}
+	return v.Data.Task.Yellow.Criteria
+}
+
+// MoveCursor moves the criterion selection by delta, staying in range
+func (v *View) MoveCursor(delta int) {
+	v.Cursor += delta
+	if n := len(v.Criteria()); v.Cursor >= n {
+		v.Cursor = n - 1
+	}
+	if v.Cursor < 0 {
+		v.Cursor = 0
+	}
+}
+
+const (
+	rule = "━"
+	help = "↑↓ select · space toggle · p pause · e extend · d defer · D done · q quit"
+)
+
+// Render lays the dashboard out in width columns and height rows
+func (v *View) Render(width, height int) []string {
+	s := v.Data.State
+	var top, feed []string
+
+	// Header
+	stage := s.CurrentStage
+	if stage == "" {
+		stage = "none"
+	}
+	header := fmt.Sprintf(" %s %s", stageEmoji(stage), strings.ToUpper(stage))
+	if stage != "none" && stage != "bypass" {
+		header += " LIGHT"
+	}
+	if s.CurrentTaskID != "" {
+		header += "  " + s.CurrentTaskID
+	}
+	top = append(top, spread(header, v.Now.Format("15:04")+" ", width), strings.Repeat(rule, width))
+
+	// Problem and plan
+	if t := v.Data.Task; t != nil && t.Red.Problem != "" {
+		top = append(top, " Problem  "+firstLine(t.Red.Problem))
+		if plan := chosenPlan(t); plan != "" {
+			top = append(top, " Plan     "+plan)
+		}
+	} else {
+		top = append(top, " No task yet. Start one with: yo red  or  yo next")
+	}
+	top = append(top, "")
+
+	// Timer
+	top = append(top, v.timerLines()...)
+	top = append(top, "")
+
+	// Success criteria
+	if criteria := v.Criteria(); len(criteria) > 0 {
+		top = append(top, fmt.Sprintf(" Success criteria (%d/%d)", v.Data.Task.CriteriaMet(), len(criteria)))
+		for i, c := range criteria {
+			pointer, box := "   ", "[ ]"
+			if i == v.Cursor {
+				pointer = " ▸ "
+			}
+			if c.Checked {
+				box = "[x]"
+			}
+			line := pointer + box + " " + c.Text
+			if !c.CheckedAt.IsZero() {
+				line += "  " + c.CheckedAt.Local().Format("15:04")
+			}
+			top = append(top, line)
+		}
+		top = append(top, "")
+	}
+
+	// Backlog counts
+	b := v.Data.Backlog
+	top = append(top, fmt.Sprintf(" Backlog  P0 %d · P1 %d · P2 %d · P3 %d",
+		b[backlog.P0], b[backlog.P1], b[backlog.P2], b[backlog.P3]))
+	top = append(top, "")
+
+	// Today's activity, newest first, in whatever room is left
+	feed = append(feed, fmt.Sprintf(" Today (%d events)", len(v.Data.Activity)))
+	for i := len(v.Data.Activity) - 1; i >= 0; i-- {
+		if line := activityLine(v.Data.Activity[i]); line != "" {
+			feed = append(feed, "   "+line)
+		}
+	}
+
+	// Footer: message or input line, then the key help
+	var footer []string
+	switch {
+	case v.Input != nil:
+		footer = append(footer, " "+v.Input.Prompt+v.Input.Value+"█")
+	case v.Message != "":
+		footer = append(footer, " "+v.Message)
+	default:
+		footer = append(footer, "")
+	}
+	footer = append(footer, strings.Repeat(rule, width), " "+help)
+
+	room := height - len(top) - len(footer)
+	if room < 0 {
+		// Too short: keep the footer, cut the top
+		top = top[:max(0, height-len(footer))]
+		room = 0
+	}
+	if len(feed) > room {
+		feed = feed[:room]
+	}
+
+	lines := append(append(top, feed...), make([]string, room-len(feed))...)
+	lines = append(lines, footer...)
+	for i, line := range lines {
+		lines[i] = Truncate(line, width)
+	}
+	return lines
+}
+
+func (v *View) timerLines() []string {
+	s := v.Data.State
+	switch {
+	case s.CurrentStage == "bypass":
+		line := " 🚨 Bypass: " + s.Bypass.Reason
+		if s.BypassExpired() {
+			return []string{line, "    Expired. Close it with: yo bypass --note \"what you fixed\""}
+		}
+		return []string{line, "    " + timer.FormatDuration(s.BypassRemaining()) + " left"}
+	case s.CurrentStage != "green" || s.Timer.StartedAt.IsZero():
+		return []string{" ⏱  Timer not started (yo go)"}
+	}
+
+	st := timer.GetStatus(s)
+	line := fmt.Sprintf(" ⏱  %s [%s] %3.0f%%  %s / %s", timer.ProgressIndicator(st.Progress),
+		timer.ProgressBar(st.Progress, 30), st.Progress,
+		timer.FormatDurationWithSeconds(st.Elapsed), timer.FormatHours(st.ThresholdHours))
+	if st.Paused {
+		line += "  ⏸ paused"
+	}
+	lines := []string{line}
+	if st.Overtime > 0 {
+		lines = append(lines, "    ⚠️  Over estimate by "+timer.FormatDuration(st.Overtime))
+	}
+	return lines
+}
+
+// chosenPlan summarises the chosen YELLOW option
+func chosenPlan(t *task.Task) string {
+	label := strings.ToUpper(strings.TrimSpace(t.Yellow.ChosenOption))
+	if label == "" {
+		return ""
+	}
+	for _, opt := range t.Yellow.Options {
+		if strings.EqualFold(opt.Label, label) && opt.Description != "" {
+			plan := fmt.Sprintf("Option %s: %s", opt.Label, opt.Description)
+			if opt.Estimate != "" {
+				plan += " (" + opt.Estimate + ")"
+			}
+			return plan
+		}
+	}
+	return "Option " + label
+}
+
+func activityLine(e activity.Entry) string {
+	ts := e.Timestamp.Format("15:04")
+	switch e.Type {
+	case activity.TypeStageChange:
+		return fmt.Sprintf("%s  %s → %s", ts, e.From, e.To)
+	case activity.TypeFileChange:
+		return fmt.Sprintf("%s  📝 %s", ts, e.File)
+	case activity.TypeFileRename:
+		return fmt.Sprintf("%s  🔀 %s → %s", ts, e.From, e.File)
+	case activity.TypeFileDelete:
+		return fmt.Sprintf("%s  🗑  %s", ts, e.File)
+	case activity.TypeEmergencyBypass:
+		return fmt.Sprintf("%s  🚨 Bypass: %s", ts, e.Reason)
+	case activity.TypeTaskComplete:
+		return fmt.Sprintf("%s  ✅ Completed: %s", ts, e.Task)
+	case activity.TypeTimerPause:
+		return fmt.Sprintf("%s  ⏸  Paused", ts)
+	case activity.TypeTimerResume:
+		return fmt.Sprintf("%s  ▶️  Resumed", ts)
+	case activity.TypeTimerExtension:
+		return fmt.Sprintf("%s  ⏱  Extended %s: %s", ts, timer.FormatHours(e.Hours), e.Reason)
+	case activity.TypeTimerMilestone:
+		return fmt.Sprintf("%s  ⏰ %s of estimate", ts, e.Milestone)
+	case activity.TypeCriterionCheck:
+		return fmt.Sprintf("%s  ☑  %s", ts, e.Criterion)
+	case activity.TypeCriterionUncheck:
+		return fmt.Sprintf("%s  ☐  %s", ts, e.Criterion)
+	}
+	return ""
+}
+
+func stageEmoji(stage string) string {
+	switch stage {
+	case "red":
+		return "🔴"
+	case "yellow":
+		return "🟡"
+	case "green":
+		return "🟢"
+	case "bypass":
+		return "🚨"
+	}
+	return "⚪"
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// spread puts left and right at either end of a width-wide line
+func spread(left, right string, width int) string {
+	gap := width - DisplayWidth(left) - DisplayWidth(right)
+	if gap < 1 {
+		return left
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
+// DisplayWidth approximates the columns s takes: emoji and other wide
+// symbols count as two
+func DisplayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == 0xFE0F || r == 0x200D: // variation selector, zero-width joiner
+		return 0
+	case r >= 0x1F300 && r <= 0x1FAFF, r >= 0x2600 && r <= 0x27BF && r != 0x2713,
+		r >= 0x2B00 && r <= 0x2BFF, r >= 0x23E9 && r <= 0x23FA:
+		return 2
+	}
+	return 1
+}
+
+// Truncate cuts s to at most width columns, marking the cut with …
+func Truncate(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	w := 0
+	for i, r := range s {
+		if w+runeWidth(r) > width-1 {
+			return s[:i] + "…"
+		}
+		w += runeWidth(r)
+	}
+	return s
+}
+
+// Input is a line being typed at the bottom of the screen
+type Input struct {
+	Prompt string
+	Value  string
+}
+
+// Handle applies a key to the line. It reports whether the line was
+// submitted (Enter) or cancelled (Esc).
+func (in *Input) Handle(k Key) (submitted, cancelled bool) {
+	switch k.Code {
+	case KeyEnter:
+		return true, false
+	case KeyEsc, KeyCtrlC:
+		return false, true
+	case KeyBackspace:
+		if _, size := utf8.DecodeLastRuneInString(in.Value); size > 0 {
+			in.Value = in.Value[:len(in.Value)-size]
+		}
+	case KeyRune:
+		in.Value += string(k.Rune)
+	}
+	return false, false
+}
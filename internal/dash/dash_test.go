@@ -0,0 +1,178 @@
+package dash
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
+)
+
+const taskFile = `# Current Task
+
+## 🔴 RED LIGHT - Problem Definition
+
+### What's the Problem?
+Login fails for SSO users
+
+## 🟡 YELLOW LIGHT - Analysis & Planning
+
+### Solution Options
+
+#### Option A:
+- Description: Patch the cookie
+- Time estimate: 2h
+- Pros:
+- Cons:
+
+### Decision
+**Chosen option:** A
+**Reason:** Smallest change
+
+### Success Criteria
+- [x] SSO users can log in
+- [ ] Regression test passes
+`
+
+func testView() *View {
+	now := time.Date(2025, 1, 6, 14, 5, 0, 0, time.Local)
+	s := state.NewState()
+	s.CurrentStage = "green"
+	s.CurrentTaskID = "fix_login_a1b2c3"
+	s.Timer.StartedAt = time.Now().Add(-time.Hour)
+	s.Timer.EstimatedHours = 2
+	s.Timer.ThresholdHours = 2
+
+	return &View{
+		Data: &Data{
+			State: s,
+			Task:  task.Parse(taskFile),
+			Activity: []activity.Entry{
+				{Timestamp: now.Add(-time.Hour), Type: activity.TypeStageChange, From: "yellow", To: "green"},
+				{Timestamp: now, Type: activity.TypeFileChange, File: "auth.go"},
+			},
+			Backlog: map[string]int{"P0": 2, "P2": 1},
+		},
+		Now: now,
+	}
+}
+
+func TestRender(t *testing.T) {
+	v := testView()
+	lines := v.Render(80, 30)
+
+	if len(lines) != 30 {
+		t.Fatalf("Expected 30 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		if DisplayWidth(line) > 80 {
+			t.Errorf("Line %d is wider than 80 columns: %q", i, line)
+		}
+	}
+
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"🟢 GREEN LIGHT  fix_login_a1b2c3",
+		"14:05",
+		"Problem  Login fails for SSO users",
+		"Plan     Option A: Patch the cookie (2h)",
+		"Success criteria (1/2)",
+		" ▸ [x] SSO users can log in",
+		"   [ ] Regression test passes",
+		"Backlog  P0 2 · P1 0 · P2 1 · P3 0",
+		"Today (2 events)",
+	} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("Expected screen to contain %q:\n%s", want, screen)
+		}
+	}
+
+	// Newest activity first
+	if strings.Index(screen, "auth.go") > strings.Index(screen, "yellow → green") {
+		t.Errorf("Expected newest activity first:\n%s", screen)
+	}
+	if !strings.HasPrefix(lines[len(lines)-1], " ↑↓ select") {
+		t.Errorf("Expected key help on the last line, got %q", lines[len(lines)-1])
+	}
+}
+
+func TestRenderSmallScreen(t *testing.T) {
+	v := testView()
+	v.Input = &Input{Prompt: "Defer what? ", Value: "retries"}
+
+	lines := v.Render(40, 8)
+	if len(lines) != 8 {
+		t.Fatalf("Expected 8 lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[5], "Defer what? retries") {
+		t.Errorf("Expected the input line to survive, got %q", lines[5])
+	}
+}
+
+func TestMoveCursor(t *testing.T) {
+	v := testView()
+	v.MoveCursor(5)
+	if v.Cursor != 1 {
+		t.Errorf("Expected cursor clamped to 1, got %d", v.Cursor)
+	}
+	v.MoveCursor(-5)
+	if v.Cursor != 0 {
+		t.Errorf("Expected cursor clamped to 0, got %d", v.Cursor)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := ParseKeys([]byte("a \x1b[A\x1b[B\x1bOA\r\x7f\x03é\x1b[1;5C\x1b"))
+	expected := []Key{
+		{Code: KeyRune, Rune: 'a'},
+		{Code: KeyRune, Rune: ' '},
+		{Code: KeyUp},
+		{Code: KeyDown},
+		{Code: KeyUp},
+		{Code: KeyEnter},
+		{Code: KeyBackspace},
+		{Code: KeyCtrlC},
+		{Code: KeyRune, Rune: 'é'},
+		{Code: KeyEsc},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseKeys =\n%v\nexpected\n%v", got, expected)
+	}
+}
+
+func TestInput(t *testing.T) {
+	in := &Input{}
+	for _, k := range ParseKeys([]byte("30mé\x7f")) {
+		in.Handle(k)
+	}
+	if in.Value != "30m" {
+		t.Errorf("Expected 30m, got %q", in.Value)
+	}
+	if submitted, _ := in.Handle(Key{Code: KeyEnter}); !submitted {
+		t.Error("Expected Enter to submit")
+	}
+	if _, cancelled := in.Handle(Key{Code: KeyEsc}); !cancelled {
+		t.Error("Expected Esc to cancel")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in       string
+		width    int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a longer line", 8, "a longe…"},
+		{"🟢 GREEN", 5, "🟢 G…"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.width); got != tt.expected {
+			t.Errorf("Truncate(%q, %d) = %q, expected %q", tt.in, tt.width, got, tt.expected)
+		}
+	}
+}
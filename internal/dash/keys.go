@@ -0,0 +1,84 @@
+package dash
+
+import "unicode/utf8"
+
+// KeyCode identifies a key press
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota // a printable character, in Key.Rune
+	KeyEnter
+	KeyBackspace
+	KeyEsc
+	KeyUp
+	KeyDown
+	KeyCtrlC
+	KeyUnknown
+)
+
+// Key is one key press read from the terminal
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// ParseKeys decodes the bytes of one terminal read into key presses.
+// Escape sequences other than the arrow keys are ignored.
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b:
+			if len(b) == 1 {
+				keys = append(keys, Key{Code: KeyEsc})
+				return keys
+			}
+			if n, key := escapeSequence(b); n > 0 {
+				if key.Code != KeyUnknown {
+					keys = append(keys, key)
+				}
+				b = b[n:]
+				continue
+			}
+			keys = append(keys, Key{Code: KeyEsc})
+		case c == '\r' || c == '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+		case c == 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+		case c < 0x20:
+			// Other control characters
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r != utf8.RuneError {
+				keys = append(keys, Key{Code: KeyRune, Rune: r})
+			}
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+// escapeSequence decodes a CSI (ESC [) or SS3 (ESC O) sequence at the
+// start of b, returning its length or 0 if b doesn't start with one
+func escapeSequence(b []byte) (int, Key) {
+	if len(b) < 3 || (b[1] != '[' && b[1] != 'O') {
+		return 0, Key{}
+	}
+	// Parameters run up to the final byte in @ to ~
+	for i := 2; i < len(b); i++ {
+		if b[i] >= 0x40 && b[i] <= 0x7e {
+			switch b[i] {
+			case 'A':
+				return i + 1, Key{Code: KeyUp}
+			case 'B':
+				return i + 1, Key{Code: KeyDown}
+			}
+			return i + 1, Key{Code: KeyUnknown}
+		}
+	}
+	return 0, Key{}
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package dash
+
+import (
+	"fmt"
+	"os"
+)
+
+// Terminal is unsupported outside Unix: raw input needs stty
+
+type Terminal struct{}
+
+// OpenTerminal always fails on this platform
+func OpenTerminal() (*Terminal, error) {
+	return nil, fmt.Errorf("yo dash is only supported on Unix terminals; use 'yo timer -w'")
+}
+
+func (t *Terminal) Close() error              { return nil }
+func (t *Terminal) Resized() <-chan os.Signal { return nil }
+func (t *Terminal) Size() (width, height int) { return 80, 24 }
@@ -0,0 +1,116 @@
+//go:build unix
+
+package dash
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Terminal is the controlling terminal in raw mode on the alternate screen
+type Terminal struct {
+	saved   string         // stty settings to restore
+	winch   chan os.Signal // SIGWINCH
+	resized chan os.Signal // forwarded once the size is refreshed
+
+	mu            sync.Mutex
+	width, height int
+	closed        bool
+}
+
+// OpenTerminal switches the terminal to unbuffered, unechoed input and the
+// alternate screen. Close restores it.
+func OpenTerminal() (*Terminal, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("yo dash needs an interactive terminal")
+	}
+	// Keep output processing so \n still returns the carriage; Ctrl+C
+	// arrives as a key instead of a signal
+	if _, err := stty("-icanon", "-echo", "-isig", "-ixon", "min", "1", "time", "0"); err != nil {
+		return nil, fmt.Errorf("failed to set up terminal: %w", err)
+	}
+
+	t := &Terminal{
+		saved:   strings.TrimSpace(saved),
+		winch:   make(chan os.Signal, 1),
+		resized: make(chan os.Signal, 1),
+	}
+	t.width, t.height = querySize()
+	signal.Notify(t.winch, syscall.SIGWINCH)
+	go t.watchSize()
+	fmt.Print("\033[?1049h\033[?25l") // alternate screen, hide cursor
+	return t, nil
+}
+
+// Close leaves the alternate screen and restores the terminal settings.
+// Closing again does nothing, so it can be deferred as well.
+func (t *Terminal) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	signal.Stop(t.winch)
+	close(t.winch)
+	fmt.Print("\033[?25h\033[?1049l")
+	_, err := stty(t.saved)
+	return err
+}
+
+// Resized delivers a value whenever the window size changes
+func (t *Terminal) Resized() <-chan os.Signal {
+	return t.resized
+}
+
+// watchSize refreshes the cached size on every SIGWINCH, then passes it on
+// to Resized
+func (t *Terminal) watchSize() {
+	for sig := range t.winch {
+		width, height := querySize()
+		t.mu.Lock()
+		t.width, t.height = width, height
+		t.mu.Unlock()
+		select {
+		case t.resized <- sig:
+		default:
+		}
+	}
+}
+
+// Size returns the terminal's columns and rows as of the last resize
+func (t *Terminal) Size() (width, height int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.width, t.height
+}
+
+// querySize asks stty for the columns and rows, or 80x24 if unknown
+func querySize() (width, height int) {
+	out, err := stty("size")
+	if err == nil {
+		if fields := strings.Fields(out); len(fields) == 2 {
+			rows, err1 := strconv.Atoi(fields[0])
+			cols, err2 := strconv.Atoi(fields[1])
+			if err1 == nil && err2 == nil && rows > 0 && cols > 0 {
+				return cols, rows
+			}
+		}
+	}
+	return 80, 24
+}
+
+// stty runs stty against the terminal on stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,427 @@
+// Package debt is the tech debt register kept in .yo/tech_debt_log.md.
+// Every entry stays readable markdown; its ID sits on the heading as
+// <!-- id:xxxxxx --> and its status as extra **Field:** lines.
+package debt
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
+	"github.com/faisalahmedsifat/yo/internal/task"
+	"github.com/faisalahmedsifat/yo/internal/templates"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+)
+
+// Status is where a debt entry is in its lifecycle
+type Status string
+
+const (
+	Open     Status = "open"
+	Promoted Status = "promoted" // moved to the backlog, not fixed yet
+	Resolved Status = "resolved"
+)
+
+// Entry is one deferred shortcut
+type Entry struct {
+	ID            string
+	Task          string
+	What          string
+	Why           string
+	When          string // come back when
+	Estimate      string // as written, e.g. 2h or TBD
+	EstimateHours float64
+	Status        Status
+	Created       time.Time // when it was deferred; only the day for older entries
+	Resolved      time.Time // day it was resolved, zero while outstanding
+	Note          string    // how it was resolved
+	BacklogID     string    // backlog item it was promoted to
+	Fired         []string  // "2026-10-20 tag:auth" for each tag trigger that fired
+	Location      string    // where in the code, e.g. internal/auth/session.go:120
+	Marker        string    // where 'yo debt scan' last found its yo:debt marker
+
+	start, end int  // lines of the entry, end exclusive
+	stored     bool // whether the ID is written on the heading
+}
+
+// Outstanding reports whether the debt still needs paying back
+func (e Entry) Outstanding() bool {
+	return e.Status != Resolved
+}
+
+// Log is the parsed tech debt log
+type Log struct {
+	Entries []Entry
+	Path    string
+
+	lines []string
+}
+
+// Field names as written in the log
+const (
+	fieldTask     = "Task"
+	fieldWhat     = "What"
+	fieldWhy      = "Why skipped"
+	fieldWhen     = "Come back when"
+	fieldEstimate = "Estimated fix time"
+	fieldStatus   = "Status"
+	fieldResolved = "Resolved"
+	fieldNote     = "Resolution"
+	fieldBacklog  = "Backlog"
+	fieldFired    = "Triggered"
+	fieldLocation = "Location"
+	fieldMarker   = "Marker"
+)
+
+const dateFormat = "2006-01-02"
+
+var (
+	headingRe = regexp.MustCompile(`^##\s+Deferred on\s+(\d{4}-\d{2}-\d{2})\s*(?:<!--\s*id:([0-9a-zA-Z]+)(?:\s+at:(\S+))?\s*-->)?\s*$`)
+	fieldRe   = regexp.MustCompile(`^\*\*([^*]+?):\*\*\s*(.*)$`)
+)
+
+// Load loads the tech debt log from disk
+func Load() (*Log, error) {
+	path, err := workspace.GetTechDebtPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFrom(path)
+}
+
+// LoadFrom loads the tech debt log at path. A missing log is empty.
+func LoadFrom(path string) (*Log, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Parse(templates.TechDebtLog, path), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tech debt log: %w", err)
+	}
+	return Parse(string(content), path), nil
+}
+
+// Parse parses tech debt log markdown
+func Parse(content, path string) *Log {
+	l := &Log{Path: path, lines: strings.Split(content, "\n")}
+
+	var current *Entry
+	closeEntry := func(end int) {
+		if current == nil {
+			return
+		}
+		current.end = end
+		if current.ID == "" {
+			current.ID = legacyID(*current, len(l.Entries))
+		}
+		if current.Status == "" {
+			current.Status = Open
+			if !current.Resolved.IsZero() {
+				current.Status = Resolved
+			}
+		}
+		l.Entries = append(l.Entries, *current)
+		current = nil
+	}
+
+	for i, line := range l.lines {
+		trimmed := strings.TrimSpace(line)
+		if m := headingRe.FindStringSubmatch(trimmed); m != nil {
+			closeEntry(i)
+			created, _ := time.ParseInLocation(dateFormat, m[1], time.Local)
+			if at, err := time.Parse(time.RFC3339, m[3]); err == nil {
+				created = at.Local()
+			}
+			current = &Entry{ID: m[2], Created: created, start: i, stored: m[2] != ""}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if trimmed == "---" || strings.HasPrefix(trimmed, "## ") {
+			closeEntry(i)
+			continue
+		}
+		if m := fieldRe.FindStringSubmatch(trimmed); m != nil {
+			current.setField(m[1], strings.TrimSpace(m[2]))
+		}
+	}
+	closeEntry(len(l.lines))
+
+	return l
+}
+
+func (e *Entry) setField(name, value string) {
+	switch name {
+	case fieldTask:
+		e.Task = value
+	case fieldWhat:
+		e.What = value
+	case fieldWhy:
+		e.Why = value
+	case fieldWhen:
+		e.When = value
+	case fieldEstimate:
+		e.Estimate = value
+		e.EstimateHours, _ = task.ParseEstimate(value)
+	case fieldStatus:
+		e.Status = Status(strings.ToLower(value))
+	case fieldResolved:
+		e.Resolved, _ = time.ParseInLocation(dateFormat, value, time.Local)
+	case fieldNote:
+		e.Note = value
+	case fieldBacklog:
+		e.BacklogID = strings.TrimPrefix(value, "#")
+	case fieldFired:
+		e.Fired = nil
+		for _, record := range strings.Split(value, ",") {
+			if record = strings.TrimSpace(record); record != "" {
+				e.Fired = append(e.Fired, record)
+			}
+		}
+	case fieldLocation:
+		e.Location = value
+	case fieldMarker:
+		e.Marker = value
+	}
+}
+
+// legacyID derives a stable ID for entries logged before IDs existed, so
+// they can be referred to before their first edit writes it down. index,
+// the entry's position in the log, tells identical entries from the same
+// day apart; entries are only appended, so it doesn't move when an edit
+// adds lines above.
+func legacyID(e Entry, index int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%s|%d", e.Created.Format(dateFormat), e.Task, e.What, index)))
+	return hex.EncodeToString(sum[:3])
+}
+
+// heading is the entry's "## Deferred on" line. The comment keeps the ID
+// and the exact time, which the trigger counts start from.
+func heading(e Entry) string {
+	if e.Created.IsZero() {
+		return fmt.Sprintf("## Deferred on %s <!-- id:%s -->", e.Created.Format(dateFormat), e.ID)
+	}
+	return fmt.Sprintf("## Deferred on %s <!-- id:%s at:%s -->", e.Created.Format(dateFormat), e.ID, e.Created.Format(time.RFC3339))
+}
+
+// Format renders a new entry as it is appended to the log
+func Format(e Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%s\n", heading(e))
+	fmt.Fprintf(&b, "**%s:** %s\n\n", fieldTask, e.Task)
+	fmt.Fprintf(&b, "**%s:** %s\n", fieldWhat, e.What)
+	fmt.Fprintf(&b, "**%s:** %s\n", fieldWhy, e.Why)
+	fmt.Fprintf(&b, "**%s:** %s\n", fieldWhen, e.When)
+	fmt.Fprintf(&b, "**%s:** %s\n", fieldEstimate, e.Estimate)
+	if e.Location != "" {
+		fmt.Fprintf(&b, "**%s:** %s\n", fieldLocation, e.Location)
+	}
+	fmt.Fprintf(&b, "**%s:** %s\n", fieldStatus, Open)
+	b.WriteString("\n---\n")
+	return b.String()
+}
+
+// Add appends an open entry dated created and returns its new ID
+func (l *Log) Add(e Entry, created time.Time) (string, error) {
+	var id string
+	err := l.update(func() error {
+		id = l.newID()
+		e.ID = id
+		e.Created = created
+
+		content := strings.Join(l.lines, "\n")
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += Format(e)
+		l.lines = strings.Split(content, "\n")
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Find returns the entry with the given ID
+func (l *Log) Find(id string) (Entry, bool) {
+	if id == "" {
+		return Entry{}, false
+	}
+	for _, e := range l.Entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Lookup finds an entry by ID or unique ID prefix
+func (l *Log) Lookup(ref string) (Entry, error) {
+	ref = strings.TrimPrefix(strings.TrimSpace(ref), "#")
+	if ref == "" {
+		return Entry{}, fmt.Errorf("no tech debt entry given")
+	}
+	if e, ok := l.Find(ref); ok {
+		return e, nil
+	}
+
+	var matches []Entry
+	for _, e := range l.Entries {
+		if strings.HasPrefix(e.ID, ref) {
+			matches = append(matches, e)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return Entry{}, fmt.Errorf("no tech debt entry %q", ref)
+	default:
+		return Entry{}, fmt.Errorf("%q matches %d entries, use more characters", ref, len(matches))
+	}
+}
+
+// Outstanding returns the entries not yet resolved, oldest first
+func (l *Log) Outstanding() []Entry {
+	var entries []Entry
+	for _, e := range l.Entries {
+		if e.Outstanding() {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Resolve marks the entry paid back on date with a note on how
+func (l *Log) Resolve(id, note string, date time.Time) error {
+	return l.edit(id, func(e Entry) ([][2]string, error) {
+		if e.Status == Resolved {
+			return nil, fmt.Errorf("tech debt %s is already resolved", e.ID)
+		}
+		return [][2]string{
+			{fieldStatus, string(Resolved)},
+			{fieldResolved, date.Format(dateFormat)},
+			{fieldNote, note},
+		}, nil
+	})
+}
+
+// Promote records that the entry became the backlog item backlogID
+func (l *Log) Promote(id, backlogID string) error {
+	return l.edit(id, func(e Entry) ([][2]string, error) {
+		if e.Status != Open {
+			return nil, fmt.Errorf("tech debt %s is already %s", e.ID, e.Status)
+		}
+		return [][2]string{
+			{fieldStatus, string(Promoted)},
+			{fieldBacklog, backlogID},
+		}, nil
+	})
+}
+
+// PromotedTo returns the outstanding entries promoted to the backlog item
+func (l *Log) PromotedTo(backlogID string) []Entry {
+	var entries []Entry
+	for _, e := range l.Outstanding() {
+		if backlogID != "" && e.BacklogID == backlogID {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// edit sets the fields change returns on the entry, writing its ID on the
+// heading if it was derived. Only the entry's own lines are touched.
+func (l *Log) edit(id string, change func(Entry) ([][2]string, error)) error {
+	return l.update(func() error {
+		e, ok := l.Find(id)
+		if !ok {
+			return fmt.Errorf("no tech debt entry %q", id)
+		}
+		fields, err := change(e)
+		if err != nil {
+			return err
+		}
+		l.apply(e, fields)
+		return nil
+	})
+}
+
+// apply sets fields on the entry in the raw lines, writing its ID on the
+// heading if it was derived. Entries after it are left with stale line
+// numbers until the log is parsed again.
+func (l *Log) apply(e Entry, fields [][2]string) {
+	if !e.stored {
+		// Entries without an ID predate the time being kept
+		l.lines[e.start] = fmt.Sprintf("## Deferred on %s <!-- id:%s -->", e.Created.Format(dateFormat), e.ID)
+	}
+	for _, f := range fields {
+		e.end = l.setLine(e, f[0], f[1])
+	}
+}
+
+// setLine replaces the entry's **name:** line, or adds one after its last
+// field. It returns the entry's new end.
+func (l *Log) setLine(e Entry, name, value string) int {
+	line := fmt.Sprintf("**%s:** %s", name, value)
+	last := e.start
+	for i := e.start + 1; i < e.end; i++ {
+		m := fieldRe.FindStringSubmatch(strings.TrimSpace(l.lines[i]))
+		if m == nil {
+			continue
+		}
+		if m[1] == name {
+			l.lines[i] = line
+			return e.end
+		}
+		last = i
+	}
+
+	at := last + 1
+	l.lines = append(l.lines[:at], append([]string{line}, l.lines[at:]...)...)
+	return e.end + 1
+}
+
+// update re-reads the log while holding its lock, lets fn edit the fresh
+// lines, then writes them atomically and re-parses
+func (l *Log) update(fn func() error) error {
+	unlock, err := fileutil.Lock(l.Path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	fresh, err := LoadFrom(l.Path)
+	if err != nil {
+		return err
+	}
+	*l = *fresh
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	content := strings.Join(l.lines, "\n")
+	if err := fileutil.WriteFile(l.Path, []byte(content), 0644); err != nil {
+		return err
+	}
+	*l = *Parse(content, l.Path)
+	return nil
+}
+
+// newID returns a short ID not used by any entry
+func (l *Log) newID() string {
+	for {
+		id := task.NewShortID()
+		if _, taken := l.Find(id); !taken {
+			return id
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package debt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/templates"
+)
+
+// legacyLog is an entry written before the register had IDs
+const legacyLog = templates.TechDebtLog + `
+## Deferred on 2025-01-06
+**Task:** fix_login_a1b2c3
+
+**What:** No retry button
+**Why skipped:** Users can click deploy again
+**Come back when:** After 50 users
+**Estimated fix time:** 2h
+
+---
+`
+
+func writeLog(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tech_debt_log.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+func TestParseLegacy(t *testing.T) {
+	l := Parse(legacyLog, "")
+	if len(l.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(l.Entries))
+	}
+
+	e := l.Entries[0]
+	if e.Task != "fix_login_a1b2c3" || e.What != "No retry button" || e.When != "After 50 users" {
+		t.Errorf("Unexpected entry: %+v", e)
+	}
+	if e.Status != Open || e.EstimateHours != 2 || e.Created.Format("2006-01-02") != "2025-01-06" {
+		t.Errorf("Unexpected status, estimate or date: %+v", e)
+	}
+	if len(e.ID) != 6 || Parse(legacyLog, "").Entries[0].ID != e.ID {
+		t.Errorf("Expected a stable derived ID, got %q", e.ID)
+	}
+}
+
+func TestLegacyDuplicates(t *testing.T) {
+	entry := strings.TrimPrefix(legacyLog, templates.TechDebtLog)
+	path := writeLog(t, legacyLog+entry)
+	l, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	first, second := l.Entries[0].ID, l.Entries[1].ID
+	if first == second {
+		t.Fatalf("Expected identical legacy entries to get different IDs, both got %q", first)
+	}
+
+	// Resolving the first adds lines above the second without changing its ID
+	if err := l.Resolve(first, "Added a retry button", time.Now()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	l, _ = LoadFrom(path)
+	if e, ok := l.Find(second); !ok || e.Status != Open {
+		t.Errorf("Expected the second entry to keep ID %s, got %+v", second, l.Entries)
+	}
+}
+
+func TestAddResolvePromote(t *testing.T) {
+	path := writeLog(t, legacyLog)
+	l, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	legacy := l.Entries[0].ID
+
+	created := time.Date(2025, 1, 7, 9, 0, 0, 0, time.Local)
+	id, err := l.Add(Entry{Task: "General", What: "No rate limiting", Why: "10 users", When: "Before launch", Estimate: "3h"}, created)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if len(l.Outstanding()) != 2 {
+		t.Fatalf("Expected 2 outstanding entries, got %d", len(l.Outstanding()))
+	}
+
+	if err := l.Resolve(legacy, "Added a retry button", created); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if err := l.Resolve(legacy, "again", created); err == nil {
+		t.Error("Expected resolving twice to fail")
+	}
+	if err := l.Promote(id, "d4e5f6"); err != nil {
+		t.Fatalf("Promote failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, want := range []string{
+		"## Deferred on 2025-01-06 <!-- id:" + legacy + " -->",
+		"**Estimated fix time:** 2h\n**Status:** resolved\n**Resolved:** 2025-01-07\n**Resolution:** Added a retry button\n\n---",
+		"## Deferred on 2025-01-07 <!-- id:" + id + " at:" + created.Format(time.RFC3339) + " -->",
+		"**Status:** promoted\n**Backlog:** d4e5f6\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected log to contain %q:\n%s", want, content)
+		}
+	}
+
+	again, _ := LoadFrom(path)
+	if e, _ := again.Find(legacy); e.Status != Resolved || e.Note != "Added a retry button" || e.Resolved.IsZero() {
+		t.Errorf("Unexpected resolved entry: %+v", e)
+	}
+	if got := again.PromotedTo("d4e5f6"); len(got) != 1 || got[0].ID != id {
+		t.Errorf("PromotedTo = %+v", got)
+	}
+	if e, _ := again.Find(id); !e.Created.Equal(created) {
+		t.Errorf("Expected the time it was deferred to be kept, got %v", e.Created)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	l := Parse(templates.TechDebtLog+Format(Entry{ID: "a1b2c3", What: "one"})+Format(Entry{ID: "a1ffff", What: "two"}), "")
+
+	if e, err := l.Lookup("a1b"); err != nil || e.What != "one" {
+		t.Errorf("Lookup(a1b) = %+v, %v", e, err)
+	}
+	if _, err := l.Lookup("a1"); err == nil {
+		t.Error("Expected an ambiguous prefix to fail")
+	}
+	if _, err := l.Lookup("zzz"); err == nil {
+		t.Error("Expected an unknown ID to fail")
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tech_debt_log.md")
+	l, err := LoadFrom(path)
+	if err != nil || len(l.Entries) != 0 {
+		t.Fatalf("LoadFrom = %+v, %v", l, err)
+	}
+	if _, err := l.Add(Entry{What: "first"}, time.Now()); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# Tech Debt Log") {
+		t.Errorf("Expected a new log to start from the template:\n%s", data)
+	}
+}
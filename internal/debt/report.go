@@ -0,0 +1,165 @@
+package debt
+
+import (
+	"sort"
+	"time"
+)
+
+// Report sums up the outstanding debt and how fast it's paid back
+type Report struct {
+	Outstanding int
+	Hours       float64 // estimated fix time of the outstanding entries
+	Unestimated int     // outstanding entries without a usable estimate
+	Ages        []AgeBucket
+	Tasks       []TaskDebt // most hours first
+	Weeks       []Flow     // oldest first
+}
+
+// AgeBucket is the outstanding debt deferred within an age range
+type AgeBucket struct {
+	Label string
+	Count int
+	Hours float64
+
+	max time.Duration // exclusive, zero for the last bucket
+}
+
+// TaskDebt is the outstanding debt deferred by one task
+type TaskDebt struct {
+	Task  string
+	Count int
+	Hours float64
+}
+
+// Flow is how much debt was deferred and resolved in a range
+type Flow struct {
+	Start    time.Time
+	End      time.Time
+	Created  int
+	Resolved int
+}
+
+// Ratio is debt created per debt resolved. It's zero when nothing was
+// resolved; check Resolved first.
+func (f Flow) Ratio() float64 {
+	if f.Resolved == 0 {
+		return 0
+	}
+	return float64(f.Created) / float64(f.Resolved)
+}
+
+const day = 24 * time.Hour
+
+// ageBuckets returns the empty age ranges of a report
+func ageBuckets() []AgeBucket {
+	return []AgeBucket{
+		{Label: "under a week", max: 7 * day},
+		{Label: "1-4 weeks", max: 28 * day},
+		{Label: "1-3 months", max: 91 * day},
+		{Label: "over 3 months"},
+	}
+}
+
+// Report builds the report as of now, with the given number of weeks of
+// created and resolved counts ending with the current one
+func (l *Log) Report(now time.Time, weeks int) *Report {
+	r := &Report{Ages: ageBuckets()}
+
+	byTask := map[string]*TaskDebt{}
+	for _, e := range l.Outstanding() {
+		r.Outstanding++
+		r.Hours += e.EstimateHours
+		if e.EstimateHours == 0 {
+			r.Unestimated++
+		}
+
+		age := now.Sub(e.Created)
+		for i := range r.Ages {
+			if r.Ages[i].max == 0 || age < r.Ages[i].max {
+				r.Ages[i].Count++
+				r.Ages[i].Hours += e.EstimateHours
+				break
+			}
+		}
+
+		td, ok := byTask[e.Task]
+		if !ok {
+			td = &TaskDebt{Task: e.Task}
+			byTask[e.Task] = td
+		}
+		td.Count++
+		td.Hours += e.EstimateHours
+	}
+
+	for _, td := range byTask {
+		r.Tasks = append(r.Tasks, *td)
+	}
+	sort.Slice(r.Tasks, func(i, j int) bool {
+		if r.Tasks[i].Hours != r.Tasks[j].Hours {
+			return r.Tasks[i].Hours > r.Tasks[j].Hours
+		}
+		if r.Tasks[i].Count != r.Tasks[j].Count {
+			return r.Tasks[i].Count > r.Tasks[j].Count
+		}
+		return r.Tasks[i].Task < r.Tasks[j].Task
+	})
+
+	start := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+	for i := 0; i < weeks; i++ {
+		from := start.AddDate(0, 0, 7*i)
+		r.Weeks = append(r.Weeks, l.Flow(from, from.AddDate(0, 0, 7)))
+	}
+	return r
+}
+
+// Flow counts the debt deferred and resolved in [start, end)
+func (l *Log) Flow(start, end time.Time) Flow {
+	f := Flow{Start: start, End: end}
+	for _, e := range l.Entries {
+		if within(e.Created, start, end) {
+			f.Created++
+		}
+		if e.Status == Resolved && within(e.Resolved, start, end) {
+			f.Resolved++
+		}
+	}
+	return f
+}
+
+// OutstandingAt returns the debt that was outstanding at t, with its
+// estimated fix hours
+func (l *Log) OutstandingAt(t time.Time) (count int, hours float64) {
+	for _, e := range l.Entries {
+		if e.Created.IsZero() || !e.Created.Before(t) {
+			continue
+		}
+		if e.Status == Resolved && !e.Resolved.IsZero() && e.Resolved.Before(t) {
+			continue
+		}
+		count++
+		hours += e.EstimateHours
+	}
+	return count, hours
+}
+
+// OutstandingHours is the estimated fix time of all outstanding debt
+func (l *Log) OutstandingHours() float64 {
+	hours := 0.0
+	for _, e := range l.Outstanding() {
+		hours += e.EstimateHours
+	}
+	return hours
+}
+
+func within(t, start, end time.Time) bool {
+	return !t.IsZero() && !t.Before(start) && t.Before(end)
+}
+
+// weekStart returns the Monday starting t's week
+func weekStart(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return time.Date(t.Year(), t.Month(), t.Day()-weekday+1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,65 @@
+package debt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/templates"
+)
+
+func TestReport(t *testing.T) {
+	// A Wednesday, so the current week started on Monday the 12th
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	date := func(s string) time.Time {
+		d, _ := time.ParseInLocation(dateFormat, s, time.Local)
+		return d
+	}
+
+	path := writeLog(t, templates.TechDebtLog+
+		Format(Entry{ID: "aaaaaa", Task: "auth", What: "a", Estimate: "2h", Created: date("2026-10-13")})+
+		Format(Entry{ID: "bbbbbb", Task: "auth", What: "b", Estimate: "3h", Created: date("2026-09-20")})+
+		Format(Entry{ID: "cccccc", Task: "cache", What: "c", Estimate: "TBD", Created: date("2026-05-01")})+
+		Format(Entry{ID: "dddddd", Task: "cache", What: "d", Estimate: "8h", Created: date("2026-10-06")}))
+	l, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if err := l.Resolve("dddddd", "done", date("2026-10-12")); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	r := l.Report(now, 2)
+	if r.Outstanding != 3 || r.Hours != 5 || r.Unestimated != 1 {
+		t.Errorf("Unexpected totals: %d entries, %gh, %d unestimated", r.Outstanding, r.Hours, r.Unestimated)
+	}
+
+	counts := []int{}
+	for _, a := range r.Ages {
+		counts = append(counts, a.Count)
+	}
+	if len(counts) != 4 || counts[0] != 1 || counts[1] != 1 || counts[2] != 0 || counts[3] != 1 {
+		t.Errorf("Unexpected age distribution: %+v", r.Ages)
+	}
+
+	if len(r.Tasks) != 2 || r.Tasks[0].Task != "auth" || r.Tasks[0].Count != 2 || r.Tasks[0].Hours != 5 {
+		t.Errorf("Unexpected debt per task: %+v", r.Tasks)
+	}
+
+	if len(r.Weeks) != 2 {
+		t.Fatalf("Expected 2 weeks, got %+v", r.Weeks)
+	}
+	if w := r.Weeks[0]; w.Start.Format(dateFormat) != "2026-10-05" || w.Created != 1 || w.Resolved != 0 || w.Ratio() != 0 {
+		t.Errorf("Unexpected previous week: %+v", w)
+	}
+	if w := r.Weeks[1]; w.Created != 1 || w.Resolved != 1 || w.Ratio() != 1 {
+		t.Errorf("Unexpected current week: %+v", w)
+	}
+
+	// Before it was resolved, the 8h entry was outstanding too
+	if n, hours := l.OutstandingAt(date("2026-10-12")); n != 3 || hours != 11 {
+		t.Errorf("Expected 3 entries and 11h outstanding on the 12th, got %d and %gh", n, hours)
+	}
+	if n, _ := l.OutstandingAt(date("2026-10-13")); n != 2 {
+		t.Errorf("Expected 2 entries outstanding on the 13th, got %d", n)
+	}
+}
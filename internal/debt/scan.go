@@ -0,0 +1,164 @@
+package debt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/faisalahmedsifat/yo/internal/ignore"
+)
+
+// Marker is a comment in the code pointing at tech debt: "yo:debt <id>",
+// or a TODO(yo) that isn't in the log yet
+type Marker struct {
+	ID   string // empty for a TODO(yo) without one
+	File string // relative to the project, slash-separated
+	Line int
+	Text string // the line, trimmed
+}
+
+// Location returns the marker's file:line
+func (m Marker) Location() string {
+	return fmt.Sprintf("%s:%d", m.File, m.Line)
+}
+
+var (
+	markerRe = regexp.MustCompile(`yo:debt\s+#?([0-9a-zA-Z]+)`)
+	todoRe   = regexp.MustCompile(`TODO\(yo\)`)
+)
+
+// maxScanSize skips generated blobs and other files too big to be source
+const maxScanSize = 2 << 20
+
+// ScanMarkers finds the markers in the files under root, skipping what
+// .gitignore ignores, binary files and the .yo workspace
+func ScanMarkers(root string) ([]Marker, error) {
+	var markers []Marker
+	err := ignore.Walk(root, []string{".yo"}, func(rel string) error {
+		found, err := scanFile(filepath.Join(root, filepath.FromSlash(rel)), rel)
+		if err != nil {
+			return nil // unreadable: skip it
+		}
+		markers = append(markers, found...)
+		return nil
+	})
+	return markers, err
+}
+
+func scanFile(path, rel string) ([]Marker, error) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxScanSize {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, nil // binary
+	}
+
+	var markers []Marker
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxScanSize)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		m := Marker{File: rel, Line: n, Text: strings.TrimSpace(line)}
+		if sub := markerRe.FindStringSubmatch(line); sub != nil {
+			m.ID = sub[1]
+		} else if !todoRe.MatchString(line) {
+			continue
+		}
+		markers = append(markers, m)
+	}
+	return markers, nil
+}
+
+// Reconciled is how the markers in the code line up with the log
+type Reconciled struct {
+	Linked   []Marker         // markers of outstanding entries
+	Gone     []Entry          // outstanding entries whose marker has disappeared
+	Orphans  []Marker         // markers with no entry in the log
+	Leftover []Marker         // markers of resolved entries
+	entries  map[string]Entry // entry of each linked marker, by location
+}
+
+// Entry returns the entry a linked or leftover marker belongs to
+func (r *Reconciled) Entry(m Marker) Entry {
+	return r.entries[m.Location()]
+}
+
+// Reconcile matches markers to entries by ID or unique ID prefix, giving
+// matched markers the full ID. An
+// outstanding entry is gone when a previous scan recorded its marker and
+// none is found now.
+func (l *Log) Reconcile(markers []Marker) *Reconciled {
+	r := &Reconciled{entries: map[string]Entry{}}
+	found := map[string]bool{}
+	for _, m := range markers {
+		if m.ID == "" {
+			r.Orphans = append(r.Orphans, m)
+			continue
+		}
+		e, err := l.Lookup(m.ID)
+		if err != nil {
+			r.Orphans = append(r.Orphans, m)
+			continue
+		}
+		m.ID = e.ID
+		found[e.ID] = true
+		r.entries[m.Location()] = e
+		if e.Outstanding() {
+			r.Linked = append(r.Linked, m)
+		} else {
+			r.Leftover = append(r.Leftover, m)
+		}
+	}
+
+	for _, e := range l.Outstanding() {
+		if e.Marker != "" && !found[e.ID] {
+			r.Gone = append(r.Gone, e)
+		}
+	}
+	return r
+}
+
+// RecordMarkers writes where each linked marker was found on its entry,
+// so a later scan can tell when it disappears
+func (l *Log) RecordMarkers(r *Reconciled) error {
+	locations := map[string]string{}
+	for _, m := range r.Linked {
+		e := r.Entry(m)
+		if locations[e.ID] != "" {
+			locations[e.ID] += ", "
+		}
+		locations[e.ID] += m.Location()
+	}
+
+	changed := false
+	for id, marker := range locations {
+		if e, _ := l.Find(id); e.Marker != marker {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	return l.update(func() error {
+		// Bottom up, so inserted lines don't move the entries still to do
+		entries := append([]Entry(nil), l.Entries...)
+		sort.Slice(entries, func(i, j int) bool { return entries[i].start > entries[j].start })
+		for _, e := range entries {
+			if marker, ok := locations[e.ID]; ok && marker != e.Marker {
+				l.apply(e, [][2]string{{fieldMarker, marker}})
+			}
+		}
+		return nil
+	})
+}
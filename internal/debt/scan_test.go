@@ -0,0 +1,93 @@
+package debt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/templates"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestScanMarkers(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		".gitignore":       "gen/\n",
+		"auth/session.go":  "package auth\n\n// yo:debt aaaaaa sessions live in memory\nvar x = 1\n",
+		"api/handler.go":   "package api\n// TODO(yo): paginate this\n",
+		"gen/out.go":       "// yo:debt bbbbbb\n",
+		".yo/notes.md":     "yo:debt cccccc\n",
+		"assets/logo.png":  "\x89PNG\x00yo:debt dddddd",
+		"docs/nothing.txt": "nothing to see\n",
+	})
+
+	markers, err := ScanMarkers(root)
+	if err != nil {
+		t.Fatalf("ScanMarkers failed: %v", err)
+	}
+	if len(markers) != 2 {
+		t.Fatalf("Expected 2 markers, got %+v", markers)
+	}
+	if m := markers[0]; m.ID != "" || m.Location() != "api/handler.go:2" || m.Text != "// TODO(yo): paginate this" {
+		t.Errorf("Unexpected TODO(yo) marker: %+v", m)
+	}
+	if m := markers[1]; m.ID != "aaaaaa" || m.Location() != "auth/session.go:3" {
+		t.Errorf("Unexpected yo:debt marker: %+v", m)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	path := writeLog(t, templates.TechDebtLog+
+		Format(Entry{ID: "aaaaaa", What: "kept", Created: time.Now()})+
+		Format(Entry{ID: "bbbbbb", What: "fixed", Created: time.Now()})+
+		Format(Entry{ID: "cccccc", What: "resolved", Created: time.Now()}))
+	l, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if err := l.Resolve("cccccc", "done", time.Now()); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	first := []Marker{
+		{ID: "aaa", File: "a.go", Line: 3},
+		{ID: "bbbbbb", File: "b.go", Line: 9},
+		{ID: "cccccc", File: "c.go", Line: 1},
+		{ID: "ffffff", File: "d.go", Line: 2},
+		{File: "e.go", Line: 5},
+	}
+	r := l.Reconcile(first)
+	if len(r.Linked) != 2 || r.Entry(r.Linked[0]).ID != "aaaaaa" {
+		t.Errorf("Expected markers matched by ID prefix, got %+v", r.Linked)
+	}
+	if len(r.Leftover) != 1 || len(r.Orphans) != 2 || len(r.Gone) != 0 {
+		t.Errorf("Unexpected reconciliation: %+v", r)
+	}
+
+	if err := l.RecordMarkers(r); err != nil {
+		t.Fatalf("RecordMarkers failed: %v", err)
+	}
+	l, _ = LoadFrom(path)
+	if e, _ := l.Find("bbbbbb"); e.Marker != "b.go:9" {
+		t.Errorf("Expected the marker recorded, got %q", e.Marker)
+	}
+
+	// The marker of bbbbbb was removed since
+	r = l.Reconcile(first[:1])
+	if len(r.Gone) != 1 || r.Gone[0].ID != "bbbbbb" {
+		t.Errorf("Expected bbbbbb to be gone, got %+v", r.Gone)
+	}
+}
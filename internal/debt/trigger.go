@@ -0,0 +1,222 @@
+package debt
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+)
+
+// TriggerKind is what makes a deferred entry due
+type TriggerKind string
+
+const (
+	TriggerAfter TriggerKind = "after" // after:2026-12-01 - the date has come
+	TriggerTasks TriggerKind = "tasks" // tasks:5 - five other tasks were completed
+	TriggerTouch TriggerKind = "touch" // touch:internal/auth/ - the path changed again
+	TriggerTag   TriggerKind = "tag"   // tag:auth - a new RED problem mentions it
+)
+
+// Trigger is a structured "Come back when" condition
+type Trigger struct {
+	Kind  TriggerKind
+	Value string    // as written after the colon
+	Date  time.Time // for after
+	Count int       // for tasks
+}
+
+func (t Trigger) String() string {
+	return string(t.Kind) + ":" + t.Value
+}
+
+var (
+	triggerRe = regexp.MustCompile(`(?i)(?:^|[\s,;(])(after|tasks|touch|tag):([^\s,;)]+)`)
+	firedRe   = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+(.*)$`)
+)
+
+// ParseTriggers finds the triggers in "Come back when" prose. Triggers
+// with a value that doesn't parse are returned as invalid.
+func ParseTriggers(when string) (triggers []Trigger, invalid []string) {
+	for _, m := range triggerRe.FindAllStringSubmatch(when, -1) {
+		t := Trigger{Kind: TriggerKind(strings.ToLower(m[1])), Value: m[2]}
+		switch t.Kind {
+		case TriggerAfter:
+			date, err := time.ParseInLocation(dateFormat, t.Value, time.Local)
+			if err != nil {
+				invalid = append(invalid, t.String())
+				continue
+			}
+			t.Date = date
+		case TriggerTasks:
+			n, err := strconv.Atoi(t.Value)
+			if err != nil || n < 1 {
+				invalid = append(invalid, t.String())
+				continue
+			}
+			t.Count = n
+		case TriggerTag:
+			t.Value = strings.TrimPrefix(t.Value, "#")
+		}
+		triggers = append(triggers, t)
+	}
+	return triggers, invalid
+}
+
+// Triggers returns the entry's structured "Come back when" conditions
+func (e Entry) Triggers() []Trigger {
+	triggers, _ := ParseTriggers(e.When)
+	return triggers
+}
+
+// Due is an open entry with the reasons its triggers fired
+type Due struct {
+	Entry   Entry
+	Reasons []string
+
+	tag Trigger // the tag trigger MatchTags found
+}
+
+// Due returns the open entries whose triggers fired by now, given the
+// activity since the oldest of them was deferred. Work on the task that
+// deferred an entry doesn't count towards its triggers.
+func (l *Log) Due(entries []activity.Entry, now time.Time) []Due {
+	var due []Due
+	for _, e := range l.Entries {
+		if e.Status != Open {
+			continue
+		}
+		if reasons := e.fired(entries, now); len(reasons) > 0 {
+			due = append(due, Due{Entry: e, Reasons: reasons})
+		}
+	}
+	return due
+}
+
+// HasTriggers reports whether any open entry has a trigger to check
+func (l *Log) HasTriggers() bool {
+	for _, e := range l.Entries {
+		if e.Status == Open && len(e.Triggers()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Oldest returns when the oldest open entry was deferred
+func (l *Log) Oldest() time.Time {
+	var oldest time.Time
+	for _, e := range l.Entries {
+		if e.Status == Open && !e.Created.IsZero() && (oldest.IsZero() || e.Created.Before(oldest)) {
+			oldest = e.Created
+		}
+	}
+	return oldest
+}
+
+func (e Entry) fired(entries []activity.Entry, now time.Time) []string {
+	var reasons []string
+	for _, t := range e.Triggers() {
+		switch t.Kind {
+		case TriggerAfter:
+			if !now.Before(t.Date) {
+				reasons = append(reasons, fmt.Sprintf("%s: the date has come", t))
+			}
+		case TriggerTasks:
+			if n := e.countLater(entries, func(a activity.Entry) bool { return a.Type == activity.TypeTaskComplete }); n >= t.Count {
+				reasons = append(reasons, fmt.Sprintf("%s: %d tasks done since", t, n))
+			}
+		case TriggerTouch:
+			var touched string
+			e.countLater(entries, func(a activity.Entry) bool {
+				if a.IsFileEvent() && (touches(a.Repo, a.File, t.Value) || touches(a.Repo, a.From, t.Value)) {
+					touched = a.File
+					return true
+				}
+				return false
+			})
+			if touched != "" {
+				reasons = append(reasons, fmt.Sprintf("%s: %s changed", t, touched))
+			}
+		}
+	}
+	for _, record := range e.Fired {
+		if m := firedRe.FindStringSubmatch(record); m != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: a RED problem mentioned it on %s", m[2], m[1]))
+		}
+	}
+	return reasons
+}
+
+// countLater counts the activity since the entry was deferred that match,
+// leaving out the deferring task's own work
+func (e Entry) countLater(entries []activity.Entry, match func(activity.Entry) bool) int {
+	n := 0
+	for _, a := range entries {
+		if a.Timestamp.Before(e.Created) || (a.Task != "" && a.Task == e.Task) {
+			continue
+		}
+		if match(a) {
+			n++
+		}
+	}
+	return n
+}
+
+// touches reports whether a file changed, renamed or deleted in repo is
+// path or inside it. path may be relative to the repo or absolute.
+func touches(repo, file, path string) bool {
+	if file == "" {
+		return false
+	}
+	path = filepath.ToSlash(filepath.Clean(path))
+	file = filepath.ToSlash(file)
+	if filepath.IsAbs(path) && repo != "" {
+		file = filepath.ToSlash(filepath.Join(repo, file))
+	}
+	return file == path || strings.HasPrefix(file, path+"/") || strings.HasSuffix(file, "/"+path)
+}
+
+// MatchTags returns the open entries with a tag trigger the problem
+// mentions, as a word or #tag, and the matching trigger
+func (l *Log) MatchTags(problem string) []Due {
+	var due []Due
+	for _, e := range l.Entries {
+		if e.Status != Open {
+			continue
+		}
+		for _, t := range e.Triggers() {
+			if t.Kind != TriggerTag {
+				continue
+			}
+			re := regexp.MustCompile(`(?i)(?:^|[^\w-])#?` + regexp.QuoteMeta(t.Value) + `(?:$|[^\w-])`)
+			if re.MatchString(problem) {
+				due = append(due, Due{Entry: e, Reasons: []string{t.String() + ": this problem mentions it"}, tag: t})
+				break
+			}
+		}
+	}
+	return due
+}
+
+// MarkFired records on the entry that MatchTags found its tag on date, as
+// that can't be worked out again from the activity log. Each tag keeps one
+// record, of the day it last fired.
+func (l *Log) MarkFired(d Due, date time.Time) error {
+	if d.tag.Kind != TriggerTag {
+		return fmt.Errorf("tech debt %s has no tag trigger to record", d.Entry.ID)
+	}
+	return l.edit(d.Entry.ID, func(e Entry) ([][2]string, error) {
+		records := []string{}
+		for _, record := range e.Fired {
+			if m := firedRe.FindStringSubmatch(record); m == nil || !strings.EqualFold(m[2], d.tag.String()) {
+				records = append(records, record)
+			}
+		}
+		records = append(records, date.Format(dateFormat)+" "+d.tag.String())
+		return [][2]string{{fieldFired, strings.Join(records, ", ")}}, nil
+	})
+}
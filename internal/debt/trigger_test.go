@@ -0,0 +1,147 @@
+package debt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/templates"
+)
+
+func TestParseTriggers(t *testing.T) {
+	triggers, invalid := ParseTriggers("after:2026-12-01, tasks:3 or touch:internal/auth/ (tag:#Auth)")
+	if len(triggers) != 4 {
+		t.Fatalf("Expected 4 triggers, got %+v", triggers)
+	}
+	if triggers[0].Kind != TriggerAfter || triggers[0].Date.Format(dateFormat) != "2026-12-01" {
+		t.Errorf("Unexpected after trigger: %+v", triggers[0])
+	}
+	if triggers[1].Kind != TriggerTasks || triggers[1].Count != 3 {
+		t.Errorf("Unexpected tasks trigger: %+v", triggers[1])
+	}
+	if triggers[2].Kind != TriggerTouch || triggers[2].Value != "internal/auth/" {
+		t.Errorf("Unexpected touch trigger: %+v", triggers[2])
+	}
+	if triggers[3].Kind != TriggerTag || triggers[3].Value != "Auth" {
+		t.Errorf("Unexpected tag trigger: %+v", triggers[3])
+	}
+	if len(invalid) != 0 {
+		t.Errorf("Expected no invalid triggers, got %v", invalid)
+	}
+
+	if triggers, _ := ParseTriggers("Before launch, after 50 users"); len(triggers) != 0 {
+		t.Errorf("Expected prose without triggers, got %+v", triggers)
+	}
+	if _, invalid := ParseTriggers("after:soon tasks:0"); len(invalid) != 2 {
+		t.Errorf("Expected 2 invalid triggers, got %v", invalid)
+	}
+}
+
+func TestDue(t *testing.T) {
+	created := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	l := Parse(templates.TechDebtLog+
+		Format(Entry{ID: "aaaaaa", Task: "deferring", What: "Date", When: "after:2026-03-10", Created: created})+
+		Format(Entry{ID: "bbbbbb", Task: "deferring", What: "Tasks", When: "tasks:2", Created: created})+
+		Format(Entry{ID: "cccccc", Task: "deferring", What: "Touch", When: "touch:internal/auth", Created: created})+
+		Format(Entry{ID: "dddddd", Task: "deferring", What: "Prose", When: "When needed", Created: created}), "")
+
+	day := func(d int) time.Time { return created.AddDate(0, 0, d) }
+	entries := []activity.Entry{
+		{Timestamp: day(-1), Type: activity.TypeTaskComplete, Task: "before"},
+		{Timestamp: day(1), Type: activity.TypeTaskComplete, Task: "deferring"},
+		{Timestamp: day(1), Type: activity.TypeFileChange, Task: "deferring", File: "internal/auth/session.go"},
+		{Timestamp: day(2), Type: activity.TypeTaskComplete, Task: "later"},
+	}
+
+	if due := l.Due(entries, day(3)); len(due) != 0 {
+		t.Errorf("Expected nothing due from the deferring task's own work, got %+v", due)
+	}
+
+	entries = append(entries,
+		activity.Entry{Timestamp: day(4), Type: activity.TypeTaskComplete, Task: "other"},
+		activity.Entry{Timestamp: day(4), Type: activity.TypeFileChange, Task: "other", File: "internal/auth/token.go"},
+	)
+	due := l.Due(entries, day(8))
+	if len(due) != 3 {
+		t.Fatalf("Expected 3 entries due, got %+v", due)
+	}
+	for i, id := range []string{"aaaaaa", "bbbbbb", "cccccc"} {
+		if due[i].Entry.ID != id || len(due[i].Reasons) != 1 {
+			t.Errorf("Unexpected due entry %d: %+v", i, due[i])
+		}
+	}
+	if !strings.Contains(due[2].Reasons[0], "internal/auth/token.go") {
+		t.Errorf("Expected the touched file in the reason, got %q", due[2].Reasons[0])
+	}
+}
+
+func TestMatchTagsAndMarkFired(t *testing.T) {
+	path := writeLog(t, templates.TechDebtLog+
+		Format(Entry{ID: "aaaaaa", Task: "t", What: "Plain passwords", When: "tag:auth", Created: time.Now()}))
+	l, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+
+	if due := l.MatchTags("OAuth callback is slow"); len(due) != 0 {
+		t.Errorf("Expected no match inside a word, got %+v", due)
+	}
+	due := l.MatchTags("Login broken for #AUTH users")
+	if len(due) != 1 || due[0].Entry.ID != "aaaaaa" {
+		t.Fatalf("Expected the tag to match, got %+v", due)
+	}
+
+	if err := l.MarkFired(due[0], time.Now()); err != nil {
+		t.Fatalf("MarkFired failed: %v", err)
+	}
+	l, _ = LoadFrom(path)
+	fired := l.Due(nil, time.Now())
+	if len(fired) != 1 || !strings.HasPrefix(fired[0].Reasons[0], "tag:auth: a RED problem mentioned it on ") {
+		t.Errorf("Expected the recorded tag trigger to keep the entry due, got %+v", fired)
+	}
+}
+
+func TestMarkFiredKeepsEachTag(t *testing.T) {
+	path := writeLog(t, templates.TechDebtLog+
+		Format(Entry{ID: "aaaaaa", Task: "t", What: "Plain passwords", When: "tag:auth or tag:billing", Created: time.Now()}))
+	l, _ := LoadFrom(path)
+
+	day := time.Date(2026, 10, 20, 12, 0, 0, 0, time.Local)
+	for i, problem := range []string{"#auth is down", "#billing is down", "#auth again"} {
+		due := l.MatchTags(problem)
+		if len(due) != 1 {
+			t.Fatalf("Expected %q to match, got %+v", problem, due)
+		}
+		if err := l.MarkFired(due[0], day.AddDate(0, 0, i)); err != nil {
+			t.Fatalf("MarkFired failed: %v", err)
+		}
+	}
+
+	l, _ = LoadFrom(path)
+	e, _ := l.Find("aaaaaa")
+	expected := []string{"2026-10-21 tag:billing", "2026-10-22 tag:auth"}
+	if strings.Join(e.Fired, "|") != strings.Join(expected, "|") {
+		t.Errorf("Fired = %q, expected %q", e.Fired, expected)
+	}
+	if due := l.Due(nil, day); len(due) != 1 || len(due[0].Reasons) != 2 {
+		t.Errorf("Expected both tags as reasons, got %+v", due)
+	}
+}
+
+func TestDueCountsFromTheTimeDeferred(t *testing.T) {
+	created := time.Date(2026, 3, 2, 15, 0, 0, 0, time.Local)
+	l := Parse(templates.TechDebtLog+
+		Format(Entry{ID: "aaaaaa", Task: "deferring", What: "Tasks", When: "tasks:1", Created: created}), "")
+
+	// A task done that morning, before the debt was deferred, doesn't count
+	morning := []activity.Entry{{Timestamp: created.Add(-4 * time.Hour), Type: activity.TypeTaskComplete, Task: "other"}}
+	if due := l.Due(morning, created.Add(time.Hour)); len(due) != 0 {
+		t.Errorf("Expected nothing due from earlier the same day, got %+v", due)
+	}
+
+	evening := append(morning, activity.Entry{Timestamp: created.Add(2 * time.Hour), Type: activity.TypeTaskComplete, Task: "other"})
+	if due := l.Due(evening, created.Add(3*time.Hour)); len(due) != 1 {
+		t.Errorf("Expected the later task to make it due, got %+v", due)
+	}
+}
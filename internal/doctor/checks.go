@@ -0,0 +1,462 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/backlog"
+	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/registry"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/task"
+	"github.com/faisalahmedsifat/yo/internal/templates"
+	"github.com/faisalahmedsifat/yo/internal/watcher"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+)
+
+// validStages are the stages state.json may hold
+var validStages = map[string]bool{"none": true, "red": true, "yellow": true, "green": true, "bypass": true}
+
+// checkLayout looks for missing directories and template files
+func (c *checker) checkLayout() {
+	for _, name := range workspace.Dirs {
+		dir := c.path(name)
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			c.addFix(Warning, "layout", fmt.Sprintf("%s/ is missing", name), "create it", func() error {
+				return os.MkdirAll(dir, 0755)
+			})
+		}
+	}
+
+	names := make([]string, 0, len(workspace.TemplateFiles))
+	for name := range workspace.TemplateFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path, content := c.path(name), workspace.TemplateFiles[name]
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			sev := Warning
+			if name == "backlog.md" {
+				sev = Error
+			}
+			c.addFix(sev, "layout", fmt.Sprintf("%s is missing", name), "recreate it from the template", func() error {
+				return os.WriteFile(path, []byte(content), 0644)
+			})
+		}
+	}
+
+	if path := c.path("activity.jsonl"); !fileExists(path) {
+		c.addFix(Warning, "layout", "activity.jsonl is missing", "create an empty log", func() error {
+			return os.WriteFile(path, []byte{}, 0644)
+		})
+	}
+}
+
+// checkState cross-checks state.json against itself and tasks/
+func (c *checker) checkState() {
+	statePath := c.path("state.json")
+	if !fileExists(statePath) {
+		c.addFix(Error, "state", "state.json is missing", "create a fresh state.json (stage NONE)", func() error {
+			return state.NewState().SaveTo(c.yoDir)
+		})
+		return
+	}
+
+	version, err := state.ReadVersion(c.yoDir)
+	if err != nil {
+		c.addCorruptState(err)
+		return
+	}
+	if state.CompareVersions(version, state.SchemaVersion) > 0 {
+		c.add(Error, "state", fmt.Sprintf("schema %s is newer than this yo supports (%s); upgrade yo", version, state.SchemaVersion))
+		return
+	}
+	if state.CompareVersions(version, state.SchemaVersion) < 0 {
+		c.addFix(Warning, "state", fmt.Sprintf("schema %s is older than %s", version, state.SchemaVersion), "run the migration (originals go to backups/)", func() error {
+			_, err := state.Migrate(c.yoDir, false)
+			return err
+		})
+	}
+
+	s, err := state.LoadFrom(c.yoDir)
+	if err != nil {
+		c.addCorruptState(err)
+		return
+	}
+
+	if !validStages[s.CurrentStage] {
+		next := "none"
+		if s.CurrentTaskID != "" {
+			next = "red"
+		}
+		c.addFix(Error, "state", fmt.Sprintf("unknown stage %q", s.CurrentStage), fmt.Sprintf("set the stage to %s", strings.ToUpper(next)), c.updateState(func(s *state.State) {
+			s.SetStage(next)
+		}))
+	}
+
+	if s.CurrentStage == "green" && s.Timer.StartedAt.IsZero() {
+		c.addFix(Error, "state", "stage is GREEN but the timer never started", "go back to YELLOW so 'yo go' restarts the timer", c.updateState(func(s *state.State) {
+			s.SetStage("yellow")
+			s.Timer = state.Timer{}
+		}))
+	}
+
+	if (s.CurrentStage == "none" || s.CurrentStage == "") && s.CurrentTaskID != "" {
+		id := s.CurrentTaskID
+		_, parked := s.Tasks[id]
+		hint, keep := "clear the leftover task", false
+		if !parked && fileExists(c.taskPath(id)) {
+			hint, keep = "park it as a RED task", true
+		}
+		c.addFix(Warning, "state", fmt.Sprintf("no active stage but task %s is still set", id), hint, c.updateState(func(s *state.State) {
+			if keep {
+				if s.Tasks == nil {
+					s.Tasks = make(map[string]state.TaskState)
+				}
+				s.Tasks[id] = state.TaskState{Stage: "red", ParkedAt: time.Now(), Repo: s.CurrentTaskRepo, BacklogID: s.CurrentBacklogID}
+			}
+			s.CurrentTaskID = ""
+			s.CurrentTaskRepo = ""
+			s.CurrentBacklogID = ""
+		}))
+	}
+
+	// Parked tasks and their files in tasks/
+	ids := make([]string, 0, len(s.Tasks))
+	for id := range s.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !fileExists(c.taskPath(id)) {
+			id := id
+			c.addFix(Error, "state", fmt.Sprintf("parked task %s has no tasks/%s.md", id, id), "forget the parked task", c.updateState(func(s *state.State) {
+				delete(s.Tasks, id)
+			}))
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(c.yoDir, "tasks", "*.md"))
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), ".md")
+		if _, ok := s.Tasks[id]; ok || id == s.CurrentTaskID {
+			continue
+		}
+		parkedAt := time.Now()
+		if info, err := os.Stat(file); err == nil {
+			parkedAt = info.ModTime()
+		}
+		c.addFix(Warning, "state", fmt.Sprintf("tasks/%s.md is not a parked task", id), "park it as a RED task", c.updateState(func(s *state.State) {
+			if s.Tasks == nil {
+				s.Tasks = make(map[string]state.TaskState)
+			}
+			s.Tasks[id] = state.TaskState{Stage: "red", ParkedAt: parkedAt}
+		}))
+	}
+}
+
+// addCorruptState reports an unreadable state.json. The fix restores the
+// newest backup, or starts fresh; the broken file is kept either way.
+func (c *checker) addCorruptState(err error) {
+	backup := c.latestBackup("state.json")
+	hint := "reset to a fresh state.json (stage NONE)"
+	if backup != "" {
+		hint = fmt.Sprintf("restore %s", relPath(c.yoDir, backup))
+	}
+	hint += "; the broken file is kept as state.json.corrupt"
+
+	c.addFix(Error, "state", err.Error(), hint, func() error {
+		statePath := c.path("state.json")
+		if err := os.Rename(statePath, statePath+".corrupt"); err != nil {
+			return err
+		}
+		if backup != "" {
+			data, err := os.ReadFile(backup)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(statePath, data, 0644)
+		}
+		return state.NewState().SaveTo(c.yoDir)
+	})
+}
+
+// checkConfig looks for an unreadable config.json or out-of-range values
+func (c *checker) checkConfig() {
+	configPath := c.path("config.json")
+	if !fileExists(configPath) {
+		c.addFix(Info, "config", "config.json is missing, defaults are used", "write the defaults", func() error {
+			return config.Default().SaveTo(c.yoDir)
+		})
+		return
+	}
+
+	cfg, err := config.LoadFrom(c.yoDir)
+	if err != nil {
+		c.addFix(Error, "config", err.Error(), "write the defaults; the broken file is kept as config.json.corrupt", func() error {
+			if err := os.Rename(configPath, configPath+".corrupt"); err != nil {
+				return err
+			}
+			return config.Default().SaveTo(c.yoDir)
+		})
+		return
+	}
+
+	defaults := config.Default()
+	var bad []string
+	if cfg.BypassMinutes <= 0 {
+		bad = append(bad, "bypass_minutes")
+		cfg.BypassMinutes = defaults.BypassMinutes
+	}
+	if cfg.MaxBypassDay < 0 {
+		bad = append(bad, "max_bypass_day")
+		cfg.MaxBypassDay = defaults.MaxBypassDay
+	}
+	if cfg.MaxBypassWeek < 0 {
+		bad = append(bad, "max_bypass_week")
+		cfg.MaxBypassWeek = defaults.MaxBypassWeek
+	}
+	if cfg.MaxExtensions < 0 {
+		bad = append(bad, "max_extensions")
+		cfg.MaxExtensions = defaults.MaxExtensions
+	}
+	if cfg.MaxExtensionHours < 0 {
+		bad = append(bad, "max_extension_hours")
+		cfg.MaxExtensionHours = defaults.MaxExtensionHours
+	}
+	if cfg.CriteriaTimeout <= 0 {
+		bad = append(bad, "criteria_timeout")
+		cfg.CriteriaTimeout = defaults.CriteriaTimeout
+	}
+	if cfg.DebtBudgetHours < 0 {
+		bad = append(bad, "debt_budget_hours")
+		cfg.DebtBudgetHours = defaults.DebtBudgetHours
+	}
+	if len(bad) > 0 {
+		c.addFix(Warning, "config", fmt.Sprintf("invalid %s", strings.Join(bad, ", ")), "reset them to the defaults", func() error {
+			return cfg.SaveTo(c.yoDir)
+		})
+	}
+}
+
+// checkTask makes sure the active task has its file in tasks/ and an ID
+// that matches its problem. It also picks up current_task.md from
+// workspaces older than tasks/<id>.md.
+func (c *checker) checkTask() {
+	s, err := state.LoadFrom(c.yoDir)
+	if err != nil || !validStages[s.CurrentStage] {
+		return
+	}
+
+	legacy := c.path("current_task.md")
+	usedLegacy := false
+	switch s.CurrentStage {
+	case "red", "yellow", "green":
+		usedLegacy = c.checkActiveTask(s, legacy)
+	}
+
+	if !usedLegacy && fileExists(legacy) {
+		backup := filepath.Join(c.yoDir, "backups", fmt.Sprintf("current_task-%s.md", time.Now().Format("20060102-150405")))
+		c.addFix(Warning, "task", "current_task.md is left over from an older yo; tasks live in tasks/<id>.md", "move it to backups/", func() error {
+			if err := os.MkdirAll(filepath.Dir(backup), 0755); err != nil {
+				return err
+			}
+			return os.Rename(legacy, backup)
+		})
+	}
+}
+
+// checkActiveTask checks the active task's file, reporting whether a fix
+// takes over the legacy current_task.md
+func (c *checker) checkActiveTask(s *state.State, legacy string) bool {
+	if s.CurrentTaskID == "" {
+		t, err := task.Load(legacy)
+		if err != nil {
+			c.addFix(Error, "task", fmt.Sprintf("stage is %s but no task is set", strings.ToUpper(s.CurrentStage)), "go back to stage NONE; start over with 'yo red'", c.updateState(func(s *state.State) {
+				s.SetStage("none")
+			}))
+			return false
+		}
+		problem := strings.TrimSpace(t.Red.Problem)
+		if problem == "" {
+			c.add(Warning, "task", fmt.Sprintf("stage is %s but current_task.md has no problem; start over with 'yo red'", strings.ToUpper(s.CurrentStage)))
+			return true
+		}
+		id := task.MakeID(problem, s.CurrentBacklogID)
+		c.addFix(Warning, "task", fmt.Sprintf("stage is %s but no task ID is set", strings.ToUpper(s.CurrentStage)), fmt.Sprintf("set the task ID to %s and move current_task.md to tasks/%s.md", id, id), c.moveTask(legacy, id, ""))
+		return true
+	}
+
+	path := c.taskPath(s.CurrentTaskID)
+	t, err := task.Load(path)
+	if err != nil {
+		id := s.CurrentTaskID
+		if fileExists(legacy) {
+			c.addFix(Error, "task", fmt.Sprintf("task %s has no tasks/%s.md", id, id), fmt.Sprintf("move current_task.md to tasks/%s.md", id), func() error {
+				return os.Rename(legacy, path)
+			})
+			return true
+		}
+		c.addFix(Error, "task", fmt.Sprintf("task %s has no tasks/%s.md", id, id), "recreate it from the template", func() error {
+			return os.WriteFile(path, []byte(templates.Task), 0644)
+		})
+		return false
+	}
+
+	problem := strings.TrimSpace(t.Red.Problem)
+	if problem == "" {
+		c.add(Warning, "task", fmt.Sprintf("stage is %s but tasks/%s.md has no problem; fill it in with 'yo red'", strings.ToUpper(s.CurrentStage), s.CurrentTaskID))
+		return false
+	}
+
+	if slug := task.Slugify(problem); slug != "" && !strings.HasPrefix(s.CurrentTaskID, slug) {
+		suffix := task.IDSuffix(s.CurrentTaskID)
+		if suffix == "" {
+			suffix = s.CurrentBacklogID
+		}
+		id := task.MakeID(problem, suffix)
+		old := s.CurrentTaskID
+		c.addFix(Warning, "task", fmt.Sprintf("task ID %s doesn't match tasks/%s.md (%q)", old, old, problem), fmt.Sprintf("rename the task to %s", id), c.moveTask(path, id, old))
+	}
+	return false
+}
+
+// moveTask returns a fix that moves a task file to tasks/<id>.md and
+// makes id the active task, if the active task is still old
+func (c *checker) moveTask(from, id, old string) func() error {
+	return func() error {
+		to := c.taskPath(id)
+		if fileExists(to) {
+			return fmt.Errorf("tasks/%s.md already exists", id)
+		}
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		return c.updateState(func(s *state.State) {
+			if s.CurrentTaskID == old {
+				s.CurrentTaskID = id
+			}
+		})()
+	}
+}
+
+// checkBacklog looks for duplicate IDs and a dangling link from the task
+func (c *checker) checkBacklog() {
+	path := c.path("backlog.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return // reported by checkLayout
+	}
+	b := backlog.Parse(string(data), path)
+
+	if dups := b.DuplicateIDs(); len(dups) > 0 {
+		c.addFix(Error, "backlog", fmt.Sprintf("duplicate item IDs: %s", strings.Join(dups, ", ")), "give the later items new IDs", func() error {
+			_, err := b.FixDuplicateIDs()
+			return err
+		})
+	}
+
+	s, err := state.LoadFrom(c.yoDir)
+	if err != nil || s.CurrentBacklogID == "" {
+		return
+	}
+	if _, ok := b.Find(s.CurrentBacklogID); !ok {
+		id := s.CurrentBacklogID
+		c.addFix(Warning, "backlog", fmt.Sprintf("task links to backlog item %s, which no longer exists", id), "drop the link", c.updateState(func(s *state.State) {
+			if s.CurrentBacklogID == id {
+				s.CurrentBacklogID = ""
+			}
+		}))
+	}
+}
+
+// checkActivity looks for log lines Query would skip
+func (c *checker) checkActivity() {
+	bad, err := activity.Malformed(c.yoDir)
+	if err != nil {
+		c.add(Error, "activity", err.Error())
+		return
+	}
+	if len(bad) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(bad))
+	for i, n := range bad {
+		if i == 5 {
+			lines = append(lines, "...")
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%d", n))
+	}
+	c.addFix(Warning, "activity", fmt.Sprintf("%d malformed line(s) in activity.jsonl (line %s)", len(bad), strings.Join(lines, ", ")), "move them to activity.malformed.jsonl", func() error {
+		_, err := activity.RemoveMalformed(c.yoDir)
+		return err
+	})
+}
+
+// checkWatcher looks for a stale PID file and an unregistered workspace
+func (c *checker) checkWatcher() {
+	if pidPath, pid, stale := watcher.StalePidFile(); stale {
+		c.addFix(Warning, "watcher", fmt.Sprintf("stale PID file (process %d is not running)", pid), "remove "+pidPath, func() error {
+			return os.Remove(pidPath)
+		})
+	}
+
+	projectDir := filepath.Dir(c.yoDir)
+	r, err := registry.Load()
+	if err != nil {
+		c.add(Warning, "watcher", err.Error())
+		return
+	}
+	if _, ok := r.Find(projectDir); !ok {
+		c.addFix(Info, "watcher", "workspace is not registered, so the watcher and 'yo status --all' skip it", "register it", func() error {
+			return registry.Register(projectDir)
+		})
+	}
+}
+
+// updateState returns a fix that edits state.json under its lock
+func (c *checker) updateState(fn func(*state.State)) func() error {
+	return func() error {
+		_, err := state.UpdateIn(c.yoDir, func(s *state.State) error {
+			fn(s)
+			return nil
+		})
+		return err
+	}
+}
+
+// latestBackup returns the newest backups/*/name, or ""
+func (c *checker) latestBackup(name string) string {
+	matches, _ := filepath.Glob(filepath.Join(c.yoDir, "backups", "*", name))
+	if len(matches) == 0 {
+		return ""
+	}
+	sort.Strings(matches) // backup dirs start with a timestamp
+	return matches[len(matches)-1]
+}
+
+// taskPath returns the file of the task with id, tasks/<id>.md
+func (c *checker) taskPath(id string) string {
+	return filepath.Join(c.yoDir, "tasks", id+".md")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func relPath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,122 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/faisalahmedsifat/yo/internal/state"
+)
+
+// Severity ranks how much a problem matters
+type Severity int
+
+const (
+	Info    Severity = iota // harmless, worth knowing
+	Warning                 // something is off but yo still works
+	Error                   // yo misbehaves until it is fixed
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// Finding is one problem found in the workspace
+type Finding struct {
+	Severity Severity
+	Area     string // what was checked: layout, state, config, task, backlog, activity, watcher
+	Message  string
+	FixHint  string // what Fix does; empty when it must be fixed by hand
+
+	fix func() error
+}
+
+// Fixable reports whether Fix can repair the problem
+func (f Finding) Fixable() bool {
+	return f.fix != nil
+}
+
+// Fix repairs the problem
+func (f Finding) Fix() error {
+	if f.fix == nil {
+		return fmt.Errorf("%s cannot be fixed automatically", f.Area)
+	}
+	return f.fix()
+}
+
+// Report is the result of checking a workspace
+type Report struct {
+	YoDir    string
+	Findings []Finding
+}
+
+// Count returns how many findings have the given severity
+func (r *Report) Count(sev Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+// Fixable returns how many findings Fix can repair
+func (r *Report) Fixable() int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Fixable() {
+			n++
+		}
+	}
+	return n
+}
+
+// OK reports whether nothing worse than Info was found
+func (r *Report) OK() bool {
+	return r.Count(Error) == 0 && r.Count(Warning) == 0
+}
+
+// Run checks the workspace in yoDir. Findings come in an order that is
+// safe to fix in: layout first, then state, then everything else.
+func Run(yoDir string) (*Report, error) {
+	if _, err := os.Stat(yoDir); err != nil {
+		return nil, state.ErrNotInitialized
+	}
+
+	c := &checker{yoDir: yoDir}
+	c.checkLayout()
+	c.checkState()
+	c.checkConfig()
+	c.checkTask()
+	c.checkBacklog()
+	c.checkActivity()
+	c.checkWatcher()
+
+	return &Report{YoDir: yoDir, Findings: c.findings}, nil
+}
+
+// checker collects findings for one workspace
+type checker struct {
+	yoDir    string
+	findings []Finding
+}
+
+func (c *checker) add(sev Severity, area, message string) {
+	c.findings = append(c.findings, Finding{Severity: sev, Area: area, Message: message})
+}
+
+func (c *checker) addFix(sev Severity, area, message, hint string, fix func() error) {
+	c.findings = append(c.findings, Finding{Severity: sev, Area: area, Message: message, FixHint: hint, fix: fix})
+}
+
+func (c *checker) path(name string) string {
+	return filepath.Join(c.yoDir, name)
+}
@@ -0,0 +1,227 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/faisalahmedsifat/yo/internal/registry"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/workspace"
+)
+
+// newWorkspace initializes a registered workspace under a temp HOME
+func newWorkspace(t *testing.T) string {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	yoDir := filepath.Join(t.TempDir(), ".yo")
+	t.Setenv(state.YoDirEnv, yoDir)
+
+	if err := workspace.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if err := registry.Register(filepath.Dir(yoDir)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return yoDir
+}
+
+func findingFor(r *Report, area, substr string) (Finding, bool) {
+	for _, f := range r.Findings {
+		if f.Area == area && strings.Contains(f.Message, substr) {
+			return f, true
+		}
+	}
+	return Finding{}, false
+}
+
+func TestRunCleanWorkspace(t *testing.T) {
+	yoDir := newWorkspace(t)
+
+	report, err := Run(yoDir)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, f := range report.Findings {
+		t.Errorf("Unexpected finding: [%s] %s", f.Area, f.Message)
+	}
+}
+
+func TestRunAndFix(t *testing.T) {
+	yoDir := newWorkspace(t)
+
+	// Break the workspace in several independent ways
+	os.RemoveAll(filepath.Join(yoDir, "sessions"))
+	os.Remove(filepath.Join(yoDir, "activity.jsonl"))
+	os.WriteFile(filepath.Join(yoDir, "tasks", "orphan_task_abc123.md"), []byte("# Task\n"), 0644)
+	os.WriteFile(filepath.Join(yoDir, "backlog.md"),
+		[]byte("## P0 - Launch Blockers\n- [ ] First <!-- id:a1b2c3 -->\n- [ ] Second <!-- id:a1b2c3 -->\n"), 0644)
+	os.WriteFile(filepath.Join(yoDir, "tasks", "fix_login_ffffff.md"),
+		[]byte("## 🔴 RED LIGHT - Problem Definition\n\n### What's the Problem?\nFix login\n"), 0644)
+
+	if _, err := state.UpdateIn(yoDir, func(s *state.State) error {
+		s.CurrentStage = "purple"
+		s.CurrentTaskID = "fix_login_ffffff"
+		s.Tasks = map[string]state.TaskState{"missing_task_123456": {Stage: "red"}}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateIn failed: %v", err)
+	}
+
+	report, err := Run(yoDir)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	expected := []struct {
+		area, substr string
+		sev          Severity
+	}{
+		{"layout", "sessions/", Warning},
+		{"layout", "activity.jsonl", Warning},
+		{"state", `"purple"`, Error},
+		{"state", "missing_task_123456", Error},
+		{"state", "orphan_task_abc123", Warning},
+		{"backlog", "a1b2c3", Error},
+	}
+	for _, e := range expected {
+		f, ok := findingFor(report, e.area, e.substr)
+		if !ok {
+			t.Errorf("Expected a %s finding about %q", e.area, e.substr)
+			continue
+		}
+		if f.Severity != e.sev {
+			t.Errorf("Finding %q: expected %s, got %s", f.Message, e.sev, f.Severity)
+		}
+		if !f.Fixable() {
+			t.Errorf("Finding %q should be fixable", f.Message)
+		}
+	}
+	if report.OK() {
+		t.Error("Broken workspace should not be OK")
+	}
+
+	for _, f := range report.Findings {
+		if err := f.Fix(); err != nil {
+			t.Errorf("Fix for %q failed: %v", f.Message, err)
+		}
+	}
+
+	after, err := Run(yoDir)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, f := range after.Findings {
+		t.Errorf("Finding left after fixing: [%s] %s", f.Area, f.Message)
+	}
+
+	s, err := state.LoadFrom(yoDir)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if s.CurrentStage != "red" {
+		t.Errorf("Expected stage red, got %s", s.CurrentStage)
+	}
+	if _, ok := s.Tasks["orphan_task_abc123"]; !ok {
+		t.Error("Orphan task file should have been adopted")
+	}
+	if _, ok := s.Tasks["missing_task_123456"]; ok {
+		t.Error("Parked task without a file should have been dropped")
+	}
+}
+
+func TestFixLegacyTaskFile(t *testing.T) {
+	yoDir := newWorkspace(t)
+
+	// A task from before tasks/<id>.md that the migration couldn't name
+	os.WriteFile(filepath.Join(yoDir, "current_task.md"),
+		[]byte("## 🔴 RED LIGHT - Problem Definition\n\n### What's the Problem?\nFix signup\n"), 0644)
+	if _, err := state.UpdateIn(yoDir, func(s *state.State) error {
+		s.SetStage("yellow")
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateIn failed: %v", err)
+	}
+
+	report, err := Run(yoDir)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	f, ok := findingFor(report, "task", "no task ID")
+	if !ok {
+		t.Fatalf("Expected a finding about the missing task ID, got %+v", report.Findings)
+	}
+	if err := f.Fix(); err != nil {
+		t.Fatalf("Fix failed: %v", err)
+	}
+
+	s, err := state.LoadFrom(yoDir)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if !strings.HasPrefix(s.CurrentTaskID, "fix_signup_") {
+		t.Fatalf("Expected a fix_signup task ID, got %q", s.CurrentTaskID)
+	}
+	if _, err := os.Stat(filepath.Join(yoDir, "tasks", s.CurrentTaskID+".md")); err != nil {
+		t.Error("Expected current_task.md to move to tasks/<id>.md")
+	}
+	if _, err := os.Stat(filepath.Join(yoDir, "current_task.md")); err == nil {
+		t.Error("Expected current_task.md to be gone")
+	}
+
+	after, err := Run(yoDir)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for _, f := range after.Findings {
+		t.Errorf("Finding left after fixing: [%s] %s", f.Area, f.Message)
+	}
+}
+
+func TestFixCorruptState(t *testing.T) {
+	yoDir := newWorkspace(t)
+	statePath := filepath.Join(yoDir, "state.json")
+	os.WriteFile(statePath, []byte("{not json"), 0644)
+
+	report, err := Run(yoDir)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	f, ok := findingFor(report, "state", "parse")
+	if !ok || f.Severity != Error {
+		t.Fatalf("Expected a state parse error, got %+v", report.Findings)
+	}
+	if err := f.Fix(); err != nil {
+		t.Fatalf("Fix failed: %v", err)
+	}
+
+	if _, err := os.Stat(statePath + ".corrupt"); err != nil {
+		t.Error("Broken state.json should be kept as state.json.corrupt")
+	}
+	s, err := state.LoadFrom(yoDir)
+	if err != nil {
+		t.Fatalf("State should load after the fix: %v", err)
+	}
+	if s.CurrentStage != "none" {
+		t.Errorf("Expected a fresh state, got stage %s", s.CurrentStage)
+	}
+}
+
+func TestNewerSchemaIsNotFixable(t *testing.T) {
+	yoDir := newWorkspace(t)
+	os.WriteFile(filepath.Join(yoDir, "state.json"), []byte(`{"version":"99.0.0","current_stage":"none"}`), 0644)
+
+	report, err := Run(yoDir)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	f, ok := findingFor(report, "state", "newer")
+	if !ok {
+		t.Fatalf("Expected a newer-schema finding, got %+v", report.Findings)
+	}
+	if f.Fixable() {
+		t.Error("A newer schema must not be fixed automatically")
+	}
+}
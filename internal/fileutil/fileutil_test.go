@@ -0,0 +1,110 @@
+package fileutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWriteFileReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	if err := WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := WriteFile(path, []byte("new"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "new" {
+		t.Errorf("Expected new content, got %q", data)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// No temp files are left behind
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only state.json, got %d entries", len(entries))
+	}
+}
+
+func TestLockSerialisesReadModifyWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	os.WriteFile(path, []byte("0"), 0644)
+
+	const workers, rounds = 8, 25
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				unlock, err := Lock(path)
+				if err != nil {
+					t.Errorf("Lock failed: %v", err)
+					return
+				}
+				data, _ := os.ReadFile(path)
+				n, _ := strconv.Atoi(string(data))
+				WriteFile(path, []byte(strconv.Itoa(n+1)), 0644)
+				unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != strconv.Itoa(workers*rounds) {
+		t.Errorf("Expected counter %d, got %s (lost updates)", workers*rounds, data)
+	}
+}
+
+func TestAppendLineConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.jsonl")
+
+	const workers, rounds = 8, 50
+	// Long lines make interleaved partial writes easy to spot
+	padding := strings.Repeat("x", 4096)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				line := fmt.Sprintf("%d-%d-%s-end", w, r, padding)
+				if err := AppendLine(path, []byte(line)); err != nil {
+					t.Errorf("AppendLine failed: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != workers*rounds {
+		t.Fatalf("Expected %d lines, got %d", workers*rounds, len(lines))
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		if !strings.HasSuffix(line, padding+"-end") {
+			t.Fatalf("Corrupted line: %.40q", line)
+		}
+		seen[line] = true
+	}
+	if len(seen) != workers*rounds {
+		t.Errorf("Expected %d distinct lines, got %d", workers*rounds, len(seen))
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package fileutil
+
+import "os"
+
+// Advisory locking is only implemented on Unix; elsewhere writes are still
+// atomic but concurrent read-modify-write cycles are not serialised.
+
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
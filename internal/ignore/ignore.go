@@ -0,0 +1,184 @@
+// Package ignore matches paths against .gitignore files, so walks of a
+// repository skip what git skips.
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Matcher holds the rules of the .gitignore files added so far
+type Matcher struct {
+	rules []rule
+}
+
+type rule struct {
+	base    string // directory of the .gitignore, slash-separated, "" for the root
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	name    bool // no slash in the pattern: match the name at any depth
+}
+
+// AddFile adds the rules of the ignore file at file, which apply below
+// dir (relative to the root). A missing file adds nothing.
+func (m *Matcher) AddFile(dir, file string) error {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dir = filepath.ToSlash(dir)
+	if dir == "." {
+		dir = ""
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m.Add(dir, scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// Add adds one .gitignore line for paths below dir
+func (m *Matcher) Add(dir, line string) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	r := rule{base: dir}
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	r.name = !strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return
+	}
+
+	re, err := regexp.Compile("^" + globToRegexp(line) + "$")
+	if err != nil {
+		return
+	}
+	r.re = re
+	m.rules = append(m.rules, r)
+}
+
+// Ignored reports whether the slash-separated path relative to the root
+// is ignored. The last matching rule wins, as in git.
+func (m *Matcher) Ignored(rel string, isDir bool) bool {
+	rel = filepath.ToSlash(rel)
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		sub := rel
+		if r.base != "" {
+			if !strings.HasPrefix(rel, r.base+"/") {
+				continue
+			}
+			sub = strings.TrimPrefix(rel, r.base+"/")
+		}
+		if r.name {
+			sub = path.Base(sub)
+		}
+		if r.re.MatchString(sub) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// globToRegexp translates gitignore wildcards: * and ? stay within a path
+// segment, ** crosses them
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Walk calls fn for every file below root that isn't ignored, reading each
+// directory's .gitignore (and the root's .git/info/exclude) on the way.
+// The .git directory is always skipped. Paths given to fn are relative to
+// root.
+func Walk(root string, skipDirs []string, fn func(rel string) error) error {
+	m := &Matcher{}
+	if err := m.AddFile("", filepath.Join(root, ".git", "info", "exclude")); err != nil {
+		return err
+	}
+
+	skip := map[string]bool{".git": true}
+	for _, d := range skipDirs {
+		skip[d] = true
+	}
+
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			return nil // unreadable: skip it
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if rel != "." && (skip[d.Name()] || m.Ignored(rel, true)) {
+				return filepath.SkipDir
+			}
+			return m.AddFile(rel, filepath.Join(p, ".gitignore"))
+		}
+		if !d.Type().IsRegular() || m.Ignored(rel, false) {
+			return nil
+		}
+		return fn(filepath.ToSlash(rel))
+	})
+}
@@ -0,0 +1,86 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestIgnored(t *testing.T) {
+	m := &Matcher{}
+	for _, line := range []string{"# comment", "*.log", "!keep.log", "build/", "/root-only.txt", "docs/**/*.tmp", "a?c"} {
+		m.Add("", line)
+	}
+	m.Add("sub", "local.txt")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"debug.log", false, true},
+		{"deep/dir/debug.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"src/build", true, true},
+		{"root-only.txt", false, true},
+		{"src/root-only.txt", false, false},
+		{"docs/x.tmp", false, true},
+		{"docs/a/b/x.tmp", false, true},
+		{"abc", false, true},
+		{"abbc", false, false},
+		{"sub/local.txt", false, true},
+		{"sub/deeper/local.txt", false, true},
+		{"local.txt", false, false},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Ignored(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Ignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":          "node_modules/\n*.gen.go\n",
+		"main.go":             "",
+		"api.gen.go":          "",
+		"node_modules/x.js":   "",
+		"pkg/.gitignore":      "secret.txt\n",
+		"pkg/secret.txt":      "",
+		"pkg/util.go":         "",
+		".git/HEAD":           "",
+		".yo/state.json":      "",
+		"other/secret.txt":    "",
+		"other/nested/ok.txt": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var seen []string
+	err := Walk(root, []string{".yo"}, func(rel string) error {
+		seen = append(seen, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	sort.Strings(seen)
+
+	want := []string{".gitignore", "main.go", "other/nested/ok.txt", "other/secret.txt", "pkg/.gitignore", "pkg/util.go"}
+	if strings.Join(seen, ",") != strings.Join(want, ",") {
+		t.Errorf("Walk saw %v, want %v", seen, want)
+	}
+}
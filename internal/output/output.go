@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/faisalahmedsifat/yo/internal/yaml"
+)
+
+// Format is how a command prints its result
+type Format string
+
+const (
+	Text Format = "text" // human-readable, the default
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+// ParseFormat parses the value of --output
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "", Text:
+		return Text, nil
+	case JSON, YAML:
+		return f, nil
+	}
+	return Text, fmt.Errorf("invalid output format %q (use text, json or yaml)", s)
+}
+
+// Structured reports whether the format is meant for machines
+func (f Format) Structured() bool {
+	return f == JSON || f == YAML
+}
+
+// Write encodes v to w as JSON or YAML
+func Write(w io.Writer, f Format, v interface{}) error {
+	var data []byte
+	var err error
+	switch f {
+	case JSON:
+		data, err = MarshalJSON(v)
+	case YAML:
+		data, err = yaml.Marshal(v)
+	default:
+		return fmt.Errorf("output format %s is not structured", f)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// MarshalJSON encodes v as indented JSON with a trailing newline
+func MarshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Error is the structured form of a failed command
+type Error struct {
+	Code     string `json:"code"` // stable, e.g. not_initialized, usage, error
+	Message  string `json:"message"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// WriteError encodes err as {"error": {...}}
+func WriteError(w io.Writer, f Format, e Error) error {
+	return Write(w, f, struct {
+		Error Error `json:"error"`
+	}{e})
+}
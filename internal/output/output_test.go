@@ -0,0 +1,87 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/backlog"
+	"github.com/faisalahmedsifat/yo/internal/state"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected Format
+		wantErr  bool
+	}{
+		{"", Text, false},
+		{"text", Text, false},
+		{"JSON", JSON, false},
+		{"yaml", YAML, false},
+		{"xml", Text, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("ParseFormat(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteError(&buf, JSON, Error{Code: "usage", Message: "bad flag", ExitCode: 1}); err != nil {
+		t.Fatalf("WriteError failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"code": "usage"`) {
+		t.Errorf("Unexpected error object: %s", buf.String())
+	}
+
+	if err := Write(&buf, Text, nil); err == nil {
+		t.Error("Expected an error writing text as structured output")
+	}
+}
+
+func TestNewBacklogItem(t *testing.T) {
+	now := time.Date(2026, 11, 5, 12, 0, 0, 0, time.UTC)
+	b := backlog.Parse("## P1 - Paying User Blockers\n- [ ] Fix login #auth ~2h due:2026-11-01 @sam <!-- id:a1b2c3 -->\n- [ ] Plain\n", "backlog.md")
+
+	item := NewBacklogItem(b.Items[backlog.P1][0], 1, now)
+	if item.ID != "a1b2c3" || item.Title != "Fix login" || item.Due != "2026-11-01" || !item.Overdue {
+		t.Errorf("Unexpected item: %+v", item)
+	}
+	if item.EstimateHours != 2 || item.Assignee != "sam" || len(item.Tags) != 1 {
+		t.Errorf("Unexpected metadata: %+v", item)
+	}
+
+	plain := NewBacklogItem(b.Items[backlog.P1][1], 2, now)
+	if plain.Tags == nil || plain.Due != "" || plain.Overdue {
+		t.Errorf("Unexpected plain item: %+v", plain)
+	}
+}
+
+func TestNewStatus(t *testing.T) {
+	s := state.NewState()
+	s.SetStage("green")
+	s.CurrentTaskID = "fix_login_a1b2c3"
+	s.StartTimer(2)
+
+	status := NewStatus(s, "/work/api")
+	if status.Stage != "green" || status.TaskID != "fix_login_a1b2c3" || status.Workspace != "/work/api" {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if status.Timer == nil || !status.Timer.Running || status.Timer.ThresholdHours == 0 {
+		t.Errorf("Expected a running timer, got %+v", status.Timer)
+	}
+	if status.Bypass != nil || status.Parked == nil {
+		t.Errorf("Expected no bypass and an empty parked list: %+v", status)
+	}
+
+	s.SetStage("red")
+	if NewStatus(s, "").Timer != nil {
+		t.Error("Timer should be null outside GREEN")
+	}
+}
@@ -0,0 +1,451 @@
+package output
+
+import (
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/backlog"
+	"github.com/faisalahmedsifat/yo/internal/debt"
+	"github.com/faisalahmedsifat/yo/internal/state"
+	"github.com/faisalahmedsifat/yo/internal/stats"
+	"github.com/faisalahmedsifat/yo/internal/task"
+	"github.com/faisalahmedsifat/yo/internal/timer"
+)
+
+// The types below are the documented --output schemas. Fields may be
+// added, but existing ones are never renamed, removed or retyped.
+// Durations are whole seconds, times are RFC 3339 and dates YYYY-MM-DD.
+
+// Timer is the schema of a running GREEN timer, built from timer.Status
+type Timer struct {
+	Running         bool    `json:"running"`
+	Paused          bool    `json:"paused"`
+	ElapsedSeconds  int64   `json:"elapsed_seconds"`
+	ElapsedHours    float64 `json:"elapsed_hours"`
+	ThresholdHours  float64 `json:"threshold_hours"`
+	Progress        float64 `json:"progress"` // percent of the threshold
+	Extensions      int     `json:"extensions"`
+	OvertimeSeconds int64   `json:"overtime_seconds"`
+}
+
+// NewTimer converts a timer status
+func NewTimer(st *timer.Status) *Timer {
+	return &Timer{
+		Running:         st.Running,
+		Paused:          st.Paused,
+		ElapsedSeconds:  seconds(st.Elapsed),
+		ElapsedHours:    st.ElapsedHours,
+		ThresholdHours:  st.ThresholdHours,
+		Progress:        st.Progress,
+		Extensions:      st.Extensions,
+		OvertimeSeconds: seconds(st.Overtime),
+	}
+}
+
+// Bypass is the schema of an active emergency bypass
+type Bypass struct {
+	Reason           string    `json:"reason"`
+	StartedAt        time.Time `json:"started_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	Expired          bool      `json:"expired"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+	PreviousStage    string    `json:"previous_stage"`
+}
+
+// NewBypass returns the active bypass, or nil outside the bypass stage
+func NewBypass(s *state.State) *Bypass {
+	if s.CurrentStage != "bypass" {
+		return nil
+	}
+	return &Bypass{
+		Reason:           s.Bypass.Reason,
+		StartedAt:        s.Bypass.StartedAt,
+		ExpiresAt:        s.BypassExpiresAt(),
+		Expired:          s.BypassExpired(),
+		RemainingSeconds: seconds(s.BypassRemaining()),
+		PreviousStage:    s.Bypass.PreviousStage,
+	}
+}
+
+// ParkedTask is the schema of a task set aside with 'yo park'
+type ParkedTask struct {
+	ID        string    `json:"id"`
+	Stage     string    `json:"stage"`
+	ParkedAt  time.Time `json:"parked_at"`
+	Repo      string    `json:"repo"`
+	BacklogID string    `json:"backlog_id"`
+}
+
+// Session is the schema of an active work session
+type Session struct {
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds int64     `json:"duration_seconds"`
+}
+
+// BypassCounts is how many emergency bypasses were used
+type BypassCounts struct {
+	Today    int `json:"today"`
+	ThisWeek int `json:"this_week"`
+}
+
+// Status is the schema of 'yo status'
+type Status struct {
+	Workspace         string       `json:"workspace"` // project directory
+	Stage             string       `json:"stage"`     // none, red, yellow, green or bypass
+	TaskID            string       `json:"task_id"`
+	TaskFile          string       `json:"task_file"` // the task's .yo/tasks/<id>.md; empty without a task
+	Repo              string       `json:"repo"`
+	BacklogID         string       `json:"backlog_id"`
+	Timer             *Timer       `json:"timer"`   // null unless the GREEN timer started
+	Bypass            *Bypass      `json:"bypass"`  // null outside the bypass stage
+	Session           *Session     `json:"session"` // null without an active session
+	Parked            []ParkedTask `json:"parked"`
+	EmergencyBypasses BypassCounts `json:"emergency_bypasses"`
+	Criteria          *CriteriaMet `json:"criteria"` // null without a current task
+	DebtDue           []DebtDue    `json:"debt_due"` // open tech debt whose trigger fired
+}
+
+// CriteriaMet is how many of the current task's success criteria are ticked
+type CriteriaMet struct {
+	Met   int `json:"met"`
+	Total int `json:"total"`
+}
+
+// Criterion is the schema of a success criterion in 'yo criteria'
+type Criterion struct {
+	Number    int        `json:"number"`
+	Text      string     `json:"text"`
+	Command   string     `json:"command"` // empty for criteria checked by hand
+	Checked   bool       `json:"checked"`
+	CheckedAt *time.Time `json:"checked_at"` // null unless ticked with 'yo check'
+}
+
+// NewCriteria converts a task's success criteria
+func NewCriteria(t *task.Task) []Criterion {
+	criteria := []Criterion{}
+	for i, c := range t.Yellow.Criteria {
+		item := Criterion{Number: i + 1, Text: c.Text, Command: c.Command, Checked: c.Checked}
+		if !c.CheckedAt.IsZero() {
+			at := c.CheckedAt
+			item.CheckedAt = &at
+		}
+		criteria = append(criteria, item)
+	}
+	return criteria
+}
+
+// NewStatus builds the status of the workspace in projectDir
+func NewStatus(s *state.State, projectDir string) *Status {
+	status := &Status{
+		Workspace: projectDir,
+		Stage:     s.CurrentStage,
+		TaskID:    s.CurrentTaskID,
+		Repo:      s.CurrentTaskRepo,
+		BacklogID: s.CurrentBacklogID,
+		Timer:     timerOf(s),
+		Bypass:    NewBypass(s),
+		Parked:    []ParkedTask{},
+		DebtDue:   []DebtDue{},
+		EmergencyBypasses: BypassCounts{
+			Today:    s.EmergencyBypasses.Today,
+			ThisWeek: s.EmergencyBypasses.ThisWeek,
+		},
+	}
+
+	for _, id := range s.ParkedTaskIDs() {
+		t := s.Tasks[id]
+		status.Parked = append(status.Parked, ParkedTask{
+			ID:        id,
+			Stage:     t.Stage,
+			ParkedAt:  t.ParkedAt,
+			Repo:      t.Repo,
+			BacklogID: t.BacklogID,
+		})
+	}
+
+	if s.Session.Active {
+		status.Session = &Session{
+			StartedAt:       s.Session.StartedAt,
+			DurationSeconds: seconds(time.Since(s.Session.StartedAt)),
+		}
+	}
+	return status
+}
+
+// TimerReport is the schema of 'yo timer'
+type TimerReport struct {
+	Stage  string  `json:"stage"`
+	TaskID string  `json:"task_id"`
+	Timer  *Timer  `json:"timer"`  // null unless a GREEN timer runs
+	Bypass *Bypass `json:"bypass"` // null in GREEN
+}
+
+// NewTimerReport builds the report for 'yo timer'
+func NewTimerReport(s *state.State) *TimerReport {
+	return &TimerReport{
+		Stage:  s.CurrentStage,
+		TaskID: s.CurrentTaskID,
+		Timer:  timerOf(s),
+		Bypass: NewBypass(s),
+	}
+}
+
+// WorkspaceStatus is one workspace in 'yo status --all'
+type WorkspaceStatus struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Stage  string `json:"stage"` // empty when the workspace can't be read
+	TaskID string `json:"task_id"`
+	Timer  *Timer `json:"timer"`
+	Parked int    `json:"parked"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AllStatus is the schema of 'yo status --all'
+type AllStatus struct {
+	Workspaces []WorkspaceStatus `json:"workspaces"`
+}
+
+// BacklogItem is the schema of a backlog item, built from backlog.Item
+type BacklogItem struct {
+	ID            string   `json:"id"`
+	Priority      string   `json:"priority"` // P0 to P3
+	Position      int      `json:"position"` // 1-based within the priority, as in P1.2
+	Text          string   `json:"text"`     // as written, including inline metadata
+	Title         string   `json:"title"`    // text without inline metadata
+	Done          bool     `json:"done"`
+	DoneDate      string   `json:"done_date"`
+	Tags          []string `json:"tags"`
+	Estimate      string   `json:"estimate"`
+	EstimateHours float64  `json:"estimate_hours"`
+	Due           string   `json:"due"`
+	Overdue       bool     `json:"overdue"`
+	Assignee      string   `json:"assignee"`
+}
+
+// NewBacklogItem converts a backlog item at the given position
+func NewBacklogItem(item backlog.Item, position int, now time.Time) BacklogItem {
+	out := BacklogItem{
+		ID:            item.ID,
+		Priority:      item.Priority,
+		Position:      position,
+		Text:          item.Text,
+		Title:         item.Title(),
+		Done:          item.Checked,
+		DoneDate:      item.DoneDate,
+		Tags:          item.Tags,
+		Estimate:      item.Estimate,
+		EstimateHours: item.EstimateHours,
+		Overdue:       item.Overdue(now),
+		Assignee:      item.Assignee,
+	}
+	if out.Tags == nil {
+		out.Tags = []string{}
+	}
+	if !item.Due.IsZero() {
+		out.Due = item.Due.Format("2006-01-02")
+	}
+	return out
+}
+
+// BacklogList is the schema of 'yo list'
+type BacklogList struct {
+	Items []BacklogItem `json:"items"` // after filters, in display order
+	Total int           `json:"total"` // items in the backlog before filters
+}
+
+// DebtEntry is the schema of a tech debt entry, built from debt.Entry
+type DebtEntry struct {
+	ID            string  `json:"id"`
+	Task          string  `json:"task"`
+	What          string  `json:"what"`
+	Why           string  `json:"why"`
+	ComeBackWhen  string  `json:"come_back_when"`
+	Estimate      string  `json:"estimate"` // as written, e.g. 2h or TBD
+	EstimateHours float64 `json:"estimate_hours"`
+	Status        string  `json:"status"` // open, promoted or resolved
+	Created       string  `json:"created"`
+	Resolved      string  `json:"resolved"`
+	Resolution    string  `json:"resolution"`
+	BacklogID     string  `json:"backlog_id"` // set once promoted
+	Location      string  `json:"location"`   // file:line given to 'yo defer --at'
+	Marker        string  `json:"marker"`     // file:line of its yo:debt marker at the last scan
+}
+
+// NewDebtEntry converts a tech debt entry
+func NewDebtEntry(e debt.Entry) DebtEntry {
+	out := DebtEntry{
+		ID:            e.ID,
+		Task:          e.Task,
+		What:          e.What,
+		Why:           e.Why,
+		ComeBackWhen:  e.When,
+		Estimate:      e.Estimate,
+		EstimateHours: e.EstimateHours,
+		Status:        string(e.Status),
+		Created:       e.Created.Format("2006-01-02"),
+		Resolution:    e.Note,
+		BacklogID:     e.BacklogID,
+		Location:      e.Location,
+		Marker:        e.Marker,
+	}
+	if !e.Resolved.IsZero() {
+		out.Resolved = e.Resolved.Format("2006-01-02")
+	}
+	return out
+}
+
+// DebtList is the schema of 'yo debt list'
+type DebtList struct {
+	Items []DebtEntry `json:"items"` // oldest first
+	Total int         `json:"total"` // entries in the log, resolved included
+}
+
+// DebtMarker is a yo:debt or TODO(yo) comment found by 'yo debt scan'
+type DebtMarker struct {
+	ID   string `json:"id"` // empty for a TODO(yo) without one
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// DebtScan is the schema of 'yo debt scan'
+type DebtScan struct {
+	Linked   []DebtMarker `json:"linked"`   // markers of outstanding entries
+	Gone     []DebtEntry  `json:"gone"`     // outstanding entries whose marker disappeared
+	Orphans  []DebtMarker `json:"orphans"`  // markers with no log entry
+	Leftover []DebtMarker `json:"leftover"` // markers of resolved entries
+}
+
+// NewDebtScan converts a scan's reconciliation
+func NewDebtScan(r *debt.Reconciled) *DebtScan {
+	markers := func(ms []debt.Marker) []DebtMarker {
+		out := []DebtMarker{}
+		for _, m := range ms {
+			out = append(out, DebtMarker{ID: m.ID, File: m.File, Line: m.Line, Text: m.Text})
+		}
+		return out
+	}
+	out := &DebtScan{Linked: markers(r.Linked), Gone: []DebtEntry{}, Orphans: markers(r.Orphans), Leftover: markers(r.Leftover)}
+	for _, e := range r.Gone {
+		out.Gone = append(out.Gone, NewDebtEntry(e))
+	}
+	return out
+}
+
+// DebtReport is the schema of 'yo debt report'
+type DebtReport struct {
+	Outstanding      int        `json:"outstanding"`
+	OutstandingHours float64    `json:"outstanding_hours"` // estimated fix time
+	Unestimated      int        `json:"unestimated"`       // outstanding entries without an estimate
+	BudgetHours      float64    `json:"budget_hours"`      // 0 without a budget
+	Ages             []DebtAge  `json:"ages"`
+	Tasks            []DebtTask `json:"tasks"` // most hours first
+	Weeks            []DebtWeek `json:"weeks"` // oldest first
+}
+
+// DebtAge is the outstanding debt within an age range
+type DebtAge struct {
+	Age   string  `json:"age"` // e.g. 1-4 weeks
+	Count int     `json:"count"`
+	Hours float64 `json:"hours"`
+}
+
+// DebtTask is the outstanding debt deferred by one task
+type DebtTask struct {
+	Task  string  `json:"task"`
+	Count int     `json:"count"`
+	Hours float64 `json:"hours"`
+}
+
+// DebtWeek is the debt deferred and resolved in a week
+type DebtWeek struct {
+	WeekStart string   `json:"week_start"`
+	Created   int      `json:"created"`
+	Resolved  int      `json:"resolved"`
+	Ratio     *float64 `json:"ratio"` // created per resolved, null when nothing was resolved
+}
+
+// NewDebtReport converts a tech debt report
+func NewDebtReport(r *debt.Report, budgetHours float64) *DebtReport {
+	out := &DebtReport{
+		Outstanding:      r.Outstanding,
+		OutstandingHours: r.Hours,
+		Unestimated:      r.Unestimated,
+		BudgetHours:      budgetHours,
+		Ages:             []DebtAge{},
+		Tasks:            []DebtTask{},
+		Weeks:            []DebtWeek{},
+	}
+	for _, a := range r.Ages {
+		out.Ages = append(out.Ages, DebtAge{Age: a.Label, Count: a.Count, Hours: a.Hours})
+	}
+	for _, t := range r.Tasks {
+		out.Tasks = append(out.Tasks, DebtTask{Task: t.Task, Count: t.Count, Hours: t.Hours})
+	}
+	for _, w := range r.Weeks {
+		week := DebtWeek{WeekStart: w.Start.Format("2006-01-02"), Created: w.Created, Resolved: w.Resolved}
+		if w.Resolved > 0 {
+			ratio := w.Ratio()
+			week.Ratio = &ratio
+		}
+		out.Weeks = append(out.Weeks, week)
+	}
+	return out
+}
+
+// DebtDue is an open tech debt entry whose "come back when" trigger fired
+type DebtDue struct {
+	ID      string   `json:"id"`
+	What    string   `json:"what"`
+	Reasons []string `json:"reasons"` // e.g. "tasks:5: 5 tasks done since"
+}
+
+// NewDebtDue converts the due tech debt
+func NewDebtDue(due []debt.Due) []DebtDue {
+	out := []DebtDue{}
+	for _, d := range due {
+		out = append(out, DebtDue{ID: d.Entry.ID, What: d.Entry.What, Reasons: d.Reasons})
+	}
+	return out
+}
+
+// ActivityCounts summarises an activity range
+type ActivityCounts struct {
+	StageChanges int `json:"stage_changes"`
+	FileChanges  int `json:"file_changes"`
+	Bypasses     int `json:"bypasses"`
+}
+
+// Activity is the schema of 'yo activity'. Entries use the activity.jsonl
+// format, oldest first.
+type Activity struct {
+	Range   string           `json:"range"` // today, yesterday or week
+	Counts  ActivityCounts   `json:"counts"`
+	Entries []activity.Entry `json:"entries"`
+}
+
+// Focus is the schema of 'yo focus'
+type Focus struct {
+	Score            float64        `json:"score"` // percent of file changes on task
+	OnTaskChanges    int            `json:"on_task_changes"`
+	UntrackedChanges int            `json:"untracked_changes"`
+	Repos            map[string]int `json:"repos"` // file changes per repo
+}
+
+// Stats is the schema of 'yo stats': stats.WeekStats plus insights
+type Stats struct {
+	*stats.WeekStats
+	Insights []string `json:"insights"`
+}
+
+func timerOf(s *state.State) *Timer {
+	if s.CurrentStage != "green" || s.Timer.StartedAt.IsZero() {
+		return nil
+	}
+	return NewTimer(timer.GetStatus(s))
+}
+
+func seconds(d time.Duration) int64 {
+	return int64(d / time.Second)
+}
@@ -0,0 +1,92 @@
+package prompt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/faisalahmedsifat/yo/internal/yaml"
+)
+
+// Answers are pre-recorded answers keyed like the command flags. Values
+// are strings, lists ([]interface{}) or maps (map[string]interface{}).
+type Answers map[string]interface{}
+
+// LoadAnswers reads an answers file. Files ending in .json are JSON;
+// anything else is read as JSON if it starts with "{", YAML otherwise.
+func LoadAnswers(path string) (Answers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers: %w", err)
+	}
+
+	answers, err := ParseAnswers(data, filepath.Ext(path) == ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse answers %s: %w", path, err)
+	}
+	return answers, nil
+}
+
+// ParseAnswers parses answers as JSON or the YAML subset yaml.Parse reads
+func ParseAnswers(data []byte, isJSON bool) (Answers, error) {
+	if isJSON || strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		var answers Answers
+		if err := json.Unmarshal(data, &answers); err != nil {
+			return nil, err
+		}
+		return answers, nil
+	}
+
+	v, err := yaml.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return Answers{}, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("answers must be a mapping of keys to answers")
+	}
+	return Answers(m), nil
+}
+
+// Strings returns the answer for key as a list of strings: a scalar is a
+// one-item list. Maps inside lists are skipped; use the raw value for those.
+func (a Answers) Strings(key string) ([]string, bool) {
+	v, ok := a[key]
+	if !ok || v == nil {
+		return nil, false
+	}
+
+	if list, ok := v.([]interface{}); ok {
+		var values []string
+		for _, item := range list {
+			if s, ok := scalarString(item); ok {
+				values = append(values, s)
+			}
+		}
+		return values, true
+	}
+
+	s, ok := scalarString(v)
+	if !ok {
+		return nil, false
+	}
+	return []string{s}, true
+}
+
+func scalarString(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
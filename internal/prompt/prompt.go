@@ -0,0 +1,157 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Flags are answers given on the command line
+type Flags interface {
+	// Lookup returns the flag's values and whether it was set
+	Lookup(key string) ([]string, bool)
+	// Has reports whether the command has a flag named key
+	Has(key string) bool
+}
+
+// Prompter asks the questions commands need answered. Every question has
+// a key; its answer comes from the first of: the command flag named key,
+// the answers file, or stdin. With NoInput, a question nobody answered is
+// an error instead of a blocking read.
+type Prompter struct {
+	Flags   Flags
+	Answers Answers
+	NoInput bool // never read stdin
+	Yes     bool // answer yes to every confirmation
+
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// New returns a Prompter that reads in and prints questions to out.
+// All questions share one reader, so piped answers aren't lost between them.
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// MissingError is returned for an unanswered question in NoInput mode
+type MissingError struct {
+	Key  string
+	Flag bool // the command has a flag for it
+}
+
+func (e *MissingError) Error() string {
+	if e.Flag {
+		return fmt.Sprintf("no answer for %q and --no-input is set: pass --%s or set %q in the --answers file", e.Key, e.Key, e.Key)
+	}
+	return fmt.Sprintf("no answer for %q and --no-input is set: set %q in the --answers file", e.Key, e.Key)
+}
+
+// Answered reports whether key is answered without asking
+func (p *Prompter) Answered(key string) bool {
+	_, ok := p.lookup(key)
+	return ok
+}
+
+// Ask returns the answer to a free-text question. The prompt is printed
+// only when the answer is read from stdin.
+func (p *Prompter) Ask(key, prompt string) (string, error) {
+	if values, ok := p.lookup(key); ok {
+		return strings.TrimSpace(strings.Join(values, ", ")), nil
+	}
+	if p.NoInput {
+		return "", p.missing(key)
+	}
+
+	fmt.Fprint(p.out, prompt)
+	return p.readLine(), nil
+}
+
+// AskList returns a list answer. On stdin it asks itemPrompt(n) for each
+// item until an empty line.
+func (p *Prompter) AskList(key, prompt string, itemPrompt func(n int) string) ([]string, error) {
+	if values, ok := p.lookup(key); ok {
+		var items []string
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				items = append(items, v)
+			}
+		}
+		return items, nil
+	}
+	if p.NoInput {
+		return nil, p.missing(key)
+	}
+
+	fmt.Fprint(p.out, prompt)
+	var items []string
+	for n := 1; ; n++ {
+		fmt.Fprint(p.out, itemPrompt(n))
+		line := p.readLine()
+		if line == "" {
+			return items, nil
+		}
+		items = append(items, line)
+	}
+}
+
+// Confirm asks a yes/no question. Yes answers true; NoInput without an
+// answer is an error like any other question. An empty or unrecognised
+// reply on stdin takes def.
+func (p *Prompter) Confirm(key, question string, def bool) (bool, error) {
+	if values, ok := p.lookup(key); ok && len(values) > 0 {
+		answer, ok := parseBool(values[0])
+		if !ok {
+			return false, fmt.Errorf("invalid answer for %q: %q (use yes or no)", key, values[0])
+		}
+		return answer, nil
+	}
+	if p.Yes {
+		return true, nil
+	}
+	if p.NoInput {
+		return false, p.missing(key)
+	}
+
+	fmt.Fprintf(p.out, "%s (y/n): ", question)
+	if answer, ok := parseBool(p.readLine()); ok {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// Value returns the raw answers-file value for key, for answers that are
+// more than a string or list of strings
+func (p *Prompter) Value(key string) (interface{}, bool) {
+	v, ok := p.Answers[key]
+	return v, ok
+}
+
+func (p *Prompter) lookup(key string) ([]string, bool) {
+	if p.Flags != nil {
+		if values, ok := p.Flags.Lookup(key); ok {
+			return values, true
+		}
+	}
+	return p.Answers.Strings(key)
+}
+
+func (p *Prompter) missing(key string) error {
+	return &MissingError{Key: key, Flag: p.Flags != nil && p.Flags.Has(key)}
+}
+
+func (p *Prompter) readLine() string {
+	line, _ := p.in.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "y", "yes", "true", "1":
+		return true, true
+	case "n", "no", "false", "0":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,145 @@
+package prompt
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeFlags map[string][]string
+
+func (f fakeFlags) Lookup(key string) ([]string, bool) {
+	v, ok := f[key]
+	return v, ok
+}
+
+func (f fakeFlags) Has(key string) bool {
+	return key == "problem"
+}
+
+func TestAskOrder(t *testing.T) {
+	var out bytes.Buffer
+	p := New(strings.NewReader("from stdin\nsecond\n"), &out)
+	p.Flags = fakeFlags{"problem": {"from flag"}}
+	p.Answers = Answers{"problem": "from answers", "reason": "from answers"}
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"problem", "from flag"},
+		{"reason", "from answers"},
+		{"other", "from stdin"},
+		{"again", "second"},
+	}
+	for _, tt := range tests {
+		got, err := p.Ask(tt.key, tt.key+"? ")
+		if err != nil || got != tt.expected {
+			t.Errorf("Ask(%q) = %q, %v, expected %q", tt.key, got, err, tt.expected)
+		}
+	}
+
+	if out.String() != "other? again? " {
+		t.Errorf("Expected prompts only for stdin answers, got %q", out.String())
+	}
+}
+
+func TestNoInput(t *testing.T) {
+	p := New(strings.NewReader("never read\n"), &bytes.Buffer{})
+	p.Flags = fakeFlags{}
+	p.NoInput = true
+
+	_, err := p.Ask("problem", "? ")
+	var missing *MissingError
+	if !errors.As(err, &missing) || missing.Key != "problem" || !missing.Flag {
+		t.Fatalf("Expected MissingError with a flag, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "--problem") {
+		t.Errorf("Expected error to suggest --problem, got %q", err)
+	}
+
+	if _, err := p.AskList("step", "", nil); !errors.As(err, &missing) || missing.Flag {
+		t.Errorf("Expected MissingError without a flag, got %v", err)
+	}
+
+	// Confirmations don't fall back to their default either
+	if ok, err := p.Confirm("roll", "Roll?", true); ok || !errors.As(err, &missing) || missing.Key != "roll" {
+		t.Errorf("Confirm = %v, %v, expected MissingError", ok, err)
+	}
+
+	// Yes still answers them
+	p.Yes = true
+	if ok, err := p.Confirm("roll", "Roll?", false); !ok || err != nil {
+		t.Errorf("Confirm with Yes = %v, %v, expected true", ok, err)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	p := New(strings.NewReader("y\n\nmaybe\nno\n"), &bytes.Buffer{})
+	p.Answers = Answers{"park": "no", "bad": "sure"}
+
+	if ok, _ := p.Confirm("park", "Park?", true); ok {
+		t.Error("Expected the answers file to say no")
+	}
+	if _, err := p.Confirm("bad", "Bad?", true); err == nil {
+		t.Error("Expected an error for an answer that isn't yes or no")
+	}
+
+	expected := []bool{true, true, true, false} // y, empty, unrecognised, no
+	for i, want := range expected {
+		if got, _ := p.Confirm("ask", "Ok?", true); got != want {
+			t.Errorf("Confirm #%d = %v, expected %v", i+1, got, want)
+		}
+	}
+
+	p.Yes = true
+	if ok, _ := p.Confirm("ask", "Ok?", false); !ok {
+		t.Error("Expected --yes to confirm")
+	}
+	if ok, _ := p.Confirm("park", "Park?", false); ok {
+		t.Error("Expected an explicit answer to beat --yes")
+	}
+}
+
+func TestAskList(t *testing.T) {
+	var out bytes.Buffer
+	p := New(strings.NewReader("one\ntwo\n\n"), &out)
+	item := func(n int) string { return "- " }
+
+	got, err := p.AskList("step", "Steps:\n", item)
+	if err != nil || !reflect.DeepEqual(got, []string{"one", "two"}) {
+		t.Errorf("AskList from stdin = %v, %v", got, err)
+	}
+
+	p.Answers = Answers{"step": []interface{}{"a", " ", "b"}}
+	got, _ = p.AskList("step", "", item)
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("AskList from answers = %v", got)
+	}
+}
+
+func TestParseAnswers(t *testing.T) {
+	a, err := ParseAnswers([]byte(`{"impact": ["users", "debt"], "yes": true, "count": 2}`), false)
+	if err != nil {
+		t.Fatalf("ParseAnswers failed: %v", err)
+	}
+
+	if got, _ := a.Strings("impact"); !reflect.DeepEqual(got, []string{"users", "debt"}) {
+		t.Errorf("Strings(impact) = %v", got)
+	}
+	if got, _ := a.Strings("yes"); !reflect.DeepEqual(got, []string{"true"}) {
+		t.Errorf("Strings(yes) = %v", got)
+	}
+	if got, _ := a.Strings("count"); !reflect.DeepEqual(got, []string{"2"}) {
+		t.Errorf("Strings(count) = %v", got)
+	}
+	if _, ok := a.Strings("missing"); ok {
+		t.Error("Expected no answer for a missing key")
+	}
+
+	if _, err := ParseAnswers([]byte("- a\n- b\n"), false); err == nil {
+		t.Error("Expected an error for a top-level list")
+	}
+}
@@ -0,0 +1,192 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
+)
+
+// Workspace is a project directory that has been initialized with yo
+type Workspace struct {
+	Path    string    `json:"path"` // Project directory containing .yo
+	AddedAt time.Time `json:"added_at"`
+}
+
+// YoDir returns the workspace's .yo directory
+func (w Workspace) YoDir() string {
+	return filepath.Join(w.Path, ".yo")
+}
+
+// Name returns a short display name for the workspace
+func (w Workspace) Name() string {
+	return filepath.Base(w.Path)
+}
+
+// Exists reports whether the workspace still has a state.json
+func (w Workspace) Exists() bool {
+	_, err := os.Stat(filepath.Join(w.YoDir(), "state.json"))
+	return err == nil
+}
+
+// Registry is the global list of workspaces in ~/.yo/workspaces.json
+type Registry struct {
+	Workspaces []Workspace `json:"workspaces"`
+
+	path string
+}
+
+// GetGlobalDir returns the global .yo directory in the user's home
+func GetGlobalDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".yo"), nil
+}
+
+// GetPath returns the path to workspaces.json
+func GetPath() (string, error) {
+	globalDir, err := GetGlobalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(globalDir, "workspaces.json"), nil
+}
+
+// Load loads the global registry
+func Load() (*Registry, error) {
+	path, err := GetPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFrom(path)
+}
+
+// LoadFrom loads a registry file; a missing file is an empty registry
+func LoadFrom(path string) (*Registry, error) {
+	r := &Registry{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, fmt.Errorf("failed to read workspace registry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace registry: %w", err)
+	}
+	return r, nil
+}
+
+// Save writes the registry back to disk
+func (r *Registry) Save() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(r.path), err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal workspace registry: %w", err)
+	}
+
+	if err := fileutil.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write workspace registry: %w", err)
+	}
+	return nil
+}
+
+// Add records a project directory. Returns false if it was already known.
+func (r *Registry) Add(dir string) bool {
+	dir = filepath.Clean(dir)
+	for _, w := range r.Workspaces {
+		if w.Path == dir {
+			return false
+		}
+	}
+	r.Workspaces = append(r.Workspaces, Workspace{Path: dir, AddedAt: time.Now()})
+	return true
+}
+
+// Remove forgets a project directory. Returns false if it wasn't known.
+func (r *Registry) Remove(dir string) bool {
+	dir = filepath.Clean(dir)
+	for i, w := range r.Workspaces {
+		if w.Path == dir {
+			r.Workspaces = append(r.Workspaces[:i], r.Workspaces[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the workspace containing path. When workspaces are nested
+// the deepest one wins.
+func (r *Registry) Find(path string) (Workspace, bool) {
+	var found Workspace
+	for _, w := range r.Workspaces {
+		if Contains(w.Path, path) && len(w.Path) > len(found.Path) {
+			found = w
+		}
+	}
+	return found, found.Path != ""
+}
+
+// YoDirs returns the .yo directories of workspaces that still exist
+func (r *Registry) YoDirs() []string {
+	var dirs []string
+	for _, w := range r.Workspaces {
+		if w.Exists() {
+			dirs = append(dirs, w.YoDir())
+		}
+	}
+	return dirs
+}
+
+// Register adds a project directory to the global registry
+func Register(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	path, err := GetPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	unlock, err := fileutil.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r, err := LoadFrom(path)
+	if err != nil {
+		return err
+	}
+
+	if !r.Add(abs) {
+		return nil
+	}
+	return r.Save()
+}
+
+// Contains reports whether path is dir or inside it
+func Contains(dir, path string) bool {
+	dir = filepath.Clean(dir)
+	path = filepath.Clean(path)
+	if path == dir {
+		return true
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegisterAndLoad(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	project := filepath.Join(home, "Dev", "api")
+	if err := Register(project); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	// Registering twice keeps one entry
+	if err := Register(project); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	r, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(r.Workspaces) != 1 || r.Workspaces[0].Path != project {
+		t.Fatalf("Unexpected workspaces: %+v", r.Workspaces)
+	}
+
+	if _, err := os.Stat(filepath.Join(home, ".yo", "workspaces.json")); err != nil {
+		t.Errorf("Expected workspaces.json in ~/.yo: %v", err)
+	}
+
+	// Only workspaces with a state.json are returned
+	if dirs := r.YoDirs(); len(dirs) != 0 {
+		t.Errorf("Expected no existing workspaces, got %v", dirs)
+	}
+	os.MkdirAll(filepath.Join(project, ".yo"), 0755)
+	os.WriteFile(filepath.Join(project, ".yo", "state.json"), []byte("{}"), 0644)
+	if dirs := r.YoDirs(); len(dirs) != 1 || dirs[0] != filepath.Join(project, ".yo") {
+		t.Errorf("Unexpected workspaces: %v", dirs)
+	}
+
+	if !r.Remove(project) || len(r.Workspaces) != 0 {
+		t.Error("Expected workspace to be removed")
+	}
+}
+
+func TestFind(t *testing.T) {
+	r := &Registry{}
+	r.Add("/home/user/Dev/mono")
+	r.Add("/home/user/Dev/mono/api")
+	r.Add("/home/user/Dev/web")
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/home/user/Dev/mono/api/main.go", "/home/user/Dev/mono/api"},
+		{"/home/user/Dev/mono/README.md", "/home/user/Dev/mono"},
+		{"/home/user/Dev/web", "/home/user/Dev/web"},
+		{"/home/user/Dev/webapp/index.js", ""},
+	}
+
+	for _, tt := range tests {
+		w, _ := r.Find(tt.path)
+		if w.Path != tt.expected {
+			t.Errorf("Find(%s) = %q, expected %q", tt.path, w.Path, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
+)
+
+// SchemaVersion is the workspace layout this build reads and writes.
+// Bump it together with a new entry in migrations.
+const SchemaVersion = "1.2.0"
+
+// baseVersion is assumed for state.json files without a version
+const baseVersion = "1.0.0"
+
+// Migration upgrades a workspace from one schema version to the next
+type Migration struct {
+	From        string
+	To          string
+	Description string
+	Apply       func(w *Snapshot) error
+}
+
+// migrations are applied in order, each starting where the last ended
+var migrations = []Migration{
+	{
+		From:        "1.0.0",
+		To:          "1.1.0",
+		Description: "Parked tasks, timer extensions and bypass length",
+		Apply: func(w *Snapshot) error {
+			w.Mkdir("tasks")
+			w.SetDefault(w.Config, "config.json", "bypass_minutes", 30)
+			w.SetDefault(w.Config, "config.json", "max_extensions", 2)
+			w.SetDefault(w.Config, "config.json", "max_extension_hours", 4)
+			return nil
+		},
+	},
+	{
+		From:        "1.1.0",
+		To:          "1.2.0",
+		Description: "Every task lives in tasks/<id>.md, the active one too",
+		Apply: func(w *Snapshot) error {
+			w.Mkdir("tasks")
+			id, _ := w.State["current_task_id"].(string)
+			switch {
+			case !fileExistsIn(w.YoDir, "current_task.md"):
+			case id != "" && !fileExistsIn(w.YoDir, filepath.Join("tasks", id+".md")):
+				w.Rename("current_task.md", filepath.Join("tasks", id+".md"))
+			case id == "" && isBlankTaskTemplate(filepath.Join(w.YoDir, "current_task.md")):
+				w.Remove("current_task.md")
+			default:
+				// A task without an ID, or one clashing with a parked
+				// task: leave it for 'yo doctor' to sort out
+				w.Note("keep current_task.md (run 'yo doctor')")
+			}
+			return nil
+		},
+	},
+}
+
+// fileExistsIn reports whether rel exists inside yoDir
+func fileExistsIn(yoDir, rel string) bool {
+	_, err := os.Stat(filepath.Join(yoDir, rel))
+	return err == nil
+}
+
+// isBlankTaskTemplate reports whether a task file has no problem filled in
+func isBlankTaskTemplate(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	_, rest, found := strings.Cut(string(data), "### What's the Problem?")
+	if !found {
+		return false
+	}
+	body, _, _ := strings.Cut(rest, "###")
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !(strings.HasPrefix(line, "<!--") && strings.HasSuffix(line, "-->")) {
+			return false
+		}
+	}
+	return true
+}
+
+// Snapshot is the raw workspace a migration edits: state.json and
+// config.json as generic JSON, so migrations don't depend on today's
+// structs, plus directories to create and files to move. Every edit is
+// recorded as a change.
+type Snapshot struct {
+	YoDir  string
+	State  map[string]interface{}
+	Config map[string]interface{}
+
+	dirs    []string
+	moves   [][2]string // from, to; an empty to removes the file
+	changes []string
+}
+
+// Mkdir creates a directory inside .yo unless it already exists
+func (w *Snapshot) Mkdir(rel string) {
+	if _, err := os.Stat(filepath.Join(w.YoDir, rel)); err == nil {
+		return
+	}
+	for _, dir := range w.dirs {
+		if dir == rel {
+			return
+		}
+	}
+	w.dirs = append(w.dirs, rel)
+	w.Note("create %s/", rel)
+}
+
+// Rename moves a file inside .yo
+func (w *Snapshot) Rename(from, to string) {
+	w.moves = append(w.moves, [2]string{from, to})
+	w.Note("move %s to %s", from, to)
+}
+
+// Remove deletes a file inside .yo. It is copied to the backup first.
+func (w *Snapshot) Remove(rel string) {
+	w.moves = append(w.moves, [2]string{rel, ""})
+	w.Note("remove %s", rel)
+}
+
+// SetDefault sets key in a JSON file's map unless it is already present
+func (w *Snapshot) SetDefault(m map[string]interface{}, file, key string, value interface{}) {
+	if _, ok := m[key]; ok {
+		return
+	}
+	m[key] = value
+	w.Note("%s: add %s = %v", file, key, value)
+}
+
+// Note records a human-readable change
+func (w *Snapshot) Note(format string, args ...interface{}) {
+	w.changes = append(w.changes, fmt.Sprintf(format, args...))
+}
+
+// MigrationResult describes what Migrate did, or would do on a dry run
+type MigrationResult struct {
+	From      string
+	To        string
+	Steps     []Migration
+	Changes   []string
+	BackupDir string // empty on a dry run or when nothing changed
+}
+
+// Pending reports whether the workspace needed migrating
+func (r *MigrationResult) Pending() bool {
+	return len(r.Steps) > 0
+}
+
+// CompareVersions compares two "major.minor.patch" versions, returning
+// -1, 0 or 1. Missing or non-numeric parts count as zero.
+func CompareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < 3; i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// checkVersion refuses workspaces written by a newer yo
+func checkVersion(version string) error {
+	if CompareVersions(version, SchemaVersion) > 0 {
+		return fmt.Errorf("workspace schema %s is newer than this yo supports (%s). Upgrade yo", version, SchemaVersion)
+	}
+	return nil
+}
+
+// ReadVersion returns the schema version recorded in state.json
+func ReadVersion(yoDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(yoDir, "state.json"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read state: %w", err)
+	}
+
+	var v struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "", fmt.Errorf("failed to parse state: %w", err)
+	}
+	if v.Version == "" {
+		return baseVersion, nil
+	}
+	return v.Version, nil
+}
+
+// NeedsMigration reports whether the workspace is older than this build.
+// It errors if the workspace is newer.
+func NeedsMigration(yoDir string) (bool, error) {
+	version, err := ReadVersion(yoDir)
+	if err != nil {
+		return false, err
+	}
+	if err := checkVersion(version); err != nil {
+		return false, err
+	}
+	return CompareVersions(version, SchemaVersion) < 0, nil
+}
+
+// Migrate upgrades the workspace to SchemaVersion. The original state.json
+// and config.json are copied to .yo/backups/ first. With dryRun nothing
+// is written and the result lists what would change.
+func Migrate(yoDir string, dryRun bool) (*MigrationResult, error) {
+	statePath := filepath.Join(yoDir, "state.json")
+	configPath := filepath.Join(yoDir, "config.json")
+
+	unlock, err := fileutil.Lock(statePath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Hold the lock config.Save uses too, so a concurrent "yo config set"
+	// can't land between reading config.json and rewriting it
+	unlockConfig, err := fileutil.Lock(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockConfig()
+
+	from, err := ReadVersion(yoDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(from); err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{From: from, To: from}
+	if CompareVersions(from, SchemaVersion) == 0 {
+		return result, nil
+	}
+
+	w := &Snapshot{YoDir: yoDir}
+	stateData, err := readJSONMap(statePath, &w.State)
+	if err != nil {
+		return nil, err
+	}
+	configData, err := readJSONMap(configPath, &w.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	for result.To != SchemaVersion {
+		m, ok := findMigration(result.To)
+		if !ok {
+			return nil, fmt.Errorf("no migration from schema %s to %s", result.To, SchemaVersion)
+		}
+		if err := m.Apply(w); err != nil {
+			return nil, fmt.Errorf("migration %s → %s failed: %w", m.From, m.To, err)
+		}
+		w.Note("state.json: version %s → %s", m.From, m.To)
+		result.Steps = append(result.Steps, m)
+		result.To = m.To
+	}
+	w.State["version"] = result.To
+	result.Changes = w.changes
+
+	if dryRun {
+		return result, nil
+	}
+
+	// Back up the originals before touching anything
+	result.BackupDir = filepath.Join(yoDir, "backups", fmt.Sprintf("%s-v%s", time.Now().Format("20060102-150405"), from))
+	if err := os.MkdirAll(result.BackupDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+	if err := fileutil.WriteFile(filepath.Join(result.BackupDir, "state.json"), stateData, 0644); err != nil {
+		return nil, fmt.Errorf("failed to back up state.json: %w", err)
+	}
+	if configData != nil {
+		if err := fileutil.WriteFile(filepath.Join(result.BackupDir, "config.json"), configData, 0644); err != nil {
+			return nil, fmt.Errorf("failed to back up config.json: %w", err)
+		}
+	}
+
+	for _, dir := range w.dirs {
+		if err := os.MkdirAll(filepath.Join(yoDir, dir), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+
+	for _, move := range w.moves {
+		from := filepath.Join(yoDir, move[0])
+		if move[1] == "" {
+			data, err := os.ReadFile(from)
+			if err != nil {
+				return nil, fmt.Errorf("failed to back up %s: %w", move[0], err)
+			}
+			if err := fileutil.WriteFile(filepath.Join(result.BackupDir, filepath.Base(from)), data, 0644); err != nil {
+				return nil, fmt.Errorf("failed to back up %s: %w", move[0], err)
+			}
+			if err := os.Remove(from); err != nil {
+				return nil, fmt.Errorf("failed to remove %s: %w", move[0], err)
+			}
+			continue
+		}
+		if err := os.Rename(from, filepath.Join(yoDir, move[1])); err != nil {
+			return nil, fmt.Errorf("failed to move %s: %w", move[0], err)
+		}
+	}
+
+	if len(w.Config) > 0 {
+		data, err := json.MarshalIndent(w.Config, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		if err := fileutil.WriteFile(configPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write config: %w", err)
+		}
+	}
+
+	// Round-trip through State so state.json keeps its usual field order
+	data, err := json.Marshal(w.State)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse migrated state: %w", err)
+	}
+	if err := s.write(yoDir); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func findMigration(from string) (Migration, bool) {
+	for _, m := range migrations {
+		if CompareVersions(m.From, from) == 0 {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+// readJSONMap decodes a JSON object file into m, returning the raw bytes.
+// A missing file gives an empty map and nil bytes.
+func readJSONMap(path string, m *map[string]interface{}) ([]byte, error) {
+	*m = make(map[string]interface{})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return data, nil
+}
@@ -0,0 +1,197 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "1.1.0", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"2", "1.9.9", 1},
+		{"1.0", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.expected {
+			t.Errorf("CompareVersions(%s, %s) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func writeLegacyWorkspace(t *testing.T) string {
+	t.Helper()
+
+	yoDir := filepath.Join(t.TempDir(), ".yo")
+	os.MkdirAll(yoDir, 0755)
+	os.WriteFile(filepath.Join(yoDir, "state.json"),
+		[]byte(`{"version":"1.0.0","current_stage":"red","current_task_id":"fix_login","timer":{}}`), 0644)
+	os.WriteFile(filepath.Join(yoDir, "config.json"),
+		[]byte(`{"notifications":false,"max_bypass_day":1}`), 0644)
+	os.WriteFile(filepath.Join(yoDir, "current_task.md"),
+		[]byte("# Current Task\n\n## 🔴 RED LIGHT\n\n### What's the Problem?\nLogin fails\n"), 0644)
+	return yoDir
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	yoDir := writeLegacyWorkspace(t)
+	before, _ := os.ReadFile(filepath.Join(yoDir, "state.json"))
+
+	result, err := Migrate(yoDir, true)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	if !result.Pending() || result.From != "1.0.0" || result.To != SchemaVersion {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	changes := strings.Join(result.Changes, "\n")
+	for _, want := range []string{"create tasks/", "max_extensions", "version 1.0.0 → 1.1.0", "move current_task.md to tasks/fix_login.md"} {
+		if !strings.Contains(changes, want) {
+			t.Errorf("Expected change %q in:\n%s", want, changes)
+		}
+	}
+
+	// Nothing is written
+	after, _ := os.ReadFile(filepath.Join(yoDir, "state.json"))
+	if string(after) != string(before) {
+		t.Error("Dry run modified state.json")
+	}
+	if _, err := os.Stat(filepath.Join(yoDir, "tasks")); err == nil {
+		t.Error("Dry run created tasks/")
+	}
+	if _, err := os.Stat(filepath.Join(yoDir, "backups")); err == nil {
+		t.Error("Dry run created a backup")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	yoDir := writeLegacyWorkspace(t)
+
+	needed, err := NeedsMigration(yoDir)
+	if err != nil || !needed {
+		t.Fatalf("Expected migration to be needed, got %v, %v", needed, err)
+	}
+
+	result, err := Migrate(yoDir, false)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	s, err := LoadFrom(yoDir)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if s.Version != SchemaVersion || s.CurrentTaskID != "fix_login" || s.CurrentStage != "red" {
+		t.Errorf("Unexpected migrated state: %+v", s)
+	}
+
+	var cfg map[string]interface{}
+	data, _ := os.ReadFile(filepath.Join(yoDir, "config.json"))
+	json.Unmarshal(data, &cfg)
+	if cfg["notifications"] != false || cfg["max_extensions"] != float64(2) {
+		t.Errorf("Unexpected migrated config: %v", cfg)
+	}
+
+	if _, err := os.Stat(filepath.Join(yoDir, "tasks")); err != nil {
+		t.Error("Expected tasks/ to be created")
+	}
+	if data, err := os.ReadFile(filepath.Join(yoDir, "tasks", "fix_login.md")); err != nil || !strings.Contains(string(data), "Login fails") {
+		t.Errorf("Expected the active task moved to tasks/fix_login.md, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(yoDir, "current_task.md")); err == nil {
+		t.Error("Expected current_task.md to be moved")
+	}
+
+	backup, err := os.ReadFile(filepath.Join(result.BackupDir, "state.json"))
+	if err != nil || !strings.Contains(string(backup), `"version":"1.0.0"`) {
+		t.Errorf("Expected original state.json in backup, got %q (%v)", backup, err)
+	}
+
+	// A second run is a no-op
+	again, err := Migrate(yoDir, false)
+	if err != nil || again.Pending() {
+		t.Errorf("Expected nothing to migrate, got %+v, %v", again, err)
+	}
+}
+
+func TestMigrateWaitsForConfigLock(t *testing.T) {
+	yoDir := writeLegacyWorkspace(t)
+	configPath := filepath.Join(yoDir, "config.json")
+
+	// A concurrent "yo config set" holds the lock and writes a new value
+	unlock, err := fileutil.Lock(configPath)
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	done := make(chan error)
+	go func() {
+		_, err := Migrate(yoDir, false)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Expected Migrate to wait for the config lock, it returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	os.WriteFile(configPath, []byte(`{"notifications": false, "max_extensions": 5}`), 0644)
+	unlock()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	var cfg map[string]interface{}
+	data, _ := os.ReadFile(configPath)
+	json.Unmarshal(data, &cfg)
+	if cfg["max_extensions"] != float64(5) {
+		t.Errorf("Expected the concurrent config write to survive, got %v", cfg)
+	}
+}
+
+func TestMigrateRemovesBlankTask(t *testing.T) {
+	yoDir := filepath.Join(t.TempDir(), ".yo")
+	os.MkdirAll(filepath.Join(yoDir, "tasks"), 0755)
+	os.WriteFile(filepath.Join(yoDir, "state.json"), []byte(`{"version":"1.1.0","current_stage":"none"}`), 0644)
+	os.WriteFile(filepath.Join(yoDir, "current_task.md"),
+		[]byte("# Current Task\n\n### What's the Problem?\n<!-- Describe the problem clearly -->\n\n### Impact\n"), 0644)
+
+	result, err := Migrate(yoDir, false)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(yoDir, "current_task.md")); err == nil {
+		t.Error("Expected the blank current_task.md to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(result.BackupDir, "current_task.md")); err != nil {
+		t.Error("Expected the removed file in the backup")
+	}
+}
+
+func TestRefuseNewerWorkspace(t *testing.T) {
+	yoDir := filepath.Join(t.TempDir(), ".yo")
+	os.MkdirAll(yoDir, 0755)
+	os.WriteFile(filepath.Join(yoDir, "state.json"), []byte(`{"version":"99.0.0","current_stage":"none"}`), 0644)
+
+	if _, err := LoadFrom(yoDir); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected LoadFrom to refuse a newer workspace, got %v", err)
+	}
+	if _, err := NeedsMigration(yoDir); err == nil {
+		t.Error("Expected NeedsMigration to refuse a newer workspace")
+	}
+	if _, err := Migrate(yoDir, false); err == nil {
+		t.Error("Expected Migrate to refuse a newer workspace")
+	}
+}
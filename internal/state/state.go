@@ -2,21 +2,28 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
 )
 
 // State represents the current state of the yo workspace
 type State struct {
-	Version           string            `json:"version"`
-	CurrentStage      string            `json:"current_stage"` // none, red, yellow, green
-	CurrentTaskID     string            `json:"current_task_id"`
-	CurrentTaskRepo   string            `json:"current_task_repo"`
-	Timer             Timer             `json:"timer"`
-	Session           Session           `json:"session"`
-	EmergencyBypasses EmergencyBypasses `json:"emergency_bypasses"`
+	Version           string               `json:"version"`
+	CurrentStage      string               `json:"current_stage"` // none, red, yellow, green, bypass
+	CurrentTaskID     string               `json:"current_task_id"`
+	CurrentTaskRepo   string               `json:"current_task_repo"`
+	CurrentBacklogID  string               `json:"current_backlog_id,omitempty"` // backlog item the task came from
+	Timer             Timer                `json:"timer"`
+	Session           Session              `json:"session"`
+	EmergencyBypasses EmergencyBypasses    `json:"emergency_bypasses"`
+	Bypass            Bypass               `json:"bypass"`
+	Tasks             map[string]TaskState `json:"tasks,omitempty"` // parked tasks by ID; every task's file is tasks/<id>.md
 }
 
 // Timer tracks the current task timer
@@ -25,10 +32,17 @@ type Timer struct {
 	EstimatedHours float64     `json:"estimated_hours"`
 	ThresholdHours float64     `json:"threshold_hours"`
 	Paused         bool        `json:"paused"`
+	Intervals      []Interval  `json:"intervals,omitempty"` // active work periods
 	Extensions     []Extension `json:"extensions,omitempty"`
 	Notifications  []string    `json:"notifications,omitempty"` // track which milestones were notified
 }
 
+// Interval is a period of active work on the timer (End is zero while running)
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitempty"`
+}
+
 // Extension represents a timer extension
 type Extension struct {
 	At     time.Time `json:"at"`
@@ -49,10 +63,30 @@ type EmergencyBypasses struct {
 	LastReset string `json:"last_reset"`
 }
 
+// Bypass tracks the active emergency bypass session
+type Bypass struct {
+	StartedAt       time.Time `json:"started_at,omitempty"`
+	DurationMinutes int       `json:"duration_minutes,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	PreviousStage   string    `json:"previous_stage,omitempty"`
+	PausedTimer     bool      `json:"paused_timer,omitempty"`    // timer was paused by the bypass
+	ExpiryNotified  bool      `json:"expiry_notified,omitempty"` // expiry notification sent
+}
+
+// TaskState is the saved progress of a parked task
+type TaskState struct {
+	Stage       string    `json:"stage"`
+	Timer       Timer     `json:"timer"`
+	Repo        string    `json:"repo,omitempty"`
+	BacklogID   string    `json:"backlog_id,omitempty"`
+	ParkedAt    time.Time `json:"parked_at"`
+	PausedTimer bool      `json:"paused_timer,omitempty"` // timer was paused by parking
+}
+
 // NewState creates a new default state
 func NewState() *State {
 	return &State{
-		Version:      "1.0.0",
+		Version:      SchemaVersion,
 		CurrentStage: "none",
 		Timer: Timer{
 			EstimatedHours: 0,
@@ -70,15 +104,54 @@ func NewState() *State {
 	}
 }
 
-// GetYoDir returns the path to the .yo directory
+// YoDirEnv names the environment variable that overrides workspace discovery
+const YoDirEnv = "YO_DIR"
+
+// GetYoDir returns the path to the .yo directory: $YO_DIR when set,
+// otherwise the nearest workspace in the current directory or one of its
+// parents, falling back to .yo in the current directory
 func GetYoDir() (string, error) {
+	if dir := os.Getenv(YoDirEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get current directory: %w", err)
 	}
+
+	if yoDir, ok := FindYoDir(cwd); ok {
+		return yoDir, nil
+	}
 	return filepath.Join(cwd, ".yo"), nil
 }
 
+// FindYoDir walks up from dir to the nearest .yo directory that holds a
+// state.json. Directories without one (like the global ~/.yo) are skipped.
+func FindYoDir(dir string) (string, bool) {
+	for {
+		yoDir := filepath.Join(dir, ".yo")
+		if _, err := os.Stat(filepath.Join(yoDir, "state.json")); err == nil {
+			return yoDir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// GetProjectDir returns the directory containing the .yo directory
+func GetProjectDir() (string, error) {
+	yoDir, err := GetYoDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Dir(yoDir), nil
+}
+
 // GetStatePath returns the path to state.json
 func GetStatePath() (string, error) {
 	yoDir, err := GetYoDir()
@@ -90,15 +163,21 @@ func GetStatePath() (string, error) {
 
 // Load loads state from disk
 func Load() (*State, error) {
-	statePath, err := GetStatePath()
+	yoDir, err := GetYoDir()
 	if err != nil {
 		return nil, err
 	}
+	return LoadFrom(yoDir)
+}
+
+// LoadFrom loads state from the given .yo directory
+func LoadFrom(yoDir string) (*State, error) {
+	statePath := filepath.Join(yoDir, "state.json")
 
 	data, err := os.ReadFile(statePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("workspace not initialized. Run 'yo init' first")
+			return nil, ErrNotInitialized
 		}
 		return nil, fmt.Errorf("failed to read state: %w", err)
 	}
@@ -108,6 +187,10 @@ func Load() (*State, error) {
 		return nil, fmt.Errorf("failed to parse state: %w", err)
 	}
 
+	if err := checkVersion(state.Version); err != nil {
+		return nil, err
+	}
+
 	// Reset daily/weekly counters if needed
 	state.resetBypassCounters()
 
@@ -116,23 +199,86 @@ func Load() (*State, error) {
 
 // Save saves state to disk
 func (s *State) Save() error {
-	statePath, err := GetStatePath()
+	yoDir, err := GetYoDir()
+	if err != nil {
+		return err
+	}
+	return s.SaveTo(yoDir)
+}
+
+// SaveTo saves state to the given .yo directory
+func (s *State) SaveTo(yoDir string) error {
+	unlock, err := fileutil.Lock(filepath.Join(yoDir, "state.json"))
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	return s.write(yoDir)
+}
 
+// write atomically replaces state.json; the caller holds the lock
+func (s *State) write(yoDir string) error {
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal state: %w", err)
 	}
 
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
+	if err := fileutil.WriteFile(filepath.Join(yoDir, "state.json"), data, 0644); err != nil {
 		return fmt.Errorf("failed to write state: %w", err)
 	}
 
 	return nil
 }
 
+// ErrNotInitialized is returned when there is no workspace to work on
+var ErrNotInitialized = errors.New("workspace not initialized. Run 'yo init' first")
+
+// ErrNoChange can be returned by an Update function to skip the save
+var ErrNoChange = errors.New("no change")
+
+// Update loads state, applies fn and saves the result while holding the
+// state lock, so concurrent commands and the watcher never lose each
+// other's changes. The updated state is returned.
+func Update(fn func(*State) error) (*State, error) {
+	yoDir, err := GetYoDir()
+	if err != nil {
+		return nil, err
+	}
+	return UpdateIn(yoDir, fn)
+}
+
+// UpdateIn is Update for the given .yo directory
+func UpdateIn(yoDir string, fn func(*State) error) (*State, error) {
+	// Report a missing workspace before trying to create its lock file
+	if _, err := os.Stat(yoDir); os.IsNotExist(err) {
+		return nil, ErrNotInitialized
+	}
+
+	unlock, err := fileutil.Lock(filepath.Join(yoDir, "state.json"))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	s, err := LoadFrom(yoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(s); err != nil {
+		if errors.Is(err, ErrNoChange) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	if err := s.write(yoDir); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 // resetBypassCounters resets daily/weekly bypass counters if needed
 func (s *State) resetBypassCounters() {
 	today := time.Now().Format("2006-01-02")
@@ -162,22 +308,83 @@ func (s *State) resetBypassCounters() {
 
 // StartTimer starts the timer for the current task
 func (s *State) StartTimer(estimatedHours float64) {
+	now := time.Now()
 	s.Timer = Timer{
-		StartedAt:      time.Now(),
+		StartedAt:      now,
 		EstimatedHours: estimatedHours,
 		ThresholdHours: estimatedHours,
 		Paused:         false,
+		Intervals:      []Interval{{Start: now}},
 		Extensions:     []Extension{},
 		Notifications:  []string{},
 	}
 }
 
-// GetElapsed returns the elapsed time since timer started
+// GetElapsed returns the active time on the timer, excluding paused periods
 func (s *State) GetElapsed() time.Duration {
-	if s.Timer.StartedAt.IsZero() {
+	return s.Timer.Elapsed()
+}
+
+// Elapsed returns the active time on the timer, excluding paused periods
+func (t Timer) Elapsed() time.Duration {
+	if t.StartedAt.IsZero() {
 		return 0
 	}
-	return time.Since(s.Timer.StartedAt)
+
+	// Timers started before intervals were tracked have a single open period
+	if len(t.Intervals) == 0 {
+		return time.Since(t.StartedAt)
+	}
+
+	var elapsed time.Duration
+	now := time.Now()
+	for _, iv := range t.Intervals {
+		end := iv.End
+		if end.IsZero() {
+			end = now
+		}
+		elapsed += end.Sub(iv.Start)
+	}
+	return elapsed
+}
+
+// PauseTimer closes the current work interval
+func (s *State) PauseTimer() error {
+	if s.Timer.StartedAt.IsZero() {
+		return fmt.Errorf("timer is not running")
+	}
+	if s.Timer.Paused {
+		return fmt.Errorf("timer is already paused")
+	}
+
+	if len(s.Timer.Intervals) == 0 {
+		s.Timer.Intervals = []Interval{{Start: s.Timer.StartedAt}}
+	}
+	s.Timer.Intervals[len(s.Timer.Intervals)-1].End = time.Now()
+	s.Timer.Paused = true
+	return nil
+}
+
+// ResumeTimer opens a new work interval
+func (s *State) ResumeTimer() error {
+	if s.Timer.StartedAt.IsZero() {
+		return fmt.Errorf("timer is not running")
+	}
+	if !s.Timer.Paused {
+		return fmt.Errorf("timer is not paused")
+	}
+
+	s.Timer.Intervals = append(s.Timer.Intervals, Interval{Start: time.Now()})
+	s.Timer.Paused = false
+	return nil
+}
+
+// PausedSince returns when the timer was paused, or zero if it is running
+func (s *State) PausedSince() time.Time {
+	if !s.Timer.Paused || len(s.Timer.Intervals) == 0 {
+		return time.Time{}
+	}
+	return s.Timer.Intervals[len(s.Timer.Intervals)-1].End
 }
 
 // GetProgress returns the progress percentage (elapsed / threshold)
@@ -199,12 +406,152 @@ func (s *State) ExtendTimer(hours float64, reason string) {
 	s.Timer.ThresholdHours += hours
 }
 
+// TotalExtensionHours returns the hours added to the timer by extensions
+func (s *State) TotalExtensionHours() float64 {
+	var total float64
+	for _, ext := range s.Timer.Extensions {
+		total += ext.Hours
+	}
+	return total
+}
+
 // StopTimer stops the timer
 func (s *State) StopTimer() {
 	s.Timer.StartedAt = time.Time{}
 	s.Timer.Paused = true
 }
 
+// StartBypass enters the bypass stage, pausing a running timer so the
+// emergency doesn't count as task time
+func (s *State) StartBypass(reason string, minutes int) {
+	s.Bypass = Bypass{
+		StartedAt:       time.Now(),
+		DurationMinutes: minutes,
+		Reason:          reason,
+		PreviousStage:   s.CurrentStage,
+	}
+	if s.CurrentStage == "green" && !s.Timer.Paused && s.PauseTimer() == nil {
+		s.Bypass.PausedTimer = true
+	}
+	s.CurrentStage = "bypass"
+}
+
+// BypassExpiresAt returns when the active bypass runs out
+func (s *State) BypassExpiresAt() time.Time {
+	return s.Bypass.StartedAt.Add(time.Duration(s.Bypass.DurationMinutes) * time.Minute)
+}
+
+// BypassRemaining returns the time left in the active bypass
+func (s *State) BypassRemaining() time.Duration {
+	remaining := time.Until(s.BypassExpiresAt())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// BypassExpired reports whether the active bypass has run out
+func (s *State) BypassExpired() bool {
+	return s.CurrentStage == "bypass" && !time.Now().Before(s.BypassExpiresAt())
+}
+
+// EndBypass leaves the bypass stage, restoring the previous stage and
+// resuming the timer if the bypass paused it
+func (s *State) EndBypass() {
+	stage := s.Bypass.PreviousStage
+	if stage == "" || stage == "bypass" {
+		stage = "none"
+	}
+	s.CurrentStage = stage
+	if s.Bypass.PausedTimer {
+		s.ResumeTimer()
+	}
+	s.Bypass = Bypass{}
+}
+
+// HasTask reports whether there is an active task
+func (s *State) HasTask() bool {
+	return s.CurrentTaskID != "" && s.CurrentStage != "none" && s.CurrentStage != ""
+}
+
+// ParkTask saves the active task's stage and timer under its ID and clears
+// the active slot. A running GREEN LIGHT timer is paused while parked. An
+// ID that is already parked is refused, so neither task is lost.
+func (s *State) ParkTask() error {
+	if s.CurrentStage == "bypass" {
+		return fmt.Errorf("can't park a task during an emergency bypass")
+	}
+	if !s.HasTask() {
+		return fmt.Errorf("no active task to park")
+	}
+	if _, ok := s.Tasks[s.CurrentTaskID]; ok {
+		return fmt.Errorf("a task %s is already parked; finish or switch to it first", s.CurrentTaskID)
+	}
+
+	parked := TaskState{
+		Stage:     s.CurrentStage,
+		Repo:      s.CurrentTaskRepo,
+		BacklogID: s.CurrentBacklogID,
+		ParkedAt:  time.Now(),
+	}
+	if s.CurrentStage == "green" && !s.Timer.StartedAt.IsZero() && !s.Timer.Paused && s.PauseTimer() == nil {
+		parked.PausedTimer = true
+	}
+	parked.Timer = s.Timer
+
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]TaskState)
+	}
+	s.Tasks[s.CurrentTaskID] = parked
+
+	s.CurrentStage = "none"
+	s.CurrentTaskID = ""
+	s.CurrentTaskRepo = ""
+	s.CurrentBacklogID = ""
+	s.Timer = Timer{}
+	return nil
+}
+
+// UnparkTask makes a parked task the active one, resuming its timer if
+// parking paused it. The active slot must be empty.
+func (s *State) UnparkTask(id string) error {
+	parked, ok := s.Tasks[id]
+	if !ok {
+		return fmt.Errorf("no parked task %q", id)
+	}
+	if s.HasTask() {
+		return fmt.Errorf("task %s is still active, park it first", s.CurrentTaskID)
+	}
+
+	s.CurrentStage = parked.Stage
+	s.CurrentTaskID = id
+	s.CurrentTaskRepo = parked.Repo
+	s.CurrentBacklogID = parked.BacklogID
+	s.Timer = parked.Timer
+	if parked.PausedTimer {
+		s.ResumeTimer()
+	}
+
+	delete(s.Tasks, id)
+	return nil
+}
+
+// ParkedTaskIDs returns the parked task IDs, oldest first
+func (s *State) ParkedTaskIDs() []string {
+	ids := make([]string, 0, len(s.Tasks))
+	for id := range s.Tasks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := s.Tasks[ids[i]], s.Tasks[ids[j]]
+		if !a.ParkedAt.Equal(b.ParkedAt) {
+			return a.ParkedAt.Before(b.ParkedAt)
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
 // SetStage sets the current stage
 func (s *State) SetStage(stage string) {
 	s.CurrentStage = stage
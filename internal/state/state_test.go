@@ -1,8 +1,10 @@
 package state
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -37,8 +39,8 @@ func setupTestWorkspace(t *testing.T) (string, func()) {
 func TestNewState(t *testing.T) {
 	s := NewState()
 
-	if s.Version != "1.0.0" {
-		t.Errorf("Expected version 1.0.0, got %s", s.Version)
+	if s.Version != SchemaVersion {
+		t.Errorf("Expected version %s, got %s", SchemaVersion, s.Version)
 	}
 
 	if s.CurrentStage != "none" {
@@ -115,6 +117,11 @@ func TestTimerOperations(t *testing.T) {
 	if s.Timer.Extensions[0].Reason != "test reason" {
 		t.Errorf("Expected reason 'test reason', got %s", s.Timer.Extensions[0].Reason)
 	}
+
+	s.ExtendTimer(0.5, "another reason")
+	if s.TotalExtensionHours() != 1.5 {
+		t.Errorf("Expected 1.5 extension hours, got %f", s.TotalExtensionHours())
+	}
 }
 
 func TestTimerProgress(t *testing.T) {
@@ -133,6 +140,199 @@ func TestTimerProgress(t *testing.T) {
 	}
 }
 
+func TestPauseResume(t *testing.T) {
+	s := NewState()
+
+	if err := s.PauseTimer(); err == nil {
+		t.Error("Expected error pausing a timer that isn't running")
+	}
+
+	// Two hours ago: worked 30m, paused 1h, working again for 30m
+	start := time.Now().Add(-2 * time.Hour)
+	s.StartTimer(2.0)
+	s.Timer.StartedAt = start
+	s.Timer.Intervals = []Interval{
+		{Start: start, End: start.Add(30 * time.Minute)},
+		{Start: start.Add(90 * time.Minute)},
+	}
+
+	elapsed := s.GetElapsed()
+	if elapsed < 59*time.Minute || elapsed > 61*time.Minute {
+		t.Errorf("Expected elapsed ~1h excluding pause, got %v", elapsed)
+	}
+
+	if err := s.PauseTimer(); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	if !s.Timer.Paused {
+		t.Error("Expected timer to be paused")
+	}
+	if err := s.PauseTimer(); err == nil {
+		t.Error("Expected error pausing twice")
+	}
+	if s.PausedSince().IsZero() {
+		t.Error("Expected paused-since time to be set")
+	}
+
+	// Time passing while paused must not count
+	s.Timer.Intervals[1].End = s.Timer.Intervals[1].End.Add(-10 * time.Minute)
+	pausedElapsed := s.GetElapsed()
+
+	if err := s.ResumeTimer(); err != nil {
+		t.Fatalf("Failed to resume: %v", err)
+	}
+	if s.Timer.Paused {
+		t.Error("Expected timer to be running after resume")
+	}
+	if len(s.Timer.Intervals) != 3 {
+		t.Errorf("Expected 3 intervals, got %d", len(s.Timer.Intervals))
+	}
+	if got := s.GetElapsed(); got-pausedElapsed > time.Second {
+		t.Errorf("Expected resume to add no time, went from %v to %v", pausedElapsed, got)
+	}
+	if err := s.ResumeTimer(); err == nil {
+		t.Error("Expected error resuming a running timer")
+	}
+}
+
+func TestLegacyTimerPause(t *testing.T) {
+	s := NewState()
+	s.Timer.StartedAt = time.Now().Add(-1 * time.Hour)
+	s.Timer.ThresholdHours = 2.0
+
+	if err := s.PauseTimer(); err != nil {
+		t.Fatalf("Failed to pause legacy timer: %v", err)
+	}
+
+	if len(s.Timer.Intervals) != 1 {
+		t.Fatalf("Expected legacy start converted to 1 interval, got %d", len(s.Timer.Intervals))
+	}
+
+	elapsed := s.GetElapsed()
+	if elapsed < 59*time.Minute || elapsed > 61*time.Minute {
+		t.Errorf("Expected elapsed ~1h, got %v", elapsed)
+	}
+}
+
+func TestBypassLifecycle(t *testing.T) {
+	s := NewState()
+	s.SetStage("green")
+	s.StartTimer(2.0)
+
+	s.StartBypass("prod down", 30)
+
+	if s.CurrentStage != "bypass" {
+		t.Errorf("Expected stage 'bypass', got %s", s.CurrentStage)
+	}
+	if !s.Timer.Paused || !s.Bypass.PausedTimer {
+		t.Error("Expected bypass to pause the running timer")
+	}
+	if s.BypassExpired() {
+		t.Error("Expected fresh bypass not to be expired")
+	}
+	if r := s.BypassRemaining(); r < 29*time.Minute || r > 30*time.Minute {
+		t.Errorf("Expected ~30m remaining, got %v", r)
+	}
+
+	s.Bypass.StartedAt = time.Now().Add(-31 * time.Minute)
+	if !s.BypassExpired() {
+		t.Error("Expected bypass to be expired")
+	}
+	if s.BypassRemaining() != 0 {
+		t.Errorf("Expected 0 remaining, got %v", s.BypassRemaining())
+	}
+
+	s.EndBypass()
+
+	if s.CurrentStage != "green" {
+		t.Errorf("Expected stage restored to 'green', got %s", s.CurrentStage)
+	}
+	if s.Timer.Paused {
+		t.Error("Expected timer to be resumed")
+	}
+	if !s.Bypass.StartedAt.IsZero() {
+		t.Error("Expected bypass to be cleared")
+	}
+}
+
+func TestParkAndUnparkTask(t *testing.T) {
+	s := NewState()
+	s.SetStage("green")
+	s.CurrentTaskID = "fix_login"
+	s.CurrentTaskRepo = "/code/app"
+	s.StartTimer(2.0)
+
+	if err := s.ParkTask(); err != nil {
+		t.Fatalf("ParkTask failed: %v", err)
+	}
+
+	if s.HasTask() || s.CurrentStage != "none" || s.CurrentTaskID != "" {
+		t.Errorf("Expected empty active slot, got stage=%s task=%s", s.CurrentStage, s.CurrentTaskID)
+	}
+	parked, ok := s.Tasks["fix_login"]
+	if !ok {
+		t.Fatal("Expected fix_login to be parked")
+	}
+	if parked.Stage != "green" || !parked.Timer.Paused || !parked.PausedTimer {
+		t.Errorf("Expected parked green task with paused timer, got %+v", parked)
+	}
+
+	// A second task can start while the first is parked
+	s.SetStage("red")
+	s.CurrentTaskID = "hotfix"
+	if err := s.UnparkTask("fix_login"); err == nil {
+		t.Error("Expected UnparkTask to fail while another task is active")
+	}
+	if err := s.ParkTask(); err != nil {
+		t.Fatalf("ParkTask failed: %v", err)
+	}
+	if ids := s.ParkedTaskIDs(); len(ids) != 2 || ids[0] != "fix_login" {
+		t.Errorf("Expected [fix_login hotfix], got %v", ids)
+	}
+
+	if err := s.UnparkTask("fix_login"); err != nil {
+		t.Fatalf("UnparkTask failed: %v", err)
+	}
+	if s.CurrentTaskID != "fix_login" || s.CurrentStage != "green" || s.CurrentTaskRepo != "/code/app" {
+		t.Errorf("Expected fix_login restored, got %s/%s/%s", s.CurrentTaskID, s.CurrentStage, s.CurrentTaskRepo)
+	}
+	if s.Timer.Paused {
+		t.Error("Expected timer to resume after unparking")
+	}
+	if _, ok := s.Tasks["fix_login"]; ok {
+		t.Error("Expected fix_login to be removed from parked tasks")
+	}
+
+	if err := s.UnparkTask("missing"); err == nil {
+		t.Error("Expected error for unknown task")
+	}
+}
+
+func TestParkTaskRefusesDuplicateID(t *testing.T) {
+	s := NewState()
+	s.SetStage("green")
+	s.CurrentTaskID = "fix_login"
+	if err := s.ParkTask(); err != nil {
+		t.Fatalf("ParkTask failed: %v", err)
+	}
+
+	s.SetStage("red")
+	s.CurrentTaskID = "fix_login"
+	if err := s.ParkTask(); err == nil {
+		t.Error("Expected ParkTask to refuse an ID that is already parked")
+	}
+	if s.CurrentTaskID != "fix_login" || s.Tasks["fix_login"].Stage != "green" {
+		t.Error("Expected the active and parked tasks to be left alone")
+	}
+}
+
+func TestParkTaskWithoutTask(t *testing.T) {
+	s := NewState()
+	if err := s.ParkTask(); err == nil {
+		t.Error("Expected error parking with no active task")
+	}
+}
+
 func TestSessionOperations(t *testing.T) {
 	s := NewState()
 
@@ -189,3 +389,87 @@ func TestBypassCounterReset(t *testing.T) {
 		t.Errorf("Expected last reset to be today, got %s", s.EmergencyBypasses.LastReset)
 	}
 }
+
+func TestGetYoDirDiscovery(t *testing.T) {
+	tmpDir, cleanup := setupTestWorkspace(t)
+	defer cleanup()
+	t.Setenv(YoDirEnv, "")
+
+	if err := NewState().Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// A subdirectory finds the workspace above it
+	sub := filepath.Join(tmpDir, "src", "pkg")
+	os.MkdirAll(sub, 0755)
+	os.Chdir(sub)
+
+	yoDir, err := GetYoDir()
+	if err != nil {
+		t.Fatalf("GetYoDir failed: %v", err)
+	}
+	if yoDir != filepath.Join(tmpDir, ".yo") {
+		t.Errorf("Expected %s, got %s", filepath.Join(tmpDir, ".yo"), yoDir)
+	}
+
+	// A .yo without state.json (like ~/.yo) is skipped
+	os.MkdirAll(filepath.Join(tmpDir, "src", ".yo"), 0755)
+	if yoDir, _ := GetYoDir(); yoDir != filepath.Join(tmpDir, ".yo") {
+		t.Errorf("Expected %s, got %s", filepath.Join(tmpDir, ".yo"), yoDir)
+	}
+
+	// YO_DIR wins over discovery
+	other := filepath.Join(tmpDir, "elsewhere", ".yo")
+	t.Setenv(YoDirEnv, other)
+	if yoDir, _ := GetYoDir(); yoDir != other {
+		t.Errorf("Expected %s, got %s", other, yoDir)
+	}
+}
+
+func TestUpdateConcurrent(t *testing.T) {
+	tmpDir, cleanup := setupTestWorkspace(t)
+	defer cleanup()
+
+	yoDir := filepath.Join(tmpDir, ".yo")
+	if err := NewState().SaveTo(yoDir); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := UpdateIn(yoDir, func(s *State) error {
+				if s.Tasks == nil {
+					s.Tasks = make(map[string]TaskState)
+				}
+				s.Tasks[fmt.Sprintf("task_%d", i)] = TaskState{Stage: "red"}
+				return nil
+			})
+			if err != nil {
+				t.Errorf("UpdateIn failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	s, err := LoadFrom(yoDir)
+	if err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if len(s.Tasks) != workers {
+		t.Errorf("Expected %d tasks, got %d (lost updates)", workers, len(s.Tasks))
+	}
+
+	// ErrNoChange skips the save
+	before, _ := os.Stat(filepath.Join(yoDir, "state.json"))
+	if _, err := UpdateIn(yoDir, func(s *State) error { return ErrNoChange }); err != nil {
+		t.Errorf("Expected ErrNoChange to be swallowed, got %v", err)
+	}
+	after, _ := os.Stat(filepath.Join(yoDir, "state.json"))
+	if !after.ModTime().Equal(before.ModTime()) {
+		t.Error("Expected state.json to be untouched")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/debt"
 	"github.com/faisalahmedsifat/yo/internal/state"
 )
 
@@ -22,6 +23,29 @@ type WeekStats struct {
 	OnTaskChanges  int       `json:"on_task_changes"`
 	TotalChanges   int       `json:"total_changes"`
 	TotalHours     float64   `json:"total_hours"`
+	Extensions     int       `json:"extensions"`
+	ExtensionHours float64   `json:"extension_hours"`
+	Debt           DebtStats `json:"debt"`
+}
+
+// DebtStats is the tech debt side of a week
+type DebtStats struct {
+	Created          int     `json:"created"`
+	Resolved         int     `json:"resolved"`
+	Outstanding      int     `json:"outstanding"`       // at the end of the week
+	OutstandingHours float64 `json:"outstanding_hours"` // their estimated fix time
+}
+
+// Debt works out the tech debt stats for the week [start, end)
+func Debt(l *debt.Log, start, end time.Time) DebtStats {
+	flow := l.Flow(start, end)
+	outstanding, hours := l.OutstandingAt(end)
+	return DebtStats{
+		Created:          flow.Created,
+		Resolved:         flow.Resolved,
+		Outstanding:      outstanding,
+		OutstandingHours: hours,
+	}
 }
 
 // Calculate generates stats from activity entries
@@ -45,7 +69,11 @@ func Calculate(entries []activity.Entry) *WeekStats {
 		case activity.TypeEmergencyBypass:
 			stats.Bypasses++
 
-		case activity.TypeFileChange:
+		case activity.TypeTimerExtension:
+			stats.Extensions++
+			stats.ExtensionHours += e.Hours
+
+		case activity.TypeFileChange, activity.TypeFileRename, activity.TypeFileDelete:
 			stats.TotalChanges++
 			if !e.Untracked {
 				stats.OnTaskChanges++
@@ -95,6 +123,10 @@ func ForWeek(weekStart time.Time) (*WeekStats, error) {
 	stats.WeekStart = start
 	stats.WeekEnd = end
 
+	if l, err := debt.Load(); err == nil {
+		stats.Debt = Debt(l, start, end)
+	}
+
 	return stats, nil
 }
 
@@ -173,6 +205,16 @@ func GenerateInsights(s *WeekStats) *Insights {
 		insights.Messages = append(insights.Messages, "🚨 Too many emergency bypasses - improve planning")
 	}
 
+	// Extension insights
+	if s.TasksCompleted > 0 && s.Extensions > s.TasksCompleted {
+		insights.Messages = append(insights.Messages, "⏱️ More extensions than tasks - plan in smaller pieces")
+	}
+
+	// Tech debt insights
+	if s.Debt.Created >= 3 && s.Debt.Created > 2*s.Debt.Resolved {
+		insights.Messages = append(insights.Messages, "🧾 Deferring debt faster than paying it back - see yo debt report")
+	}
+
 	// Productivity insights
 	if s.TasksCompleted == 0 {
 		insights.Messages = append(insights.Messages, "💡 No tasks completed - break work into smaller chunks")
@@ -12,6 +12,8 @@ func TestCalculate(t *testing.T) {
 		{Type: activity.TypeTaskComplete, ActualHours: 4.0, EstimatedHours: 4.0},
 		{Type: activity.TypeTaskComplete, ActualHours: 3.0, EstimatedHours: 2.0},
 		{Type: activity.TypeEmergencyBypass},
+		{Type: activity.TypeTimerExtension, Hours: 1.0},
+		{Type: activity.TypeTimerExtension, Hours: 0.5},
 		{Type: activity.TypeFileChange, Untracked: false},
 		{Type: activity.TypeFileChange, Untracked: false},
 		{Type: activity.TypeFileChange, Untracked: true},
@@ -27,6 +29,14 @@ func TestCalculate(t *testing.T) {
 		t.Errorf("Expected 1 bypass, got %d", stats.Bypasses)
 	}
 
+	if stats.Extensions != 2 {
+		t.Errorf("Expected 2 extensions, got %d", stats.Extensions)
+	}
+
+	if stats.ExtensionHours != 1.5 {
+		t.Errorf("Expected 1.5 extension hours, got %f", stats.ExtensionHours)
+	}
+
 	if stats.TotalChanges != 3 {
 		t.Errorf("Expected 3 total changes, got %d", stats.TotalChanges)
 	}
@@ -109,6 +119,18 @@ func TestGenerateInsights(t *testing.T) {
 	if len(insights.Messages) < 3 {
 		t.Error("Expected multiple warnings for bad stats")
 	}
+
+	// Debt piling up
+	piling := &WeekStats{FocusScore: 100, Debt: DebtStats{Created: 4, Resolved: 1}}
+	found = false
+	for _, msg := range GenerateInsights(piling).Messages {
+		if contains(msg, "yo debt report") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a tech debt insight when deferring outpaces resolving")
+	}
 }
 
 func contains(s, substr string) bool {
@@ -0,0 +1,61 @@
+package statusline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// snippets set up 'yo prompt' in each shell or status bar
+var snippets = map[string]string{
+	"bash": `# yo: show the stage and timer in your prompt (add to ~/.bashrc)
+__yo_prompt() {
+  local s
+  s="$(yo prompt 2>/dev/null)"
+  [ -n "$s" ] && printf '%s ' "$s"
+}
+PS1='$(__yo_prompt)'"$PS1"
+`,
+	"zsh": `# yo: show the stage and timer in your prompt (add to ~/.zshrc)
+setopt PROMPT_SUBST
+__yo_prompt() {
+  local s
+  s="$(yo prompt 2>/dev/null)"
+  [[ -n $s ]] && print -rn -- "${s//\%/%%} "
+}
+PROMPT='$(__yo_prompt)'"$PROMPT"
+`,
+	"fish": `# yo: show the stage and timer in your prompt
+# (add to ~/.config/fish/config.fish)
+functions -q __yo_fish_prompt; or functions -c fish_prompt __yo_fish_prompt
+function fish_prompt
+    set -l s (yo prompt 2>/dev/null)
+    test -n "$s"; and printf '%s ' $s
+    __yo_fish_prompt
+end
+`,
+	"tmux": `# yo: show the stage and timer of the active pane's workspace
+# (add to ~/.tmux.conf)
+set -g status-interval 15
+set -g status-right '#(cd "#{pane_current_path}" && yo prompt) %H:%M'
+`,
+}
+
+// Snippet returns the setup snippet for a shell or tmux
+func Snippet(shell string) (string, error) {
+	s, ok := snippets[strings.ToLower(shell)]
+	if !ok {
+		return "", fmt.Errorf("no snippet for %q (use %s)", shell, strings.Join(Shells(), ", "))
+	}
+	return s, nil
+}
+
+// Shells lists the targets Snippet supports
+func Shells() []string {
+	var names []string
+	for name := range snippets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,253 @@
+// Package statusline renders the compact stage and timer segment that
+// 'yo prompt' prints into shell prompts and status bars.
+package statusline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/fileutil"
+	"github.com/faisalahmedsifat/yo/internal/state"
+)
+
+// DefaultFormat is the segment printed without --format, e.g.
+// "🟢 fix_login 42m/2h"
+const DefaultFormat = "{icon} {task} {timer}"
+
+// FormatEnv overrides DefaultFormat
+const FormatEnv = "YO_PROMPT_FORMAT"
+
+// cacheFile sits next to state.json and holds the last snapshot taken of it
+const cacheFile = "prompt_cache.json"
+
+// cacheVersion changes with the Snapshot fields, so older caches are
+// refreshed rather than read with fields missing
+const cacheVersion = 2
+
+// Snapshot is the part of state.json a prompt needs. Elapsed time is kept
+// as work done so far plus the start of the running interval, so a cached
+// snapshot stays correct as the clock moves.
+type Snapshot struct {
+	Stage          string    `json:"stage"`
+	TaskID         string    `json:"task_id"`
+	ThresholdHours float64   `json:"threshold_hours"` // estimate plus extensions
+	TimerStarted   bool      `json:"timer_started"`
+	Paused         bool      `json:"paused"`
+	Worked         int64     `json:"worked"`        // nanoseconds in closed intervals
+	RunningSince   time.Time `json:"running_since"` // zero when paused
+	BypassExpires  time.Time `json:"bypass_expires"`
+}
+
+// cache is the on-disk cache, valid while state.json is unchanged
+type cache struct {
+	Version      int      `json:"version"` // cacheVersion it was written with
+	StateModTime int64    `json:"state_mod_time"`
+	StateSize    int64    `json:"state_size"`
+	Snapshot     Snapshot `json:"snapshot"`
+}
+
+// FromState takes a snapshot of s
+func FromState(s *state.State) Snapshot {
+	snap := Snapshot{
+		Stage:          s.CurrentStage,
+		TaskID:         s.CurrentTaskID,
+		ThresholdHours: s.Timer.ThresholdHours,
+		TimerStarted:   !s.Timer.StartedAt.IsZero(),
+		Paused:         s.Timer.Paused,
+	}
+	if snap.ThresholdHours == 0 {
+		// Timers started before thresholds were kept
+		snap.ThresholdHours = s.Timer.EstimatedHours
+	}
+	if snap.Stage == "" {
+		snap.Stage = "none"
+	}
+	if snap.Stage == "bypass" {
+		snap.BypassExpires = s.BypassExpiresAt()
+	}
+
+	if len(s.Timer.Intervals) == 0 {
+		// Timers started before intervals were tracked have one open period
+		if snap.TimerStarted && !snap.Paused {
+			snap.RunningSince = s.Timer.StartedAt
+		}
+		return snap
+	}
+	for _, iv := range s.Timer.Intervals {
+		if iv.End.IsZero() {
+			snap.RunningSince = iv.Start
+			continue
+		}
+		snap.Worked += int64(iv.End.Sub(iv.Start))
+	}
+	return snap
+}
+
+// Load returns the snapshot of the workspace in yoDir. It reads the cache
+// when state.json hasn't changed since it was written, and refreshes it
+// otherwise; a cache that can't be written is not an error.
+func Load(yoDir string) (*Snapshot, error) {
+	statePath := filepath.Join(yoDir, "state.json")
+	info, err := os.Stat(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, state.ErrNotInitialized
+		}
+		return nil, err
+	}
+
+	cachePath := filepath.Join(yoDir, cacheFile)
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var c cache
+		if json.Unmarshal(data, &c) == nil && c.Version == cacheVersion && c.StateModTime == info.ModTime().UnixNano() && c.StateSize == info.Size() {
+			return &c.Snapshot, nil
+		}
+	}
+
+	s, err := state.LoadFrom(yoDir)
+	if err != nil {
+		return nil, err
+	}
+	snap := FromState(s)
+
+	c := cache{Version: cacheVersion, StateModTime: info.ModTime().UnixNano(), StateSize: info.Size(), Snapshot: snap}
+	if data, err := json.Marshal(c); err == nil {
+		fileutil.WriteFile(cachePath, data, 0644)
+	}
+	return &snap, nil
+}
+
+// Elapsed returns the active time on the timer at now
+func (s Snapshot) Elapsed(now time.Time) time.Duration {
+	elapsed := time.Duration(s.Worked)
+	if !s.RunningSince.IsZero() {
+		elapsed += now.Sub(s.RunningSince)
+	}
+	return elapsed
+}
+
+var placeholder = regexp.MustCompile(`\{[a-z]+\}`)
+
+// Render fills in the placeholders of format:
+//
+//	{icon}      stage light: 🔴 🟡 🟢 🚨, or ⚪ without a task
+//	{stage}     none, red, yellow, green or bypass
+//	{task}      task ID without its short suffix, e.g. fix_login
+//	{id}        full task ID
+//	{elapsed}   time on the GREEN timer, e.g. 42m or 1h05m
+//	{estimate}  GREEN threshold, the estimate plus extensions, e.g. 2h
+//	{progress}  percent of the threshold, e.g. 35%
+//	{paused}    ⏸ while the timer is paused
+//	{timer}     42m/2h in GREEN (⏸ prefixed when paused), 12m left in a bypass
+//
+// Unknown placeholders are kept as written. Runs of spaces left by empty
+// placeholders are collapsed and the result is trimmed.
+func (s Snapshot) Render(format string, now time.Time) string {
+	out := placeholder.ReplaceAllStringFunc(format, func(p string) string {
+		v, ok := s.value(p[1:len(p)-1], now)
+		if !ok {
+			return p
+		}
+		return v
+	})
+	return strings.Join(strings.Fields(out), " ")
+}
+
+func (s Snapshot) value(name string, now time.Time) (string, bool) {
+	green := s.Stage == "green" && s.TimerStarted
+	switch name {
+	case "icon":
+		return stageIcon(s.Stage), true
+	case "stage":
+		return s.Stage, true
+	case "task":
+		return shortTaskID(s.TaskID), true
+	case "id":
+		return s.TaskID, true
+	case "elapsed":
+		if !green {
+			return "", true
+		}
+		return compact(s.Elapsed(now)), true
+	case "estimate":
+		if !green {
+			return "", true
+		}
+		return compact(s.threshold()), true
+	case "progress":
+		if !green || s.ThresholdHours <= 0 {
+			return "", true
+		}
+		return fmt.Sprintf("%.0f%%", s.Elapsed(now).Hours()/s.ThresholdHours*100), true
+	case "paused":
+		if green && s.Paused {
+			return "⏸", true
+		}
+		return "", true
+	case "timer":
+		switch {
+		case s.Stage == "bypass" && !s.BypassExpires.IsZero():
+			remaining := s.BypassExpires.Sub(now)
+			if remaining <= 0 {
+				return "expired", true
+			}
+			return compact(remaining) + " left", true
+		case !green:
+			return "", true
+		}
+		t := compact(s.Elapsed(now)) + "/" + compact(s.threshold())
+		if s.Paused {
+			t = "⏸ " + t
+		}
+		return t, true
+	}
+	return "", false
+}
+
+// threshold is the time the GREEN timer allows, as timer.GetStatus has it
+func (s Snapshot) threshold() time.Duration {
+	return time.Duration(s.ThresholdHours * float64(time.Hour))
+}
+
+func stageIcon(stage string) string {
+	switch stage {
+	case "red":
+		return "🔴"
+	case "yellow":
+		return "🟡"
+	case "green":
+		return "🟢"
+	case "bypass":
+		return "🚨"
+	}
+	return "⚪"
+}
+
+var idSuffix = regexp.MustCompile(`_[0-9a-f]{6}$`)
+
+// shortTaskID drops the random suffix task.MakeID appends
+func shortTaskID(id string) string {
+	if short := idSuffix.ReplaceAllString(id, ""); short != "" && !strings.HasPrefix(id, "task_") {
+		return short
+	}
+	return id
+}
+
+// compact formats a duration for tight spaces: 45m, 2h, 1h05m
+func compact(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	m := (d - h*time.Hour) / time.Minute
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
@@ -0,0 +1,136 @@
+package statusline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/state"
+)
+
+func TestRender(t *testing.T) {
+	now := time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+	green := Snapshot{
+		Stage:          "green",
+		TaskID:         "fix_login_a1b2c3",
+		ThresholdHours: 2,
+		TimerStarted:   true,
+		Worked:         int64(30 * time.Minute),
+		RunningSince:   now.Add(-12 * time.Minute),
+	}
+	paused := green
+	paused.Paused = true
+	paused.RunningSince = time.Time{}
+
+	tests := []struct {
+		name     string
+		snap     Snapshot
+		format   string
+		expected string
+	}{
+		{"green", green, DefaultFormat, "🟢 fix_login 42m/2h"},
+		{"paused", paused, DefaultFormat, "🟢 fix_login ⏸ 30m/2h"},
+		{"progress", green, "{stage}:{id} {progress}", "green:fix_login_a1b2c3 35%"},
+		{"red has no timer", Snapshot{Stage: "red", TaskID: "task_a1b2c3"}, DefaultFormat, "🔴 task_a1b2c3"},
+		{"idle", Snapshot{Stage: "none"}, DefaultFormat, "⚪"},
+		{"bypass", Snapshot{Stage: "bypass", BypassExpires: now.Add(90 * time.Minute)}, "{icon} {timer}", "🚨 1h30m left"},
+		{"unknown placeholder", green, "{task} {nope}", "fix_login {nope}"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.snap.Render(tt.format, now); got != tt.expected {
+			t.Errorf("%s: Render(%q) = %q, expected %q", tt.name, tt.format, got, tt.expected)
+		}
+	}
+}
+
+func TestFromState(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	s := state.NewState()
+	s.CurrentStage = "green"
+	s.CurrentTaskID = "fix_login_a1b2c3"
+	s.Timer.StartedAt = start
+	s.Timer.EstimatedHours = 2
+	s.Timer.Intervals = []state.Interval{
+		{Start: start, End: start.Add(20 * time.Minute)},
+		{Start: start.Add(40 * time.Minute)},
+	}
+
+	snap := FromState(s)
+	if got := snap.Elapsed(time.Now()).Round(time.Minute); got != s.GetElapsed().Round(time.Minute) {
+		t.Errorf("Elapsed = %v, expected %v", got, s.GetElapsed())
+	}
+}
+
+func TestFromStateExtended(t *testing.T) {
+	s := state.NewState()
+	s.CurrentStage = "green"
+	s.CurrentTaskID = "login_broken_for_users_a1b2c3"
+	s.StartTimer(0.5)
+	s.ExtendTimer(0.5, "flaky test")
+	s.Timer.Intervals = []state.Interval{{Start: time.Now().Add(-15 * time.Minute)}}
+
+	snap := FromState(s)
+	if snap.ThresholdHours != s.Timer.ThresholdHours || snap.ThresholdHours != 1 {
+		t.Fatalf("ThresholdHours = %v, expected the extended %v", snap.ThresholdHours, s.Timer.ThresholdHours)
+	}
+	now := time.Now()
+	if got := snap.Render("{timer} {estimate} {progress}", now); got != "15m/1h 1h 25%" {
+		t.Errorf("Render = %q, expected the extended threshold", got)
+	}
+	if progress := fmt.Sprintf("%.0f%%", s.GetProgress()); progress != "25%" {
+		t.Errorf("GetProgress = %s, expected it to match the prompt", progress)
+	}
+
+	// Timers from before thresholds were kept fall back to the estimate
+	s.Timer.ThresholdHours = 0
+	s.Timer.EstimatedHours = 2
+	if snap := FromState(s); snap.ThresholdHours != 2 {
+		t.Errorf("Expected the estimate as a fallback, got %v", snap.ThresholdHours)
+	}
+}
+
+func TestLoadCache(t *testing.T) {
+	yoDir := t.TempDir()
+	if _, err := Load(yoDir); err != state.ErrNotInitialized {
+		t.Fatalf("Expected ErrNotInitialized, got %v", err)
+	}
+
+	s := state.NewState()
+	s.CurrentStage = "red"
+	s.CurrentTaskID = "fix_login_a1b2c3"
+	if err := s.SaveTo(yoDir); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+
+	snap, err := Load(yoDir)
+	if err != nil || snap.Stage != "red" {
+		t.Fatalf("Load = %+v, %v", snap, err)
+	}
+	if _, err := os.Stat(filepath.Join(yoDir, cacheFile)); err != nil {
+		t.Fatalf("Expected a cache file: %v", err)
+	}
+
+	// A changed state.json invalidates the cache
+	s.CurrentStage = "yellow"
+	if err := s.SaveTo(yoDir); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+	os.Chtimes(filepath.Join(yoDir, "state.json"), time.Now(), time.Now().Add(time.Second))
+	if snap, _ := Load(yoDir); snap.Stage != "yellow" {
+		t.Errorf("Expected a fresh snapshot, got stage %q", snap.Stage)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "tmux"} {
+		if s, err := Snippet(shell); err != nil || s == "" {
+			t.Errorf("Snippet(%q) = %q, %v", shell, s, err)
+		}
+	}
+	if _, err := Snippet("powershell"); err == nil {
+		t.Error("Expected an error for an unsupported shell")
+	}
+}
@@ -0,0 +1,76 @@
+package task
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// maxSlugLength caps the readable part of a task ID
+const maxSlugLength = 30
+
+// NewShortID returns a random 6-character hex ID
+func NewShortID() string {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to the clock; still unique enough for one workspace
+		n := time.Now().UnixNano()
+		b = []byte{byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+	return hex.EncodeToString(b)
+}
+
+// MakeID builds a task ID from a description: a readable slug plus a short
+// suffix (the backlog item's ID when there is one, otherwise a new random
+// one) so two similarly named tasks never share an ID
+func MakeID(description, suffix string) string {
+	if suffix == "" {
+		suffix = NewShortID()
+	}
+
+	slug := Slugify(description)
+	if slug == "" {
+		return "task_" + suffix
+	}
+	return slug + "_" + suffix
+}
+
+// Slugify lowercases a description and keeps only letters, digits and
+// underscores, truncated to a readable length
+func Slugify(s string) string {
+	s = strings.ToLower(s)
+	s = strings.ReplaceAll(s, " ", "_")
+
+	var result strings.Builder
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			result.WriteRune(r)
+		}
+	}
+
+	slug := result.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+	}
+	return strings.Trim(slug, "_")
+}
+
+// IDSuffix returns the short hex suffix MakeID appended to an ID, or ""
+// for IDs without one
+func IDSuffix(id string) string {
+	i := strings.LastIndex(id, "_")
+	if i == -1 {
+		return ""
+	}
+	suffix := id[i+1:]
+	if len(suffix) != 6 {
+		return ""
+	}
+	for _, r := range suffix {
+		if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f')) {
+			return ""
+		}
+	}
+	return suffix
+}
@@ -0,0 +1,60 @@
+package task
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Fix Login Bug", "fix_login_bug"},
+		{"OAuth: callback 500s!", "oauth_callback_500s"},
+		{"a very long description that keeps going on", "a_very_long_description_that_k"},
+		{"???", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Slugify(tt.input); got != tt.expected {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestMakeID(t *testing.T) {
+	if id := MakeID("Fix login bug", "a1b2c3"); id != "fix_login_bug_a1b2c3" {
+		t.Errorf("Expected fix_login_bug_a1b2c3, got %s", id)
+	}
+
+	// Same description without a backlog ID still gets distinct IDs
+	a := MakeID("Fix login bug", "")
+	b := MakeID("Fix login bug", "")
+	if a == b {
+		t.Errorf("Expected distinct IDs, got %s twice", a)
+	}
+	if !strings.HasPrefix(a, "fix_login_bug_") {
+		t.Errorf("Expected readable prefix, got %s", a)
+	}
+
+	if id := MakeID("???", "abc123"); id != "task_abc123" {
+		t.Errorf("Expected task_abc123, got %s", id)
+	}
+}
+
+func TestIDSuffix(t *testing.T) {
+	tests := map[string]string{
+		"fix_login_bug_a1b2c3": "a1b2c3",
+		"task_abc123":          "abc123",
+		"fix_the_button":       "",
+		"fix_login":            "",
+		"nounderscore":         "",
+	}
+
+	for id, expected := range tests {
+		if got := IDSuffix(id); got != expected {
+			t.Errorf("IDSuffix(%s) = %q, expected %q", id, got, expected)
+		}
+	}
+}
@@ -0,0 +1,908 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task is the parsed form of a task file (tasks/<id>.md)
+type Task struct {
+	Title      string
+	Red        RedSection
+	Yellow     YellowSection
+	Green      GreenSection
+	Completion CompletionSection
+
+	// lines holds the original markdown so serializing keeps everything
+	// the parser doesn't model (notes, extra headings, comments)
+	lines []string
+	refs  refs
+}
+
+// RedSection is the RED LIGHT problem definition
+type RedSection struct {
+	Present  bool
+	Problem  string
+	Impact   []Checkbox
+	Severity []Checkbox
+}
+
+// YellowSection is the YELLOW LIGHT analysis and plan
+type YellowSection struct {
+	Present         bool
+	ImmediateCause  string
+	UnderlyingCause string
+	SystemCause     string
+	Options         []Option
+	ChosenOption    string
+	Reason          string
+	Steps           []string
+	Criteria        []Criterion
+}
+
+// GreenSection is the GREEN LIGHT execution log
+type GreenSection struct {
+	Present       bool
+	TimerStarted  string
+	EstimatedTime string
+	Notes         string
+	Blockers      string
+}
+
+// CompletionSection is filled in when the task is done
+type CompletionSection struct {
+	Present        bool
+	ActualTime     string
+	Accuracy       string
+	LessonsLearned string
+}
+
+// Checkbox is a "- [ ] label" line
+type Checkbox struct {
+	Label   string
+	Checked bool
+	line    int
+}
+
+// Option is a "#### Option X:" solution candidate
+type Option struct {
+	Label         string // A, B, C...
+	Description   string
+	Estimate      string  // as written, e.g. "2h"
+	EstimateHours float64 // 0 when missing or unparseable
+	Pros          string
+	Cons          string
+}
+
+// Criterion is a success criterion checkbox. A criterion checked by a
+// shell command ends with "`cmd: go test ./...`". When it was ticked is
+// kept on its line as "<!-- checked:RFC 3339 time -->".
+type Criterion struct {
+	Text      string
+	Command   string // empty for criteria checked by hand
+	Checked   bool
+	CheckedAt time.Time // zero when unchecked or ticked without a time
+	line      int
+}
+
+// inline is a value written on a single line, e.g. "**Reason:** ..."
+type inline struct {
+	line   int
+	prefix string
+	orig   string
+}
+
+// block is a value spanning the lines under a heading
+type block struct {
+	start, end int // body lines [start, end)
+	orig       string
+}
+
+// span is a range of lines [start, end)
+type span struct {
+	start, end int
+}
+
+// list is a ### subsection whose items the serializer can rewrite
+type list struct {
+	heading int    // line of the heading, -1 when the file doesn't have it
+	end     int    // end of the subsection
+	items   []span // the item lines, blank template items included
+}
+
+// edit replaces lines [start, end) with lines
+type edit struct {
+	start, end int
+	lines      []string
+}
+
+type refs struct {
+	problem      block
+	causes       map[string]*span // label lines and values, by lowercased label
+	rootCause    list
+	options      list
+	steps        list
+	criteria     list
+	yellowEnd    int // where new YELLOW subsections go
+	orig         YellowSection
+	chosen       inline
+	reason       inline
+	timerStarted inline
+	estimated    inline
+	actualTime   inline
+	accuracy     inline
+	checks       map[int]bool // original checked state by line
+	checkedAt    map[int]time.Time
+}
+
+// Section headings
+const (
+	sectionNone = iota
+	sectionRed
+	sectionYellow
+	sectionGreen
+	sectionCompletion
+)
+
+var (
+	checkboxRe  = regexp.MustCompile(`^(\s*-\s*\[)([ xX])(\]\s?)(.*)$`)
+	stepRe      = regexp.MustCompile(`^\s*\d+\.\s*(.*)$`)
+	optionRe    = regexp.MustCompile(`^####\s*Option\s+([^:]*):?\s*(.*)$`)
+	estimateRe  = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*([A-Za-z]+)`)
+	checkedAtRe = regexp.MustCompile(`\s*<!--\s*checked:(\S+)\s*-->\s*$`)
+	commandRe   = regexp.MustCompile("\\s*`cmd:\\s*([^`]+)`\\s*$")
+)
+
+// Load reads and parses a task file
+func Load(path string) (*Task, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task file: %w", err)
+	}
+	return Parse(string(content)), nil
+}
+
+// Save writes the task back to disk
+func (t *Task) Save(path string) error {
+	return os.WriteFile(path, []byte(t.String()), 0644)
+}
+
+// Parse parses task markdown into a Task
+func Parse(content string) *Task {
+	t := &Task{
+		lines: strings.Split(content, "\n"),
+		refs:  newRefs(),
+	}
+
+	section := sectionNone
+	sub := ""       // current ### heading, lowercased
+	label := ""     // current **Label:** inside root cause analysis
+	var opt *Option // current #### Option
+	var cur *list   // current YELLOW list subsection
+	var body []string
+
+	// endList ends the current YELLOW list subsection at line end
+	endList := func(end int) {
+		if cur != nil {
+			cur.end = end
+			cur = nil
+		}
+	}
+
+	// flush ends a multi-line body under a ### heading
+	flush := func(end int) {
+		text := joinBody(body)
+		switch {
+		case section == sectionRed && strings.HasPrefix(sub, "what's the problem"):
+			t.Red.Problem = text
+			t.refs.problem.end = end
+			t.refs.problem.orig = text
+		case section == sectionGreen && strings.HasPrefix(sub, "notes"):
+			t.Green.Notes = text
+		case section == sectionGreen && strings.HasPrefix(sub, "blockers"):
+			t.Green.Blockers = text
+		case section == sectionCompletion && strings.HasPrefix(sub, "lessons learned"):
+			if text != "" {
+				t.Completion.LessonsLearned = text
+			}
+		}
+		body = nil
+	}
+
+	setCause := func(value string) {
+		value = strings.TrimSpace(value)
+		if value == "" || isComment(value) {
+			return
+		}
+		switch label {
+		case "immediate cause":
+			t.Yellow.ImmediateCause = appendLine(t.Yellow.ImmediateCause, value)
+		case "underlying cause":
+			t.Yellow.UnderlyingCause = appendLine(t.Yellow.UnderlyingCause, value)
+		case "system cause":
+			t.Yellow.SystemCause = appendLine(t.Yellow.SystemCause, value)
+		}
+	}
+
+	for i, line := range t.lines {
+		trimmed := strings.TrimSpace(line)
+
+		// ## section headings
+		if strings.HasPrefix(trimmed, "## ") {
+			flush(i)
+			endList(i)
+			if section == sectionYellow && t.refs.yellowEnd < 0 {
+				t.refs.yellowEnd = i
+			}
+			sub, label, opt = "", "", nil
+			switch {
+			case strings.Contains(trimmed, "RED LIGHT"):
+				section = sectionRed
+				t.Red.Present = true
+			case strings.Contains(trimmed, "YELLOW LIGHT"):
+				section = sectionYellow
+				t.Yellow.Present = true
+			case strings.Contains(trimmed, "GREEN LIGHT"):
+				section = sectionGreen
+				t.Green.Present = true
+			case strings.Contains(trimmed, "Completion"):
+				section = sectionCompletion
+				t.Completion.Present = true
+			default:
+				section = sectionNone
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "# ") && t.Title == "" {
+			t.Title = strings.TrimSpace(strings.TrimPrefix(trimmed, "# "))
+			continue
+		}
+
+		// ### subsection headings (some carry an inline value)
+		if strings.HasPrefix(trimmed, "### ") {
+			flush(i)
+			heading := strings.TrimSpace(strings.TrimPrefix(trimmed, "### "))
+			sub = strings.ToLower(heading)
+			label, opt = "", nil
+			endList(i)
+			t.parseHeadingValue(section, heading, i)
+			if section == sectionRed && strings.HasPrefix(sub, "what's the problem") {
+				t.refs.problem.start = i + 1
+				t.refs.problem.end = i + 1
+			}
+			if section == sectionYellow {
+				if cur = t.refs.yellowList(sub); cur != nil {
+					cur.heading = i
+				}
+			}
+			continue
+		}
+
+		if trimmed == "---" {
+			flush(i)
+			endList(i)
+			if section == sectionYellow && t.refs.yellowEnd < 0 {
+				t.refs.yellowEnd = i
+			}
+			sub, label, opt = "", "", nil
+			continue
+		}
+
+		switch section {
+		case sectionRed:
+			switch {
+			case strings.HasPrefix(sub, "what's the problem"):
+				body = append(body, line)
+			case strings.HasPrefix(sub, "impact"):
+				if cb, ok := t.parseCheckbox(line, i); ok {
+					t.Red.Impact = append(t.Red.Impact, cb)
+				}
+			case strings.HasPrefix(sub, "severity"):
+				if cb, ok := t.parseCheckbox(line, i); ok {
+					t.Red.Severity = append(t.Red.Severity, cb)
+				}
+			}
+
+		case sectionYellow:
+			if m := optionRe.FindStringSubmatch(trimmed); m != nil {
+				t.Yellow.Options = append(t.Yellow.Options, Option{Label: strings.TrimSpace(m[1])})
+				opt = &t.Yellow.Options[len(t.Yellow.Options)-1]
+				t.refs.options.items = append(t.refs.options.items, span{i, i + 1})
+				continue
+			}
+
+			if value, ok := boldValue(trimmed, "Chosen option"); ok {
+				t.Yellow.ChosenOption = value
+				t.refs.chosen = inline{line: i, prefix: "**Chosen option:**", orig: value}
+				continue
+			}
+			if value, ok := boldValue(trimmed, "Reason"); ok {
+				t.Yellow.Reason = value
+				t.refs.reason = inline{line: i, prefix: "**Reason:**", orig: value}
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(sub, "root cause"):
+				if name, value, ok := boldLabel(trimmed); ok {
+					label = strings.ToLower(name)
+					if _, known := causeLabels[label]; known {
+						t.refs.causes[label] = &span{i, i + 1}
+					}
+					setCause(value)
+				} else {
+					setCause(trimmed)
+					if c := t.refs.causes[label]; c != nil && trimmed != "" {
+						c.end = i + 1
+					}
+				}
+			case strings.HasPrefix(sub, "solution options") && opt != nil:
+				parseOptionField(opt, trimmed)
+				if trimmed != "" {
+					t.refs.options.items[len(t.refs.options.items)-1].end = i + 1
+				}
+			case strings.HasPrefix(sub, "implementation steps"):
+				if m := stepRe.FindStringSubmatch(line); m != nil {
+					t.refs.steps.items = append(t.refs.steps.items, span{i, i + 1})
+					if step := strings.TrimSpace(m[1]); step != "" {
+						t.Yellow.Steps = append(t.Yellow.Steps, step)
+					}
+				}
+			case strings.HasPrefix(sub, "success criteria"):
+				if checkboxRe.MatchString(line) {
+					t.refs.criteria.items = append(t.refs.criteria.items, span{i, i + 1})
+				}
+				if cb, ok := t.parseCheckbox(line, i); ok && cb.Label != "" {
+					c := ParseCriterion(cb.Label)
+					c.Checked, c.line = cb.Checked, i
+					if !c.CheckedAt.IsZero() {
+						t.refs.checkedAt[i] = c.CheckedAt
+					}
+					t.Yellow.Criteria = append(t.Yellow.Criteria, c)
+				}
+			}
+
+		case sectionGreen, sectionCompletion:
+			body = append(body, line)
+		}
+	}
+	flush(len(t.lines))
+	endList(len(t.lines))
+	if t.refs.yellowEnd < 0 {
+		t.refs.yellowEnd = len(t.lines)
+	}
+	t.refs.orig = copyYellow(t.Yellow)
+
+	return t
+}
+
+// causeLabels are the root cause labels, lowercased, with how they're written
+var causeLabels = map[string]string{
+	"immediate cause":  "Immediate cause",
+	"underlying cause": "Underlying cause",
+	"system cause":     "System cause",
+}
+
+// yellowList returns the list subsection a YELLOW ### heading starts
+func (r *refs) yellowList(sub string) *list {
+	switch {
+	case strings.HasPrefix(sub, "root cause"):
+		return &r.rootCause
+	case strings.HasPrefix(sub, "solution options"):
+		return &r.options
+	case strings.HasPrefix(sub, "implementation steps"):
+		return &r.steps
+	case strings.HasPrefix(sub, "success criteria"):
+		return &r.criteria
+	}
+	return nil
+}
+
+func copyYellow(y YellowSection) YellowSection {
+	y.Options = append([]Option(nil), y.Options...)
+	y.Steps = append([]string(nil), y.Steps...)
+	y.Criteria = append([]Criterion(nil), y.Criteria...)
+	return y
+}
+
+// ParseCriterion splits a criterion's text from its "`cmd: ...`" command
+// and "<!-- checked:... -->" time
+func ParseCriterion(text string) Criterion {
+	c := Criterion{Text: strings.TrimSpace(text)}
+	if m := checkedAtRe.FindStringSubmatch(c.Text); m != nil {
+		c.Text = strings.TrimSpace(c.Text[:len(c.Text)-len(m[0])])
+		c.CheckedAt, _ = time.Parse(time.RFC3339, m[1])
+	}
+	if m := commandRe.FindStringSubmatch(c.Text); m != nil && len(m[0]) < len(c.Text) {
+		c.Text = strings.TrimSpace(c.Text[:len(c.Text)-len(m[0])])
+		c.Command = strings.TrimSpace(m[1])
+	}
+	return c
+}
+
+// parseHeadingValue handles "### Timer Started: <value>" style headings
+func (t *Task) parseHeadingValue(section int, heading string, line int) {
+	name, value, found := strings.Cut(heading, ":")
+	if !found {
+		return
+	}
+	value = strings.TrimSpace(value)
+	prefix := "### " + name + ":"
+
+	switch {
+	case section == sectionGreen && strings.EqualFold(name, "Timer Started"):
+		t.Green.TimerStarted = value
+		t.refs.timerStarted = inline{line: line, prefix: prefix, orig: value}
+	case section == sectionGreen && strings.EqualFold(name, "Estimated Time"):
+		t.Green.EstimatedTime = value
+		t.refs.estimated = inline{line: line, prefix: prefix, orig: value}
+	case section == sectionCompletion && strings.EqualFold(name, "Actual Time"):
+		t.Completion.ActualTime = value
+		t.refs.actualTime = inline{line: line, prefix: prefix, orig: value}
+	case section == sectionCompletion && strings.EqualFold(name, "Accuracy"):
+		t.Completion.Accuracy = value
+		t.refs.accuracy = inline{line: line, prefix: prefix, orig: value}
+	case section == sectionCompletion && strings.EqualFold(name, "Lessons Learned"):
+		t.Completion.LessonsLearned = value
+	}
+}
+
+func (t *Task) parseCheckbox(line string, i int) (Checkbox, bool) {
+	m := checkboxRe.FindStringSubmatch(line)
+	if m == nil {
+		return Checkbox{}, false
+	}
+	checked := m[2] == "x" || m[2] == "X"
+	t.refs.checks[i] = checked
+	return Checkbox{Label: strings.TrimSpace(m[4]), Checked: checked, line: i}, true
+}
+
+func parseOptionField(opt *Option, line string) {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "-"))
+	name, value, found := strings.Cut(line, ":")
+	if !found {
+		return
+	}
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "description":
+		opt.Description = value
+	case "time estimate", "estimate":
+		opt.Estimate = value
+		opt.EstimateHours, _ = ParseEstimate(value)
+	case "pros":
+		opt.Pros = value
+	case "cons":
+		opt.Cons = value
+	}
+}
+
+// ParseEstimate parses a human estimate like "2h", "1.5 hours", "45m" or
+// "1h 30m" into hours, adding up every unit it finds
+func ParseEstimate(s string) (float64, error) {
+	matches := estimateRe.FindAllStringSubmatch(s, -1)
+	if matches == nil {
+		if hours, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil && hours > 0 {
+			return hours, nil
+		}
+		return 0, fmt.Errorf("no time estimate in %q", s)
+	}
+
+	var total float64
+	for _, m := range matches {
+		value, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, err
+		}
+		switch strings.ToLower(m[2]) {
+		case "h", "hr", "hrs", "hour", "hours":
+			total += value
+		case "m", "min", "mins", "minute", "minutes":
+			total += value / 60
+		default:
+			return 0, fmt.Errorf("unknown unit %q in time estimate %q (use h or m)", m[2], s)
+		}
+	}
+	return total, nil
+}
+
+// String serializes the task, rewriting only the values that changed so
+// the rest of the user's markdown is preserved as written
+func (t *Task) String() string {
+	lines := append([]string(nil), t.lines...)
+
+	setInline := func(ref inline, value string) {
+		if ref.prefix == "" || value == ref.orig {
+			return
+		}
+		indent := lines[ref.line][:len(lines[ref.line])-len(strings.TrimLeft(lines[ref.line], " \t"))]
+		lines[ref.line] = strings.TrimRight(indent+ref.prefix+" "+value, " ")
+	}
+
+	setCheck := func(i int, checked bool) {
+		if orig, ok := t.refs.checks[i]; !ok || orig == checked {
+			return
+		}
+		mark := " "
+		if checked {
+			mark = "x"
+		}
+		lines[i] = checkboxRe.ReplaceAllString(lines[i], "${1}"+mark+"${3}${4}")
+	}
+
+	for _, cb := range t.Red.Impact {
+		setCheck(cb.line, cb.Checked)
+	}
+	for _, cb := range t.Red.Severity {
+		setCheck(cb.line, cb.Checked)
+	}
+	if !t.criteriaChanged() {
+		for _, c := range t.Yellow.Criteria {
+			if orig, ok := t.refs.checks[c.line]; ok && (orig != c.Checked || !t.refs.checkedAt[c.line].Equal(c.CheckedAt)) {
+				lines[c.line] = criterionLine(lines[c.line], c)
+			}
+		}
+	}
+
+	setInline(t.refs.chosen, t.Yellow.ChosenOption)
+	setInline(t.refs.reason, t.Yellow.Reason)
+	setInline(t.refs.timerStarted, t.Green.TimerStarted)
+	setInline(t.refs.estimated, t.Green.EstimatedTime)
+	setInline(t.refs.actualTime, t.Completion.ActualTime)
+	setInline(t.refs.accuracy, t.Completion.Accuracy)
+
+	// Multi-line values go last since they can change the line count
+	var edits []edit
+	if p := t.refs.problem; p.start > 0 && t.Red.Problem != p.orig {
+		end := t.trimBlank(p.end, p.start)
+		edits = append(edits, edit{p.start, end, strings.Split(strings.TrimSpace(t.Red.Problem), "\n")})
+	}
+	edits = append(edits, t.yellowEdits()...)
+
+	// Apply them bottom up so each edit's lines are still where they were
+	// parsed; edits at the same line keep their order
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		lines = append(lines[:e.start], append(e.lines, lines[e.end:]...)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// yellowEdits rewrites the root causes, options, steps and criteria that
+// changed since parsing. Subsections the file lacks are added at the end
+// of YELLOW LIGHT.
+func (t *Task) yellowEdits() []edit {
+	var edits []edit
+	y, orig := t.Yellow, t.refs.orig
+
+	causes := []struct{ label, value, orig string }{
+		{"immediate cause", y.ImmediateCause, orig.ImmediateCause},
+		{"underlying cause", y.UnderlyingCause, orig.UnderlyingCause},
+		{"system cause", y.SystemCause, orig.SystemCause},
+	}
+	var missing []string // causes without a label line
+	for _, c := range causes {
+		if c.value == c.orig {
+			continue
+		}
+		value := causeLines(causeLabels[c.label], c.value)
+		if sp := t.refs.causes[c.label]; sp != nil {
+			edits = append(edits, edit{sp.start, sp.end, value})
+		} else {
+			missing = append(missing, append([]string{""}, value...)...)
+		}
+	}
+	if len(missing) > 0 {
+		edits = append(edits, t.insertList(t.refs.rootCause, "Root Cause Analysis", missing))
+	}
+
+	if !optionsEqual(y.Options, orig.Options) {
+		edits = append(edits, t.replaceList(t.refs.options, "Solution Options", optionLines(y.Options), true))
+	}
+
+	if !stringsEqual(y.Steps, orig.Steps) {
+		steps := make([]string, len(y.Steps))
+		for i, step := range y.Steps {
+			steps[i] = fmt.Sprintf("%d. %s", i+1, step)
+		}
+		edits = append(edits, t.replaceList(t.refs.steps, "Implementation Steps", steps, false))
+	}
+
+	if t.criteriaChanged() {
+		criteria := make([]string, len(y.Criteria))
+		for i, c := range y.Criteria {
+			criteria[i] = criterionLine("- [ ] ", c)
+		}
+		edits = append(edits, t.replaceList(t.refs.criteria, "Success Criteria", criteria, false))
+	}
+	return edits
+}
+
+// replaceList replaces a list subsection's items with items. sep puts a
+// blank line between the heading and items added where there were none.
+func (t *Task) replaceList(l list, heading string, items []string, sep bool) edit {
+	if l.heading >= 0 && len(l.items) > 0 {
+		return edit{l.items[0].start, l.items[len(l.items)-1].end, items}
+	}
+	if sep && l.heading >= 0 {
+		items = append([]string{""}, items...)
+	}
+	return t.insertList(l, heading, items)
+}
+
+// insertList adds lines at the end of a subsection, adding the
+// subsection when the file doesn't have it
+func (t *Task) insertList(l list, heading string, lines []string) edit {
+	if l.heading < 0 {
+		pos := t.trimBlank(t.refs.yellowEnd, 0)
+		return edit{pos, pos, append([]string{"", "### " + heading}, lines...)}
+	}
+	pos := t.trimBlank(l.end, l.heading+1)
+	return edit{pos, pos, lines}
+}
+
+// trimBlank moves end back over blank lines, no further than min
+func (t *Task) trimBlank(end, min int) int {
+	for end > min && strings.TrimSpace(t.lines[end-1]) == "" {
+		end--
+	}
+	return end
+}
+
+// criteriaChanged reports whether criteria were added, removed or edited
+// since parsing, as opposed to only ticked or unticked
+func (t *Task) criteriaChanged() bool {
+	a, b := t.Yellow.Criteria, t.refs.orig.Criteria
+	if len(a) != len(b) {
+		return true
+	}
+	for i := range a {
+		if a[i].Text != b[i].Text || a[i].Command != b[i].Command {
+			return true
+		}
+	}
+	return false
+}
+
+// causeLines writes a root cause as its label followed by its value
+func causeLines(label, value string) []string {
+	lines := []string{"**" + label + ":**"}
+	if value = strings.TrimSpace(value); value != "" {
+		lines = append(lines, strings.Split(value, "\n")...)
+	}
+	return lines
+}
+
+// optionLines writes options as "#### Option X:" blocks. Options without a
+// label are lettered by position.
+func optionLines(options []Option) []string {
+	var lines []string
+	for i, o := range options {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		label := o.Label
+		if label == "" {
+			label = string(rune('A' + i))
+		}
+		lines = append(lines,
+			"#### Option "+label+":",
+			strings.TrimRight("- Description: "+o.Description, " "),
+			strings.TrimRight("- Time estimate: "+o.Estimate, " "),
+			strings.TrimRight("- Pros: "+o.Pros, " "),
+			strings.TrimRight("- Cons: "+o.Cons, " "),
+		)
+	}
+	return lines
+}
+
+func optionsEqual(a, b []Option) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Label != b[i].Label || a[i].Description != b[i].Description ||
+			a[i].Estimate != b[i].Estimate || a[i].Pros != b[i].Pros || a[i].Cons != b[i].Cons {
+			return false
+		}
+	}
+	return true
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckImpact ticks the impact checkbox whose label starts with label
+func (t *Task) CheckImpact(label string) bool {
+	for i := range t.Red.Impact {
+		if strings.HasPrefix(t.Red.Impact[i].Label, label) {
+			t.Red.Impact[i].Checked = true
+			return true
+		}
+	}
+	return false
+}
+
+// SetSeverity ticks the severity checkbox whose label starts with label and
+// clears the others
+func (t *Task) SetSeverity(label string) bool {
+	found := false
+	for i := range t.Red.Severity {
+		match := !found && strings.HasPrefix(t.Red.Severity[i].Label, label)
+		t.Red.Severity[i].Checked = match
+		found = found || match
+	}
+	return found
+}
+
+// CheckCriterion ticks or unticks criterion i, recording at as the time
+// it was ticked
+func (t *Task) CheckCriterion(i int, checked bool, at time.Time) {
+	c := &t.Yellow.Criteria[i]
+	c.Checked = checked
+	c.CheckedAt = time.Time{}
+	if checked {
+		c.CheckedAt = at.Truncate(time.Second)
+	}
+}
+
+// CriteriaMet returns how many success criteria are ticked
+func (t *Task) CriteriaMet() int {
+	met := 0
+	for _, c := range t.Yellow.Criteria {
+		if c.Checked {
+			met++
+		}
+	}
+	return met
+}
+
+// criterionLine rewrites a criterion's checkbox line for c
+func criterionLine(line string, c Criterion) string {
+	m := checkboxRe.FindStringSubmatch(line)
+	if m == nil {
+		return line
+	}
+	mark := " "
+	if c.Checked {
+		mark = "x"
+	}
+	out := m[1] + mark + m[3] + c.Text
+	if c.Command != "" {
+		out += " `cmd: " + c.Command + "`"
+	}
+	if c.Checked && !c.CheckedAt.IsZero() {
+		out += " <!-- checked:" + c.CheckedAt.Format(time.RFC3339) + " -->"
+	}
+	return out
+}
+
+// CriteriaTexts returns the success criteria descriptions
+func (t *Task) CriteriaTexts() []string {
+	criteria := []string{}
+	for _, c := range t.Yellow.Criteria {
+		criteria = append(criteria, c.Text)
+	}
+	return criteria
+}
+
+// Chosen returns the option named by the "**Chosen option:**" decision,
+// or nil when no decision was made or it doesn't match an option heading
+func (t *Task) Chosen() *Option {
+	label := normalizeOptionLabel(t.Yellow.ChosenOption)
+	if label == "" {
+		return nil
+	}
+	for i := range t.Yellow.Options {
+		if strings.EqualFold(t.Yellow.Options[i].Label, label) {
+			return &t.Yellow.Options[i]
+		}
+	}
+	return nil
+}
+
+// TimeEstimate returns the planned time in hours from the chosen option
+func (t *Task) TimeEstimate() (float64, error) {
+	opt := t.Chosen()
+	if opt == nil {
+		return 0, fmt.Errorf("no time estimate found: chosen option %q doesn't match any option", t.Yellow.ChosenOption)
+	}
+	if opt.EstimateHours <= 0 {
+		if _, err := ParseEstimate(opt.Estimate); err != nil && opt.Estimate != "" {
+			return 0, fmt.Errorf("no time estimate found for option %s: %w", opt.Label, err)
+		}
+		return 0, fmt.Errorf("no time estimate found for option %s", opt.Label)
+	}
+	return opt.EstimateHours, nil
+}
+
+// normalizeOptionLabel turns "Option B", "b", "B - quick fix" or "(B)" into "B"
+func normalizeOptionLabel(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > 7 && strings.EqualFold(s[:7], "option ") {
+		s = strings.TrimSpace(s[7:])
+	}
+	s = strings.TrimLeft(s, "([")
+	if fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ':' || r == '-' || r == ')' || r == ']' || r == ',' || r == '.'
+	}); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+func newRefs() refs {
+	return refs{
+		causes:    make(map[string]*span),
+		rootCause: list{heading: -1},
+		options:   list{heading: -1},
+		steps:     list{heading: -1},
+		criteria:  list{heading: -1},
+		yellowEnd: -1,
+		checks:    make(map[int]bool),
+		checkedAt: make(map[int]time.Time),
+	}
+}
+
+// boldValue matches "**Name:** value"
+func boldValue(line, name string) (string, bool) {
+	prefix := "**" + name + ":**"
+	if !strings.HasPrefix(line, prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+}
+
+// boldLabel matches any "**Name:** value" line
+func boldLabel(line string) (name, value string, ok bool) {
+	if !strings.HasPrefix(line, "**") {
+		return "", "", false
+	}
+	rest := line[2:]
+	end := strings.Index(rest, ":**")
+	if end == -1 {
+		return "", "", false
+	}
+	return rest[:end], strings.TrimSpace(rest[end+3:]), true
+}
+
+// joinBody joins body lines, dropping template comments and blank edges
+func joinBody(lines []string) string {
+	var kept []string
+	for _, l := range lines {
+		if isComment(strings.TrimSpace(l)) {
+			continue
+		}
+		kept = append(kept, strings.TrimRight(l, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+func isComment(s string) bool {
+	return strings.HasPrefix(s, "<!--") && strings.HasSuffix(s, "-->")
+}
+
+func appendLine(existing, value string) string {
+	if existing == "" {
+		return value
+	}
+	return existing + "\n" + value
+}
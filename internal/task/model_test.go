@@ -0,0 +1,392 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/templates"
+)
+
+const filledTask = `# Current Task
+
+## 🔴 RED LIGHT - Problem Definition
+
+### What's the Problem?
+Login fails for SSO users
+after the cookie change
+
+### Impact
+- [x] Blocks launch
+- [ ] Blocks paying users
+
+### Severity
+- [ ] P0 - Launch blocker
+- [x] P1 - Paying user blocker
+
+My own scratch note that yo doesn't know about.
+
+---
+
+## 🟡 YELLOW LIGHT - Analysis & Planning
+
+### Root Cause Analysis
+**Immediate cause:** cookie is SameSite=Strict
+**Underlying cause:**
+nobody tested the IdP redirect
+
+### Solution Options
+
+#### Option A:
+- Description: Revert cookie change
+- Time estimate: 30m
+- Pros: fast
+- Cons: reopens CSRF hole
+
+#### Option B:
+- Description: Lax cookie for SSO route
+- Time estimate: 2h
+
+### Decision
+**Chosen option:** B
+**Reason:** keeps CSRF protection
+
+### Implementation Steps
+1. Split cookie config
+2. Add e2e test
+3.
+
+### Success Criteria
+- [ ] SSO login works
+- [x] CSRF test still passes
+- [ ]
+
+---
+
+## 🟢 GREEN LIGHT - Execution
+
+### Timer Started:
+### Estimated Time:
+
+### Notes:
+<!-- Add notes as you work -->
+Started with the config split.
+`
+
+func TestParseTask(t *testing.T) {
+	task := Parse(filledTask)
+
+	if task.Title != "Current Task" {
+		t.Errorf("Expected title 'Current Task', got %q", task.Title)
+	}
+
+	if !task.Red.Present || !task.Yellow.Present || !task.Green.Present {
+		t.Errorf("Expected RED, YELLOW and GREEN sections to be present")
+	}
+
+	if task.Red.Problem != "Login fails for SSO users\nafter the cookie change" {
+		t.Errorf("Unexpected problem: %q", task.Red.Problem)
+	}
+
+	if len(task.Red.Impact) != 2 || !task.Red.Impact[0].Checked || task.Red.Impact[1].Checked {
+		t.Errorf("Unexpected impact: %+v", task.Red.Impact)
+	}
+
+	if len(task.Red.Severity) != 2 || !task.Red.Severity[1].Checked {
+		t.Errorf("Unexpected severity: %+v", task.Red.Severity)
+	}
+
+	if task.Yellow.ImmediateCause != "cookie is SameSite=Strict" {
+		t.Errorf("Unexpected immediate cause: %q", task.Yellow.ImmediateCause)
+	}
+	if task.Yellow.UnderlyingCause != "nobody tested the IdP redirect" {
+		t.Errorf("Unexpected underlying cause: %q", task.Yellow.UnderlyingCause)
+	}
+
+	if len(task.Yellow.Options) != 2 {
+		t.Fatalf("Expected 2 options, got %d", len(task.Yellow.Options))
+	}
+	a := task.Yellow.Options[0]
+	if a.Label != "A" || a.Description != "Revert cookie change" || a.EstimateHours != 0.5 || a.Cons != "reopens CSRF hole" {
+		t.Errorf("Unexpected option A: %+v", a)
+	}
+	if task.Yellow.Options[1].EstimateHours != 2 {
+		t.Errorf("Expected option B estimate 2h, got %v", task.Yellow.Options[1].EstimateHours)
+	}
+
+	if task.Yellow.ChosenOption != "B" || task.Yellow.Reason != "keeps CSRF protection" {
+		t.Errorf("Unexpected decision: %q / %q", task.Yellow.ChosenOption, task.Yellow.Reason)
+	}
+
+	if len(task.Yellow.Steps) != 2 {
+		t.Errorf("Expected 2 non-empty steps, got %v", task.Yellow.Steps)
+	}
+
+	if len(task.Yellow.Criteria) != 2 {
+		t.Fatalf("Expected 2 non-empty criteria, got %d", len(task.Yellow.Criteria))
+	}
+	if task.Yellow.Criteria[0].Checked || !task.Yellow.Criteria[1].Checked {
+		t.Errorf("Unexpected criteria state: %+v", task.Yellow.Criteria)
+	}
+
+	if task.Green.Notes != "Started with the config split." {
+		t.Errorf("Unexpected notes: %q", task.Green.Notes)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	task := Parse(templates.Task)
+
+	if task.Red.Problem != "" {
+		t.Errorf("Expected empty problem for template, got %q", task.Red.Problem)
+	}
+	if len(task.Red.Impact) != 5 || len(task.Red.Severity) != 4 {
+		t.Errorf("Expected 5 impacts and 4 severities, got %d and %d", len(task.Red.Impact), len(task.Red.Severity))
+	}
+	if len(task.Yellow.Options) != 3 {
+		t.Errorf("Expected 3 template options, got %d", len(task.Yellow.Options))
+	}
+	if task.Yellow.ChosenOption != "" {
+		t.Errorf("Expected no chosen option, got %q", task.Yellow.ChosenOption)
+	}
+	if len(task.Yellow.Criteria) != 0 {
+		t.Errorf("Expected empty template criteria to be skipped, got %d", len(task.Yellow.Criteria))
+	}
+	if !task.Completion.Present {
+		t.Error("Expected Completion section to be present")
+	}
+
+	if task.String() != templates.Task {
+		t.Error("Expected unmodified template to serialize unchanged")
+	}
+}
+
+func TestSerializePreservesContent(t *testing.T) {
+	task := Parse(filledTask)
+
+	task.Red.Problem = "SSO login broken"
+	task.Yellow.Criteria[0].Checked = true
+	task.Green.TimerStarted = "2024-01-15 10:00"
+	task.Green.EstimatedTime = "2.0h"
+	task.SetSeverity("P0")
+
+	out := task.String()
+
+	for _, want := range []string{
+		"### What's the Problem?\nSSO login broken\n\n### Impact",
+		"- [x] SSO login works",
+		"- [x] CSRF test still passes",
+		"### Timer Started: 2024-01-15 10:00",
+		"### Estimated Time: 2.0h",
+		"- [x] P0 - Launch blocker",
+		"- [ ] P1 - Paying user blocker",
+		"My own scratch note that yo doesn't know about.",
+		"<!-- Add notes as you work -->",
+		"- Pros: fast",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected serialized task to contain %q", want)
+		}
+	}
+
+	if strings.Contains(out, "after the cookie change") {
+		t.Error("Expected old problem text to be replaced")
+	}
+
+	// Round trip
+	again := Parse(out)
+	if again.Red.Problem != "SSO login broken" || again.Green.EstimatedTime != "2.0h" {
+		t.Errorf("Round trip lost values: %q, %q", again.Red.Problem, again.Green.EstimatedTime)
+	}
+	if again.String() != out {
+		t.Error("Expected re-serializing an unchanged task to be stable")
+	}
+}
+
+func TestSetProblemOnTemplate(t *testing.T) {
+	task := Parse(templates.Task)
+	task.Red.Problem = "Deploy button is broken"
+	task.CheckImpact("Other")
+
+	again := Parse(task.String())
+	if again.Red.Problem != "Deploy button is broken" {
+		t.Errorf("Expected problem to be set, got %q", again.Red.Problem)
+	}
+	if !again.Red.Impact[4].Checked {
+		t.Error("Expected 'Other' impact to be checked")
+	}
+	if again.ValidateRed().Valid {
+		t.Error("Expected RED to still need a severity")
+	}
+}
+
+func TestSetYellowOnTemplate(t *testing.T) {
+	task := Parse(templates.Task)
+	task.Yellow.ImmediateCause = "cookie is SameSite=Strict"
+	task.Yellow.UnderlyingCause = "nobody tested the IdP redirect"
+	task.Yellow.SystemCause = "no SSO e2e tests"
+	task.Yellow.Options = []Option{
+		{Label: "A", Description: "Revert", Estimate: "30m", Pros: "fast", Cons: "CSRF hole"},
+		{Label: "B", Description: "Lax cookie for SSO", Estimate: "2h"},
+		{Label: "C", Description: "Rewrite SSO", Estimate: "2d"},
+	}
+	task.Yellow.ChosenOption = "B"
+	task.Yellow.Reason = "keeps CSRF protection"
+	task.Yellow.Steps = []string{"Split cookie config", "Add e2e test"}
+	task.Yellow.Criteria = []Criterion{{Text: "SSO login works", Command: "go test ./auth/..."}}
+
+	out := task.String()
+	for _, want := range []string{
+		"**Immediate cause:**\ncookie is SameSite=Strict\n\n**Underlying cause:**",
+		"#### Option A:\n- Description: Revert\n- Time estimate: 30m\n- Pros: fast\n- Cons: CSRF hole\n\n#### Option B:",
+		"### Implementation Steps\n1. Split cookie config\n2. Add e2e test\n\n### Success Criteria",
+		"### Success Criteria\n- [ ] SSO login works `cmd: go test ./auth/...`\n\n---",
+		"<!-- Use 'yo defer -i' to log these properly, or list them here: -->",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected serialized task to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<!-- What directly causes this? -->") {
+		t.Error("Expected the cause placeholder to be replaced")
+	}
+
+	again := Parse(out)
+	if again.Yellow.SystemCause != "no SSO e2e tests" || len(again.Yellow.Options) != 3 || len(again.Yellow.Steps) != 2 {
+		t.Errorf("Round trip lost YELLOW values: %+v", again.Yellow)
+	}
+	if hours, err := again.TimeEstimate(); err != nil || hours != 2 {
+		t.Errorf("Expected the chosen option's 2h estimate, got %v (%v)", hours, err)
+	}
+	if c := again.Yellow.Criteria; len(c) != 1 || c[0].Command != "go test ./auth/..." {
+		t.Errorf("Round trip lost the criteria: %+v", c)
+	}
+	if !again.ValidateYellow().Valid {
+		t.Errorf("Expected YELLOW to validate: %+v", again.ValidateYellow())
+	}
+	if again.String() != out {
+		t.Error("Expected an unchanged task to serialize unchanged")
+	}
+}
+
+func TestSetYellowKeepsContent(t *testing.T) {
+	task := Parse(filledTask)
+	task.Yellow.Steps = append(task.Yellow.Steps, "Ship it")
+	task.Yellow.Criteria = append(task.Yellow.Criteria, Criterion{Text: "Docs updated"})
+	task.Yellow.SystemCause = "no SSO e2e tests"
+
+	out := task.String()
+	for _, want := range []string{
+		"1. Split cookie config\n2. Add e2e test\n3. Ship it\n",
+		"- [ ] SSO login works\n- [x] CSRF test still passes\n- [ ] Docs updated\n",
+		"nobody tested the IdP redirect\n\n**System cause:**\nno SSO e2e tests\n\n### Solution Options",
+		"My own scratch note that yo doesn't know about.",
+		"- Cons: reopens CSRF hole\n\n#### Option B:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected serialized task to contain %q:\n%s", want, out)
+		}
+	}
+
+	// Subsections the file lacks are added to YELLOW LIGHT
+	bare := Parse("# Task\n\n## 🟡 YELLOW LIGHT\n\n---\n\n## 🟢 GREEN LIGHT\n")
+	bare.Yellow.Steps = []string{"Do it"}
+	if out := bare.String(); !strings.Contains(out, "## 🟡 YELLOW LIGHT\n\n### Implementation Steps\n1. Do it\n\n---") {
+		t.Errorf("Expected steps added to YELLOW LIGHT:\n%s", out)
+	}
+}
+
+func TestCheckCriterion(t *testing.T) {
+	at := time.Date(2025, 1, 6, 14, 5, 30, 0, time.UTC)
+	task := Parse(filledTask)
+	task.CheckCriterion(0, true, at)
+	task.CheckCriterion(1, false, at)
+
+	out := task.String()
+	for _, want := range []string{
+		"- [x] SSO login works <!-- checked:2025-01-06T14:05:30Z -->",
+		"- [ ] CSRF test still passes\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected serialized task to contain %q", want)
+		}
+	}
+
+	again := Parse(out)
+	c := again.Yellow.Criteria[0]
+	if c.Text != "SSO login works" || !c.Checked || !c.CheckedAt.Equal(at) {
+		t.Errorf("Round trip lost the check: %+v", c)
+	}
+	if again.CriteriaMet() != 1 {
+		t.Errorf("Expected 1 criterion met, got %d", again.CriteriaMet())
+	}
+	if again.String() != out {
+		t.Error("Expected an unchanged task to serialize unchanged")
+	}
+
+	// Unticking drops the time
+	again.CheckCriterion(0, false, at)
+	if out := again.String(); strings.Contains(out, "checked:") {
+		t.Errorf("Expected the time to be removed:\n%s", out)
+	}
+}
+
+func TestCriterionCommand(t *testing.T) {
+	at := time.Date(2025, 1, 6, 14, 5, 30, 0, time.UTC)
+	task := Parse(strings.Replace(filledTask, "- [ ] SSO login works", "- [ ] SSO login works `cmd: go test ./auth/...`", 1))
+
+	c := task.Yellow.Criteria[0]
+	if c.Text != "SSO login works" || c.Command != "go test ./auth/..." {
+		t.Errorf("Expected text and command to be split, got %+v", c)
+	}
+	if task.Yellow.Criteria[1].Command != "" {
+		t.Errorf("Expected no command, got %q", task.Yellow.Criteria[1].Command)
+	}
+
+	task.CheckCriterion(0, true, at)
+	want := "- [x] SSO login works `cmd: go test ./auth/...` <!-- checked:2025-01-06T14:05:30Z -->"
+	if out := task.String(); !strings.Contains(out, want) {
+		t.Errorf("Expected serialized task to contain %q", want)
+	}
+
+	// A bare command is left as the criterion's text
+	bare := Parse(strings.Replace(filledTask, "- [ ] SSO login works", "- [ ] `cmd: make check`", 1))
+	if c := bare.Yellow.Criteria[0]; c.Text != "`cmd: make check`" || c.Command != "" {
+		t.Errorf("Unexpected bare command criterion: %+v", c)
+	}
+}
+
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		wantErr  bool
+	}{
+		{"2h", 2, false},
+		{"1.5 hours", 1.5, false},
+		{"45m", 0.75, false},
+		{"90 minutes", 1.5, false},
+		{"3", 3, false},
+		{"1h 30m", 1.5, false},
+		{"1h30m", 1.5, false},
+		{"2 hours 15 minutes", 2.25, false},
+		{"1.5h 30m", 2, false},
+		{"30m 30m", 1, false},
+		{"", 0, true},
+		{"soon", 0, true},
+		{"2d", 0, true},
+		{"1h 2d", 0, true},
+		{"3 weeks", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEstimate(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEstimate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseEstimate(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -20,155 +20,97 @@ func (e ValidationError) Error() string {
 
 // ValidationResult holds the result of validating a task file
 type ValidationResult struct {
-	Valid  bool
-	Errors []ValidationError
+	Valid    bool
+	Errors   []ValidationError
+	Warnings []ValidationError
 }
 
 // ValidateRed validates the RED LIGHT section
 func ValidateRed(filepath string) (*ValidationResult, error) {
-	content, err := os.ReadFile(filepath)
+	t, err := Load(filepath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read task file: %w", err)
+		return nil, err
 	}
+	return t.ValidateRed(), nil
+}
 
-	result := &ValidationResult{Valid: true}
-	text := string(content)
-
-	// Check for problem description
-	if !strings.Contains(text, "## 🔴 RED LIGHT") {
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "RED LIGHT section",
-			Message: "missing RED LIGHT section",
-		})
-		return result, nil
+// ValidateYellow validates the YELLOW LIGHT section
+func ValidateYellow(filepath string) (*ValidationResult, error) {
+	t, err := Load(filepath)
+	if err != nil {
+		return nil, err
 	}
+	return t.ValidateYellow(), nil
+}
 
-	// Extract RED LIGHT section
-	redSection := extractSection(text, "## 🔴 RED LIGHT", "## 🟡 YELLOW LIGHT")
+// ValidateRed validates the parsed RED LIGHT section
+func (t *Task) ValidateRed() *ValidationResult {
+	result := &ValidationResult{Valid: true}
+
+	if !t.Red.Present {
+		result.add("RED LIGHT section", "missing RED LIGHT section")
+		return result
+	}
 
 	// Check for problem statement (not just template)
-	if strings.Contains(redSection, "<!-- Describe the problem clearly -->") &&
-		!hasContentAfter(redSection, "### What's the Problem?", "<!-- Describe the problem clearly -->") {
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "Problem",
-			Message: "problem description is empty",
-		})
+	if t.Red.Problem == "" {
+		result.add("Problem", "problem description is empty")
 	}
 
 	// Check for at least one impact checkbox
-	impactChecked := countCheckedBoxes(redSection, "### Impact", "### Severity")
-	if impactChecked == 0 {
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "Impact",
-			Message: "at least one impact must be selected",
-		})
+	if countChecked(t.Red.Impact) == 0 {
+		result.add("Impact", "at least one impact must be selected")
 	}
 
 	// Check for severity selection
-	severityChecked := countCheckedBoxes(redSection, "### Severity", "---")
-	if severityChecked == 0 {
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "Severity",
-			Message: "severity must be selected (P0-P3)",
-		})
+	if countChecked(t.Red.Severity) == 0 {
+		result.add("Severity", "severity must be selected (P0-P3)")
 	}
 
-	return result, nil
+	return result
 }
 
-// ValidateYellow validates the YELLOW LIGHT section
-func ValidateYellow(filepath string) (*ValidationResult, error) {
-	content, err := os.ReadFile(filepath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read task file: %w", err)
-	}
-
+// ValidateYellow validates the parsed YELLOW LIGHT section
+func (t *Task) ValidateYellow() *ValidationResult {
 	result := &ValidationResult{Valid: true}
-	text := string(content)
-
-	// Check for YELLOW LIGHT section
-	if !strings.Contains(text, "## 🟡 YELLOW LIGHT") {
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "YELLOW LIGHT section",
-			Message: "missing YELLOW LIGHT section",
-		})
-		return result, nil
-	}
 
-	// Extract YELLOW LIGHT section
-	yellowSection := extractSection(text, "## 🟡 YELLOW LIGHT", "## 🟢 GREEN LIGHT")
+	if !t.Yellow.Present {
+		result.add("YELLOW LIGHT section", "missing YELLOW LIGHT section")
+		return result
+	}
 
 	// Check for at least 3 solution options
-	optionCount := strings.Count(yellowSection, "#### Option")
-	if optionCount < 3 {
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "Solution Options",
-			Message: fmt.Sprintf("need at least 3 solution options, found %d", optionCount),
-		})
+	if len(t.Yellow.Options) < 3 {
+		result.add("Solution Options",
+			fmt.Sprintf("need at least 3 solution options, found %d", len(t.Yellow.Options)))
 	}
 
 	// Check for decision
-	if !strings.Contains(yellowSection, "**Chosen option:**") ||
-		strings.Contains(yellowSection, "**Chosen option:** \n") {
-		hasDecision := false
-		lines := strings.Split(yellowSection, "\n")
-		for _, line := range lines {
-			if strings.HasPrefix(line, "**Chosen option:**") && len(strings.TrimPrefix(line, "**Chosen option:**")) > 1 {
-				hasDecision = true
-				break
-			}
-		}
-		if !hasDecision {
-			result.Valid = false
-			result.Errors = append(result.Errors, ValidationError{
-				Field:   "Decision",
-				Message: "chosen option must be specified",
-			})
-		}
+	if t.Yellow.ChosenOption == "" {
+		result.add("Decision", "chosen option must be specified")
+	} else if opt := t.Chosen(); opt == nil {
+		result.warn("Decision",
+			fmt.Sprintf("chosen option %q doesn't match any #### Option heading", t.Yellow.ChosenOption))
+	} else if opt.EstimateHours <= 0 {
+		result.warn("Decision", fmt.Sprintf("option %s has no time estimate", opt.Label))
 	}
 
 	// Check for success criteria (at least 1)
-	successChecked := countAllBoxes(yellowSection, "### Success Criteria", "---")
-	if successChecked < 1 {
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "Success Criteria",
-			Message: fmt.Sprintf("need at least 1 success criterion, found %d", successChecked),
-		})
+	if len(t.Yellow.Criteria) < 1 {
+		result.add("Success Criteria",
+			fmt.Sprintf("need at least 1 success criterion, found %d", len(t.Yellow.Criteria)))
 	}
 
-	return result, nil
+	return result
 }
 
 // GetSuccessCriteria extracts success criteria from the task file
 func GetSuccessCriteria(filepath string) ([]string, error) {
-	content, err := os.ReadFile(filepath)
+	t, err := Load(filepath)
 	if err != nil {
 		return nil, err
 	}
-
-	text := string(content)
-	yellowSection := extractSection(text, "## 🟡 YELLOW LIGHT", "## 🟢 GREEN LIGHT")
-	criteriaSection := extractSection(yellowSection, "### Success Criteria", "---")
-
-	var criteria []string
-	lines := strings.Split(criteriaSection, "\n")
-	checkboxRe := regexp.MustCompile(`^\s*-\s*\[[ xX]\]\s*(.+)$`)
-
-	for _, line := range lines {
-		matches := checkboxRe.FindStringSubmatch(line)
-		if len(matches) > 1 {
-			criteria = append(criteria, strings.TrimSpace(matches[1]))
-		}
-	}
-
-	return criteria, nil
+	return t.CriteriaTexts(), nil
 }
 
 // GetTimeEstimate extracts the time estimate from YELLOW LIGHT
@@ -178,82 +120,43 @@ func GetTimeEstimate(filepath string) (float64, error) {
 		return 0, err
 	}
 
-	text := string(content)
-
-	// Look for "Time estimate:" patterns with hours
-	timeReH := regexp.MustCompile(`[Tt]ime\s*estimate[:\s]+(\d+(?:\.\d+)?)\s*h`)
-	matches := timeReH.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		var hours float64
-		fmt.Sscanf(matches[1], "%f", &hours)
-		return hours, nil
+	t := Parse(string(content))
+	if len(t.Yellow.Options) > 0 {
+		return t.TimeEstimate()
 	}
 
-	// Look for "Time estimate:" patterns with minutes
-	timeReM := regexp.MustCompile(`[Tt]ime\s*estimate[:\s]+(\d+(?:\.\d+)?)\s*m`)
-	matches = timeReM.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		var minutes float64
-		fmt.Sscanf(matches[1], "%f", &minutes)
-		return minutes / 60, nil
-	}
-
-	// Try standalone "Xh" format
-	hoursRe := regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(?:hours?|h)\b`)
-	matches = hoursRe.FindStringSubmatch(text)
-	if len(matches) > 1 {
-		var hours float64
-		fmt.Sscanf(matches[1], "%f", &hours)
-		return hours, nil
-	}
+	// Free-form plans without option headings
+	return scanTimeEstimate(string(content))
+}
 
-	// Try standalone "Xm" format
-	minsRe := regexp.MustCompile(`(\d+(?:\.\d+)?)\s*m\b`)
-	matches = minsRe.FindStringSubmatch(text)
+// scanTimeEstimate looks for a "Time estimate: 4h" line anywhere in the text
+func scanTimeEstimate(text string) (float64, error) {
+	timeRe := regexp.MustCompile(`[Tt]ime\s*estimate[:\s]+((?:\d+(?:\.\d+)?[ \t]*[A-Za-z]+[ \t]*)+)`)
+	matches := timeRe.FindStringSubmatch(text)
 	if len(matches) > 1 {
-		var minutes float64
-		fmt.Sscanf(matches[1], "%f", &minutes)
-		return minutes / 60, nil
+		return ParseEstimate(matches[1])
 	}
 
 	return 0, fmt.Errorf("no time estimate found")
 }
 
-// Helper functions
-
-func extractSection(text, start, end string) string {
-	startIdx := strings.Index(text, start)
-	if startIdx == -1 {
-		return ""
-	}
-	endIdx := strings.Index(text[startIdx+len(start):], end)
-	if endIdx == -1 {
-		return text[startIdx:]
-	}
-	return text[startIdx : startIdx+len(start)+endIdx]
-}
-
-func countCheckedBoxes(text, start, end string) int {
-	section := extractSection(text, start, end)
-	return strings.Count(section, "[x]") + strings.Count(section, "[X]")
+func (r *ValidationResult) add(field, message string) {
+	r.Valid = false
+	r.Errors = append(r.Errors, ValidationError{Field: field, Message: message})
 }
 
-func countAllBoxes(text, start, end string) int {
-	section := extractSection(text, start, end)
-	return strings.Count(section, "[ ]") + strings.Count(section, "[x]") + strings.Count(section, "[X]")
+func (r *ValidationResult) warn(field, message string) {
+	r.Warnings = append(r.Warnings, ValidationError{Field: field, Message: message})
 }
 
-func hasContentAfter(text, marker, template string) bool {
-	lines := strings.Split(text, "\n")
-	for i, line := range lines {
-		if strings.Contains(line, marker) && i+1 < len(lines) {
-			nextLine := strings.TrimSpace(lines[i+1])
-			if nextLine != "" && !strings.Contains(nextLine, template) && !strings.HasPrefix(nextLine, "<!--") {
-				return true
-			}
+func countChecked(boxes []Checkbox) int {
+	count := 0
+	for _, b := range boxes {
+		if b.Checked {
+			count++
 		}
 	}
-	return false
+	return count
 }
 
 // OpenInEditor opens the file in the user's editor
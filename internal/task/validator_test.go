@@ -3,6 +3,7 @@ package task
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -268,6 +269,121 @@ Time estimate: 4h
 	}
 }
 
+func TestGetTimeEstimateCompound(t *testing.T) {
+	content := `# Task
+
+## 🟡 YELLOW LIGHT - Analysis & Planning
+
+### Solution Options
+
+#### Option A:
+- Time estimate: 1h 30m
+
+#### Option B:
+- Time estimate: 2d
+
+### Decision
+**Chosen option:** Option A
+`
+
+	tmpFile := createTempFile(t, content)
+	defer os.Remove(tmpFile)
+
+	hours, err := GetTimeEstimate(tmpFile)
+	if err != nil {
+		t.Fatalf("GetTimeEstimate failed: %v", err)
+	}
+	if hours != 1.5 {
+		t.Errorf("Expected 1.5 hours, got %f", hours)
+	}
+
+	// An estimate in units yo doesn't know is reported, not cut short
+	os.WriteFile(tmpFile, []byte(strings.Replace(content, "Chosen option:** Option A", "Chosen option:** Option B", 1)), 0644)
+	_, err = GetTimeEstimate(tmpFile)
+	if err == nil || !strings.Contains(err.Error(), `unknown unit "d"`) {
+		t.Errorf("Expected unknown unit error, got %v", err)
+	}
+
+	// Free-form plans add up compound estimates too
+	if hours, err := scanTimeEstimate("Time estimate: 2h 15m (maybe more)\n"); err != nil || hours != 2.25 {
+		t.Errorf("Expected 2.25 hours, got %v, %v", hours, err)
+	}
+}
+
+func TestGetTimeEstimateUsesChosenOption(t *testing.T) {
+	content := `# Task
+
+## 🟡 YELLOW LIGHT - Analysis & Planning
+
+### Solution Options
+
+#### Option A:
+- Time estimate: 2h
+
+#### Option B:
+- Time estimate: 4h
+
+#### Option C:
+- Time estimate: 90m
+
+### Decision
+**Chosen option:** Option C
+`
+
+	tmpFile := createTempFile(t, content)
+	defer os.Remove(tmpFile)
+
+	hours, err := GetTimeEstimate(tmpFile)
+	if err != nil {
+		t.Fatalf("GetTimeEstimate failed: %v", err)
+	}
+
+	if hours != 1.5 {
+		t.Errorf("Expected chosen option's 1.5 hours, got %f", hours)
+	}
+}
+
+func TestValidateYellowWarnsOnUnknownOption(t *testing.T) {
+	content := `## 🟡 YELLOW LIGHT - Analysis & Planning
+
+### Solution Options
+
+#### Option A:
+- Time estimate: 2h
+
+#### Option B:
+- Time estimate: 4h
+
+#### Option C:
+- Time estimate: 8h
+
+### Decision
+**Chosen option:** D
+
+### Success Criteria
+- [ ] Works
+`
+
+	tmpFile := createTempFile(t, content)
+	defer os.Remove(tmpFile)
+
+	result, err := ValidateYellow(tmpFile)
+	if err != nil {
+		t.Fatalf("ValidateYellow failed: %v", err)
+	}
+
+	if !result.Valid {
+		t.Errorf("Expected YELLOW to be valid, got errors: %v", result.Errors)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Field != "Decision" {
+		t.Errorf("Expected a Decision warning, got %v", result.Warnings)
+	}
+
+	if _, err := GetTimeEstimate(tmpFile); err == nil {
+		t.Error("Expected no estimate when the chosen option doesn't exist")
+	}
+}
+
 func createTempFile(t *testing.T, content string) string {
 	t.Helper()
 
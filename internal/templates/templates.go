@@ -1,7 +1,7 @@
 package templates
 
-// CurrentTask is the template for .yo/current_task.md
-const CurrentTask = `# Current Task
+// Task is the template for a task file, .yo/tasks/<id>.md
+const Task = `# Current Task
 
 ## 🔴 RED LIGHT - Problem Definition
 
@@ -124,6 +124,16 @@ Use: yo defer -i
 
 `
 
+// BypassLog is the template for .yo/bypass_log.md
+const BypassLog = `# Emergency Bypass Log
+
+Post-incident notes for every emergency bypass.
+Written when a bypass ends: what broke, what you fixed, what to follow up on.
+
+---
+
+`
+
 // SessionSummary is the template for session summaries
 const SessionSummary = `# Session Summary
 
@@ -164,7 +174,7 @@ As an AI agent, you MUST follow this workflow when making changes.
 
 | File | Purpose | When to Update |
 |------|---------|----------------|
-| ` + "`" + `.yo/current_task.md` + "`" + ` | Active task definition | During RED/YELLOW phases |
+| ` + "`" + `.yo/tasks/<id>.md` + "`" + ` | Task definitions, active and parked | During RED/YELLOW phases |
 | ` + "`" + `.yo/backlog.md` + "`" + ` | Prioritized task list | When adding/completing tasks |
 | ` + "`" + `.yo/tech_debt_log.md` + "`" + ` | Conscious shortcuts | When deferring work |
 | ` + "`" + `.yo/state.json` + "`" + ` | Current stage/timer | Managed by yo CLI |
@@ -191,6 +201,17 @@ As an AI agent, you MUST follow this workflow when making changes.
 
 ### Tech Debt
 - ` + "`" + `yo defer "what I'm skipping"` + "`" + ` - Log a conscious shortcut
+- ` + "`" + `yo debt list` + "`" + ` - Outstanding debt; ` + "`" + `yo debt resolve <id> --note "..."` + "`" + ` when fixed
+- ` + "`" + `yo defer "..." --at file.go:120` + "`" + ` - Then mark the spot with a ` + "`" + `yo:debt <id>` + "`" + ` comment; ` + "`" + `yo debt scan` + "`" + ` checks them
+- ` + "`" + `yo debt report` + "`" + ` - Outstanding fix hours; stay under ` + "`" + `debt_budget_hours` + "`" + `
+- ` + "`" + `--when "tasks:5"` + "`" + ` (or ` + "`" + `after:DATE` + "`" + `, ` + "`" + `touch:path` + "`" + `, ` + "`" + `tag:word` + "`" + `) - Make it come due; ` + "`" + `yo status` + "`" + ` shows due debt
+
+### Running Without a Terminal
+Pass ` + "`" + `--no-input` + "`" + ` so yo fails instead of waiting for stdin, and answer
+every prompt with flags (or an ` + "`" + `--answers` + "`" + ` JSON/YAML file):
+- ` + "`" + `yo red --problem "..." --impact users --severity P1` + "`" + `
+- ` + "`" + `yo yellow --answers plan.yaml` + "`" + ` - causes, 3 options, choice, steps, criteria
+- ` + "`" + `yo done --criteria-met all` + "`" + `
 
 ## Agent Instructions
 
@@ -205,7 +226,7 @@ As an AI agent, you MUST follow this workflow when making changes.
 3. Start with ` + "`" + `yo red` + "`" + ` for a new problem
 
 ### During RED Phase
-Fill in ` + "`" + `.yo/current_task.md` + "`" + `:
+Fill in the active task's file (` + "`" + `yo status` + "`" + ` shows its path):
 - **What's the Problem?** - Clear problem statement
 - **Impact** - Check applicable boxes
 - **Severity** - P0/P1/P2/P3
@@ -215,16 +236,18 @@ Complete the planning section:
 - **Root Cause Analysis** - 3 levels deep
 - **Solution Options** - At least 2-3 options with estimates
 - **Decision** - Chosen option with reasoning
-- **Success Criteria** - Testable criteria
+- **Success Criteria** - Testable criteria; end one with ` + "`cmd: go test ./...`" + ` (in backticks) to let yo check it
 
 ### During GREEN Phase
 - Execute the plan
 - Run ` + "`" + `yo timer` + "`" + ` to check remaining time
+- Run ` + "`" + `yo check <n>` + "`" + ` as each success criterion is met
+- Run ` + "`" + `yo verify green` + "`" + ` to run the criteria commands
 - If blocked, document in the Blockers section
 - If deferring work, use ` + "`" + `yo defer` + "`" + `
 
 ### Completing Work
-1. Verify success criteria are met
+1. Verify success criteria are met (` + "`" + `yo criteria` + "`" + ` lists them)
 2. Run ` + "`" + `yo done` + "`" + `
 3. Task is archived to ` + "`" + `.yo/done/` + "`" + `
 
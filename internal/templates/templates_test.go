@@ -5,7 +5,7 @@ import (
 	"testing"
 )
 
-func TestCurrentTaskTemplate(t *testing.T) {
+func TestTaskTemplate(t *testing.T) {
 	// Check required sections
 	sections := []string{
 		"🔴 RED LIGHT",
@@ -24,8 +24,8 @@ func TestCurrentTaskTemplate(t *testing.T) {
 	}
 
 	for _, section := range sections {
-		if !strings.Contains(CurrentTask, section) {
-			t.Errorf("CurrentTask template missing section: %s", section)
+		if !strings.Contains(Task, section) {
+			t.Errorf("Task template missing section: %s", section)
 		}
 	}
 }
@@ -63,8 +63,8 @@ func TestSessionSummaryTemplate(t *testing.T) {
 }
 
 func TestTemplatesNotEmpty(t *testing.T) {
-	if len(CurrentTask) < 100 {
-		t.Error("CurrentTask template is too short")
+	if len(Task) < 100 {
+		t.Error("Task template is too short")
 	}
 
 	if len(Backlog) < 50 {
@@ -0,0 +1,148 @@
+package timer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/notify"
+	"github.com/faisalahmedsifat/yo/internal/state"
+)
+
+// DefaultPollInterval is how often the monitor checks running timers
+const DefaultPollInterval = 30 * time.Second
+
+// Monitor polls workspaces for running GREEN LIGHT timers and sends
+// milestone notifications while the user is working
+type Monitor struct {
+	interval   time.Duration
+	workspaces func() []string // .yo directories to poll
+	stopChan   chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewMonitor creates a monitor that polls the given .yo directories
+func NewMonitor(interval time.Duration, workspaces func() []string) *Monitor {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &Monitor{
+		interval:   interval,
+		workspaces: workspaces,
+		stopChan:   make(chan struct{}),
+	}
+}
+
+// Start polls in the background until Stop is called
+func (m *Monitor) Start() {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		m.Poll()
+		for {
+			select {
+			case <-m.stopChan:
+				return
+			case <-ticker.C:
+				m.Poll()
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() { close(m.stopChan) })
+}
+
+// Poll checks every workspace once
+func (m *Monitor) Poll() {
+	for _, yoDir := range m.workspaces() {
+		if _, err := CheckWorkspace(yoDir); err != nil {
+			fmt.Printf("Timer monitor: %s: %v\n", yoDir, err)
+		}
+		if _, err := CheckBypassExpiry(yoDir); err != nil {
+			fmt.Printf("Timer monitor: %s: %v\n", yoDir, err)
+		}
+	}
+}
+
+// CheckWorkspace fires any newly reached milestones for the workspace's
+// timer. Milestones are persisted to state.json before the notification is
+// sent, so a restarted monitor never repeats them.
+func CheckWorkspace(yoDir string) ([]string, error) {
+	var milestones []string
+	s, err := state.UpdateIn(yoDir, func(s *state.State) error {
+		if s.CurrentStage != "green" || s.Timer.StartedAt.IsZero() || s.Timer.Paused {
+			return state.ErrNoChange
+		}
+		milestones = markMilestones(s)
+		if len(milestones) == 0 {
+			return state.ErrNoChange
+		}
+		return nil
+	})
+	if err != nil || len(milestones) == 0 {
+		return nil, err
+	}
+
+	cfg, err := config.LoadFrom(yoDir)
+	if err != nil {
+		cfg = config.Default()
+	}
+	notifier := notify.New(cfg.Notifications)
+
+	for _, m := range milestones {
+		activity.AppendTo(yoDir, activity.Entry{
+			Type:           activity.TypeTimerMilestone,
+			Task:           s.CurrentTaskID,
+			Milestone:      m,
+			ActualHours:    s.GetElapsed().Hours(),
+			EstimatedHours: s.Timer.EstimatedHours,
+		})
+
+		switch m {
+		case Milestone100:
+			notifier.TimerMilestone100(s.CurrentTaskID)
+		case Milestone150:
+			notifier.TimerMilestone150(s.CurrentTaskID)
+		case Milestone200:
+			notifier.TimerMilestone200(s.CurrentTaskID)
+		}
+	}
+
+	return milestones, nil
+}
+
+// CheckBypassExpiry sends the bypass-expired notification once when the
+// workspace's emergency bypass runs out. Reports whether it was sent.
+func CheckBypassExpiry(yoDir string) (bool, error) {
+	expired := false
+	s, err := state.UpdateIn(yoDir, func(s *state.State) error {
+		if !s.BypassExpired() || s.Bypass.ExpiryNotified {
+			return state.ErrNoChange
+		}
+		s.Bypass.ExpiryNotified = true
+		expired = true
+		return nil
+	})
+	if err != nil || !expired {
+		return false, err
+	}
+
+	activity.AppendTo(yoDir, activity.Entry{
+		Type:   activity.TypeBypassExpired,
+		Reason: s.Bypass.Reason,
+	})
+
+	cfg, err := config.LoadFrom(yoDir)
+	if err != nil {
+		cfg = config.Default()
+	}
+	notify.New(cfg.Notifications).BypassEnded()
+
+	return true, nil
+}
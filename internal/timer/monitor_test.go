@@ -0,0 +1,196 @@
+package timer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/config"
+	"github.com/faisalahmedsifat/yo/internal/state"
+)
+
+func setupMonitorWorkspace(t *testing.T) string {
+	t.Helper()
+
+	yoDir := filepath.Join(t.TempDir(), ".yo")
+	if err := os.MkdirAll(yoDir, 0755); err != nil {
+		t.Fatalf("Failed to create .yo dir: %v", err)
+	}
+
+	// Keep tests from sending real desktop notifications
+	cfg := config.Default()
+	cfg.Notifications = false
+	data, _ := json.Marshal(cfg)
+	os.WriteFile(filepath.Join(yoDir, "config.json"), data, 0644)
+
+	return yoDir
+}
+
+func TestCheckWorkspaceFiresOnce(t *testing.T) {
+	yoDir := setupMonitorWorkspace(t)
+
+	s := state.NewState()
+	s.SetStage("green")
+	s.CurrentTaskID = "slow_task"
+	s.StartTimer(1.0)
+	s.Timer.StartedAt = time.Now().Add(-90 * time.Minute)
+	s.Timer.Intervals = []state.Interval{{Start: s.Timer.StartedAt}}
+	if err := s.SaveTo(yoDir); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	fired, err := CheckWorkspace(yoDir)
+	if err != nil {
+		t.Fatalf("CheckWorkspace failed: %v", err)
+	}
+	if len(fired) != 2 || fired[0] != Milestone100 || fired[1] != Milestone150 {
+		t.Errorf("Expected 100%% and 150%% milestones, got %v", fired)
+	}
+
+	// A second poll (or a restarted monitor) must not repeat them
+	fired, err = CheckWorkspace(yoDir)
+	if err != nil {
+		t.Fatalf("CheckWorkspace failed: %v", err)
+	}
+	if len(fired) != 0 {
+		t.Errorf("Expected no repeated milestones, got %v", fired)
+	}
+
+	loaded, err := state.LoadFrom(yoDir)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if len(loaded.Timer.Notifications) != 2 {
+		t.Errorf("Expected 2 persisted notifications, got %v", loaded.Timer.Notifications)
+	}
+
+	log, _ := os.ReadFile(filepath.Join(yoDir, "activity.jsonl"))
+	if strings.Count(string(log), `"type":"timer_milestone"`) != 2 {
+		t.Errorf("Expected 2 milestone entries in activity log, got:\n%s", log)
+	}
+}
+
+func TestCheckWorkspaceSkipsIdleTimers(t *testing.T) {
+	yoDir := setupMonitorWorkspace(t)
+
+	s := state.NewState()
+	s.SetStage("yellow")
+	if err := s.SaveTo(yoDir); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	fired, err := CheckWorkspace(yoDir)
+	if err != nil {
+		t.Fatalf("CheckWorkspace failed: %v", err)
+	}
+	if len(fired) != 0 {
+		t.Errorf("Expected no milestones outside GREEN LIGHT, got %v", fired)
+	}
+}
+
+func TestCheckWorkspaceCountsActiveTime(t *testing.T) {
+	yoDir := setupMonitorWorkspace(t)
+
+	// Started 90m ago on a 1h estimate, but paused for an hour of it
+	start := time.Now().Add(-90 * time.Minute)
+	s := state.NewState()
+	s.SetStage("green")
+	s.CurrentTaskID = "paused_task"
+	s.StartTimer(1.0)
+	s.Timer.StartedAt = start
+	s.Timer.Intervals = []state.Interval{
+		{Start: start, End: start.Add(15 * time.Minute)},
+		{Start: start.Add(75 * time.Minute)},
+	}
+	if err := s.SaveTo(yoDir); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	fired, err := CheckWorkspace(yoDir)
+	if err != nil {
+		t.Fatalf("CheckWorkspace failed: %v", err)
+	}
+	if len(fired) != 0 {
+		t.Errorf("Expected no milestones from paused time, got %v", fired)
+	}
+}
+
+func TestCheckWorkspaceAfterReset(t *testing.T) {
+	yoDir := setupMonitorWorkspace(t)
+
+	s := state.NewState()
+	s.SetStage("green")
+	s.CurrentTaskID = "slow_task"
+	s.StartTimer(1.0)
+	s.Timer.StartedAt = time.Now().Add(-90 * time.Minute)
+	s.Timer.Intervals = []state.Interval{{Start: s.Timer.StartedAt}}
+	if err := s.SaveTo(yoDir); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+	if fired, err := CheckWorkspace(yoDir); err != nil || len(fired) != 2 {
+		t.Fatalf("Expected 100%% and 150%% milestones, got %v, %v", fired, err)
+	}
+
+	// Extending to 2h puts progress at 75%, so both fire again later
+	s, _ = state.LoadFrom(yoDir)
+	s.ExtendTimer(1.0, "more work")
+	ResetMilestones(s)
+	if len(s.Timer.Notifications) != 0 {
+		t.Errorf("Expected notifications to be reset, got %v", s.Timer.Notifications)
+	}
+	s.SaveTo(yoDir)
+	if fired, _ := CheckWorkspace(yoDir); len(fired) != 0 {
+		t.Errorf("Expected nothing at 75%%, got %v", fired)
+	}
+
+	// ...once progress reaches them on the new threshold
+	s, _ = state.LoadFrom(yoDir)
+	s.Timer.StartedAt = time.Now().Add(-150 * time.Minute)
+	s.Timer.Intervals = []state.Interval{{Start: s.Timer.StartedAt}}
+	s.SaveTo(yoDir)
+	if fired, _ := CheckWorkspace(yoDir); len(fired) != 1 || fired[0] != Milestone100 {
+		t.Errorf("Expected 100%% to fire again, got %v", fired)
+	}
+
+	// Extending by less keeps milestones that are still reached
+	s, _ = state.LoadFrom(yoDir)
+	s.Timer.ThresholdHours = 2.25
+	ResetMilestones(s)
+	if len(s.Timer.Notifications) != 1 {
+		t.Errorf("Expected 100%% notification to be kept, got %v", s.Timer.Notifications)
+	}
+}
+
+func TestCheckBypassExpiry(t *testing.T) {
+	yoDir := setupMonitorWorkspace(t)
+
+	s := state.NewState()
+	s.StartBypass("prod down", 30)
+	if err := s.SaveTo(yoDir); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	sent, err := CheckBypassExpiry(yoDir)
+	if err != nil {
+		t.Fatalf("CheckBypassExpiry failed: %v", err)
+	}
+	if sent {
+		t.Error("Expected no notification while bypass is active")
+	}
+
+	s.Bypass.StartedAt = time.Now().Add(-31 * time.Minute)
+	s.SaveTo(yoDir)
+
+	sent, _ = CheckBypassExpiry(yoDir)
+	if !sent {
+		t.Error("Expected expiry notification")
+	}
+
+	sent, _ = CheckBypassExpiry(yoDir)
+	if sent {
+		t.Error("Expected expiry notification only once")
+	}
+}
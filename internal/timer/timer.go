@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/faisalahmedsifat/yo/internal/activity"
 	"github.com/faisalahmedsifat/yo/internal/state"
 )
 
@@ -18,6 +17,7 @@ const (
 // Status represents the current timer status
 type Status struct {
 	Running        bool
+	Paused         bool
 	Elapsed        time.Duration
 	ElapsedHours   float64
 	Threshold      time.Duration
@@ -27,7 +27,8 @@ type Status struct {
 	Overtime       time.Duration
 }
 
-// GetStatus returns the current timer status
+// GetStatus returns the current timer status. Elapsed only counts active
+// work intervals, so time spent paused is excluded.
 func GetStatus(s *state.State) *Status {
 	if s.Timer.StartedAt.IsZero() {
 		return &Status{Running: false}
@@ -39,6 +40,7 @@ func GetStatus(s *state.State) *Status {
 
 	status := &Status{
 		Running:        true,
+		Paused:         s.Timer.Paused,
 		Elapsed:        elapsed,
 		ElapsedHours:   elapsed.Hours(),
 		Threshold:      threshold,
@@ -54,9 +56,9 @@ func GetStatus(s *state.State) *Status {
 	return status
 }
 
-// CheckMilestones checks if any notification milestones have been reached
-// Returns list of newly reached milestones
-func CheckMilestones(s *state.State) []string {
+// markMilestones records newly reached milestones in the timer's
+// notifications and returns them
+func markMilestones(s *state.State) []string {
 	var newMilestones []string
 	progress := s.GetProgress()
 
@@ -73,15 +75,32 @@ func CheckMilestones(s *state.State) []string {
 		if progress >= m.threshold && !hasNotified(s, m.name) {
 			newMilestones = append(newMilestones, m.name)
 			s.Timer.Notifications = append(s.Timer.Notifications, m.name)
-
-			// Log to activity
-			activity.LogTimerMilestone(m.name, s.GetElapsed().Hours(), s.Timer.EstimatedHours)
 		}
 	}
 
 	return newMilestones
 }
 
+// ResetMilestones forgets milestone notifications that are no longer reached,
+// e.g. after an extension raised the threshold, so they fire again later
+func ResetMilestones(s *state.State) {
+	progress := s.GetProgress()
+	thresholds := map[string]float64{
+		Milestone100: 100,
+		Milestone150: 150,
+		Milestone200: 200,
+	}
+
+	kept := []string{}
+	for _, n := range s.Timer.Notifications {
+		if threshold, ok := thresholds[n]; ok && progress < threshold {
+			continue
+		}
+		kept = append(kept, n)
+	}
+	s.Timer.Notifications = kept
+}
+
 // hasNotified checks if a milestone notification was already sent
 func hasNotified(s *state.State, milestone string) bool {
 	for _, n := range s.Timer.Notifications {
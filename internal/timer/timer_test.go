@@ -29,6 +29,30 @@ func TestGetStatus(t *testing.T) {
 	}
 }
 
+func TestGetStatusPaused(t *testing.T) {
+	s := state.NewState()
+	s.StartTimer(1.0)
+
+	// 1h ago: worked 30m, then paused
+	start := time.Now().Add(-1 * time.Hour)
+	s.Timer.StartedAt = start
+	s.Timer.Intervals = []state.Interval{{Start: start, End: start.Add(30 * time.Minute)}}
+	s.Timer.Paused = true
+
+	status := GetStatus(s)
+	if !status.Paused {
+		t.Error("Expected status to be paused")
+	}
+
+	if status.Elapsed != 30*time.Minute {
+		t.Errorf("Expected 30m active time, got %v", status.Elapsed)
+	}
+
+	if status.Progress < 49 || status.Progress > 51 {
+		t.Errorf("Expected progress ~50%%, got %f", status.Progress)
+	}
+}
+
 func TestFormatDuration(t *testing.T) {
 	tests := []struct {
 		d        time.Duration
@@ -0,0 +1,93 @@
+// Package verify runs the shell commands behind success criteria such as
+// "- [ ] tests pass `cmd: go test ./...`".
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a command when no timeout is configured
+const DefaultTimeout = 5 * time.Minute
+
+// waitDelay is how long a killed command's children get to let go of its
+// output before Run gives up on them
+const waitDelay = 2 * time.Second
+
+// Result is the outcome of one command
+type Result struct {
+	Command  string
+	Passed   bool // exited 0 within the timeout
+	ExitCode int  // -1 when the command could not run or was killed
+	TimedOut bool
+	Duration time.Duration
+	Output   string // stdout and stderr interleaved
+	Err      string // why the command could not run, if it didn't
+}
+
+// Run runs command through the shell in dir, killing it after timeout
+func Run(command, dir string, timeout time.Duration) Result {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.WaitDelay = waitDelay
+
+	start := time.Now()
+	err := cmd.Run()
+	r := Result{
+		Command:  command,
+		Duration: time.Since(start),
+		Output:   strings.TrimRight(out.String(), "\n"),
+		ExitCode: -1,
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		r.TimedOut = true
+	case err == nil:
+		r.Passed = true
+		r.ExitCode = 0
+	case errors.As(err, &exitErr):
+		r.ExitCode = exitErr.ExitCode()
+	default:
+		r.Err = err.Error()
+	}
+	return r
+}
+
+// Tail returns the last n lines of the output
+func (r Result) Tail(n int) string {
+	lines := strings.Split(r.Output, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Summary describes the outcome in a few words, like "passed in 1.2s"
+func (r Result) Summary() string {
+	took := r.Duration.Round(100 * time.Millisecond).String()
+	switch {
+	case r.Passed:
+		return "passed in " + took
+	case r.TimedOut:
+		return "timed out after " + took
+	case r.Err != "":
+		return "could not run: " + r.Err
+	}
+	return fmt.Sprintf("failed with exit code %d in %s", r.ExitCode, took)
+}
@@ -0,0 +1,48 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+
+	r := Run("echo ok && pwd", dir, time.Minute)
+	if !r.Passed || r.ExitCode != 0 || !strings.HasPrefix(r.Output, "ok\n") {
+		t.Errorf("Expected a pass, got %+v", r)
+	}
+	if !strings.HasSuffix(r.Output, dir[strings.LastIndex(dir, "/"):]) {
+		t.Errorf("Expected the command to run in %s, got %q", dir, r.Output)
+	}
+
+	r = Run("echo broken >&2; exit 3", dir, time.Minute)
+	if r.Passed || r.ExitCode != 3 || r.Output != "broken" {
+		t.Errorf("Expected exit code 3, got %+v", r)
+	}
+	if !strings.HasPrefix(r.Summary(), "failed with exit code 3") {
+		t.Errorf("Unexpected summary %q", r.Summary())
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	start := time.Now()
+	r := Run("sleep 10 & sleep 10", t.TempDir(), 200*time.Millisecond)
+	if r.Passed || !r.TimedOut {
+		t.Errorf("Expected a timeout, got %+v", r)
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Expected the command to be killed, took %v", took)
+	}
+}
+
+func TestTail(t *testing.T) {
+	r := Result{Output: "a\nb\nc\nd"}
+	if got := r.Tail(2); got != "c\nd" {
+		t.Errorf("Tail(2) = %q", got)
+	}
+	if got := r.Tail(10); got != "a\nb\nc\nd" {
+		t.Errorf("Tail(10) = %q", got)
+	}
+}
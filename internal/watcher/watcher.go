@@ -2,13 +2,18 @@ package watcher
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
+	"github.com/faisalahmedsifat/yo/internal/activity"
+	"github.com/faisalahmedsifat/yo/internal/registry"
+	"github.com/faisalahmedsifat/yo/internal/state"
 	"github.com/fsnotify/fsnotify"
 )
 
@@ -24,19 +29,31 @@ type Watcher struct {
 	fsWatcher *fsnotify.Watcher
 	config    *GlobalConfig
 	repos     map[string]bool // Detected git repositories
+	dirs      map[string]bool // Directories being watched
 	debouncer map[string]time.Time
+	pending   *pendingRename
 	mu        sync.Mutex
 	stopChan  chan struct{}
 	logFile   *os.File
+
+	registry        *registry.Registry // Workspaces events are routed to
+	registryModTime time.Time
+}
+
+// pendingRename is a path renamed away, waiting for the Create of its new
+// name
+type pendingRename struct {
+	path  string
+	timer *time.Timer
 }
 
+// renameWindow is how long a Rename waits for the Create of its new name
+// before it counts as a deletion: the path moved out of the watched tree
+const renameWindow = 500 * time.Millisecond
+
 // GetGlobalYoDir returns the global .yo directory in user's home
 func GetGlobalYoDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".yo"), nil
+	return registry.GetGlobalDir()
 }
 
 // GetGlobalConfigPath returns path to global config
@@ -120,6 +137,7 @@ func New() (*Watcher, error) {
 		fsWatcher: fsWatcher,
 		config:    config,
 		repos:     make(map[string]bool),
+		dirs:      make(map[string]bool),
 		debouncer: make(map[string]time.Time),
 		stopChan:  make(chan struct{}),
 	}, nil
@@ -202,27 +220,39 @@ func (w *Watcher) discoverRepos() error {
 
 // addDirRecursively adds a directory and subdirectories to the watcher
 func (w *Watcher) addDirRecursively(root string) error {
+	if w.dirs == nil {
+		w.dirs = make(map[string]bool)
+	}
 	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 
 		if info.IsDir() {
-			name := info.Name()
-			// Skip common non-project directories
-			if name == "node_modules" || name == "vendor" || name == ".git" ||
-				name == "__pycache__" || name == ".cache" || name == "dist" ||
-				name == "build" || name == ".next" || name == ".yo" {
+			if skippedDir(info.Name()) {
 				return filepath.SkipDir
 			}
 
-			return w.fsWatcher.Add(path)
+			if err := w.fsWatcher.Add(path); err != nil {
+				return err
+			}
+			w.dirs[path] = true
 		}
 
 		return nil
 	})
 }
 
+// skippedDir reports whether a directory is never watched
+func skippedDir(name string) bool {
+	// Skip common non-project directories
+	switch name {
+	case "node_modules", "vendor", ".git", "__pycache__", ".cache", "dist", "build", ".next", ".yo":
+		return true
+	}
+	return false
+}
+
 // eventLoop handles file system events
 func (w *Watcher) eventLoop() {
 	for {
@@ -235,8 +265,15 @@ func (w *Watcher) eventLoop() {
 				return
 			}
 
-			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+			switch {
+			case event.Op&fsnotify.Create != 0:
+				w.handleCreate(event.Name)
+			case event.Op&fsnotify.Write != 0:
 				w.handleFileChange(event.Name)
+			case event.Op&fsnotify.Rename != 0:
+				w.handleRename(event.Name)
+			case event.Op&fsnotify.Remove != 0:
+				w.handleRemove(event.Name)
 			}
 
 		case err, ok := <-w.fsWatcher.Errors:
@@ -253,6 +290,113 @@ func (w *Watcher) handleFileChange(path string) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	w.changed(path)
+}
+
+// handleCreate processes a new file or directory. A Create right after a
+// Rename in the same directory is the new name of the renamed path.
+func (w *Watcher) handleCreate(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	from := ""
+	if p := w.pending; p != nil && w.renamedTo(p.path, path) {
+		p.timer.Stop()
+		from = p.path
+		w.pending = nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if from != "" {
+			w.deleted(from)
+		}
+		return // Gone again already
+	}
+
+	if info.IsDir() {
+		if skippedDir(info.Name()) {
+			if from != "" {
+				w.deleted(from) // moved out of sight
+			}
+			return
+		}
+		if err := w.addDirRecursively(path); err != nil {
+			fmt.Printf("Warning: failed to watch %s: %v\n", path, err)
+		}
+	}
+
+	if from != "" {
+		w.record(activity.Entry{Type: activity.TypeFileRename, From: from}, path)
+		return
+	}
+
+	if info.IsDir() {
+		// Files can land in a new directory before its watch is added
+		filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if fi.IsDir() && skippedDir(fi.Name()) {
+				return filepath.SkipDir
+			}
+			if fi.Mode().IsRegular() {
+				w.changed(p)
+			}
+			return nil
+		})
+		return
+	}
+
+	w.changed(path)
+}
+
+// renamedTo reports whether path can be the new name of the path renamed
+// away from: unrelated Creates land within the rename window too. The
+// caller holds w.mu.
+func (w *Watcher) renamedTo(from, path string) bool {
+	return filepath.Dir(from) == filepath.Dir(path) && w.findRepo(from) == w.findRepo(path)
+}
+
+// handleRename processes a path renamed away. It is logged as a rename if
+// the Create of the new name follows, and as a deletion otherwise.
+func (w *Watcher) handleRename(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.unwatch(path)
+
+	// An earlier rename never saw its new name
+	if w.pending != nil {
+		w.pending.timer.Stop()
+		w.deleted(w.pending.path)
+	}
+
+	p := &pendingRename{path: path}
+	p.timer = time.AfterFunc(renameWindow, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if w.pending == p {
+			w.pending = nil
+			w.deleted(p.path)
+		}
+	})
+	w.pending = p
+}
+
+// handleRemove processes a deleted file or directory
+func (w *Watcher) handleRemove(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.unwatch(path)
+	w.deleted(path)
+}
+
+// changed logs a file change, at most once a second per file. The caller
+// holds w.mu.
+func (w *Watcher) changed(path string) {
 	// Debounce: ignore if we saw this file in the last second
 	if lastSeen, ok := w.debouncer[path]; ok {
 		if time.Since(lastSeen) < time.Second {
@@ -261,6 +405,34 @@ func (w *Watcher) handleFileChange(path string) {
 	}
 	w.debouncer[path] = time.Now()
 
+	w.record(activity.Entry{Type: activity.TypeFileChange}, path)
+}
+
+// deleted logs a deleted path. The caller holds w.mu.
+func (w *Watcher) deleted(path string) {
+	delete(w.debouncer, path)
+	w.record(activity.Entry{Type: activity.TypeFileDelete}, path)
+}
+
+// unwatch drops the watches on path and the directories below it. The
+// caller holds w.mu.
+func (w *Watcher) unwatch(path string) {
+	for dir := range w.dirs {
+		if dir == path || strings.HasPrefix(dir, path+string(filepath.Separator)) {
+			// Deleted directories lose their watch on their own, so
+			// errors here are expected
+			if w.fsWatcher != nil {
+				w.fsWatcher.Remove(dir)
+			}
+			delete(w.dirs, dir)
+		}
+	}
+}
+
+// record fills in the repo and file of an entry about path and logs it to
+// the workspace that contains the path. Changes outside every workspace
+// go to the current project as untracked. The caller holds w.mu.
+func (w *Watcher) record(entry activity.Entry, path string) {
 	// Skip hidden files and directories
 	if strings.Contains(path, "/.") {
 		return
@@ -271,13 +443,49 @@ func (w *Watcher) handleFileChange(path string) {
 	if repo == "" {
 		return
 	}
+	entry.Repo = repo
+	entry.File = strings.TrimPrefix(path, repo+"/")
+	if entry.From != "" {
+		entry.From = strings.TrimPrefix(entry.From, repo+"/")
+	}
+
+	if ws, ok := w.loadRegistry().Find(path); ok {
+		w.logActivity(ws.YoDir(), entry)
+		return
+	}
+
+	if w.config.CurrentDir != "" {
+		entry.Untracked = true
+		w.logActivity(filepath.Join(w.config.CurrentDir, ".yo"), entry)
+	}
+}
+
+// loadRegistry returns the workspace registry, re-reading it when
+// workspaces.json has changed since the last event
+func (w *Watcher) loadRegistry() *registry.Registry {
+	path, err := registry.GetPath()
+	if err != nil {
+		return &registry.Registry{}
+	}
+
+	info, err := os.Stat(path)
+	if err == nil && w.registry != nil && info.ModTime().Equal(w.registryModTime) {
+		return w.registry
+	}
 
-	// Check if this is the current active project
-	currentDir := w.config.CurrentDir
-	isUntracked := currentDir != "" && !strings.HasPrefix(path, currentDir)
+	r, err := registry.LoadFrom(path)
+	if err != nil {
+		if w.registry != nil {
+			return w.registry
+		}
+		return &registry.Registry{}
+	}
 
-	// Log to the current project's activity.jsonl if there is one
-	w.logActivity(path, repo, isUntracked)
+	w.registry = r
+	if info != nil {
+		w.registryModTime = info.ModTime()
+	}
+	return r
 }
 
 // findRepo finds which repo a file belongs to
@@ -290,40 +498,19 @@ func (w *Watcher) findRepo(path string) string {
 	return ""
 }
 
-// logActivity logs a file change to the activity log
-func (w *Watcher) logActivity(path, repo string, untracked bool) {
-	// Find the .yo directory for the current project
-	currentDir := w.config.CurrentDir
-	if currentDir == "" {
-		return // No active project
-	}
-
-	activityPath := filepath.Join(currentDir, ".yo", "activity.jsonl")
-	if _, err := os.Stat(filepath.Dir(activityPath)); os.IsNotExist(err) {
-		return // No .yo directory in current project
-	}
-
-	// Create log entry
-	entry := map[string]interface{}{
-		"ts":        time.Now().Format(time.RFC3339Nano),
-		"type":      "file_change",
-		"repo":      repo,
-		"file":      strings.TrimPrefix(path, repo+"/"),
-		"untracked": untracked,
-	}
-
-	data, err := json.Marshal(entry)
-	if err != nil {
-		return
+// logActivity logs a file event to a workspace's activity log
+func (w *Watcher) logActivity(yoDir string, entry activity.Entry) {
+	if _, err := os.Stat(yoDir); os.IsNotExist(err) {
+		return // Workspace was removed
 	}
 
-	f, err := os.OpenFile(activityPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
-	if err != nil {
-		return
+	// Attribute the change to the active task
+	if s, err := state.LoadFrom(yoDir); err == nil && s.HasTask() {
+		entry.Task = s.CurrentTaskID
+		entry.Stage = s.CurrentStage
 	}
-	defer f.Close()
 
-	f.WriteString(string(data) + "\n")
+	activity.AppendTo(yoDir, entry)
 }
 
 // writePidFile writes the current process ID to the pid file
@@ -350,29 +537,65 @@ func IsRunning() (bool, int) {
 		return false, 0
 	}
 
+	pid := readPid(pidPath)
+	if pid == 0 {
+		return false, 0
+	}
+
+	if !processAlive(pid) {
+		os.Remove(pidPath) // Clean up stale pid file
+		return false, 0
+	}
+
+	return true, pid
+}
+
+// StalePidFile reports a pid file left behind by a watcher that is no
+// longer running, without removing it
+func StalePidFile() (string, int, bool) {
+	pidPath, err := GetPidFilePath()
+	if err != nil {
+		return "", 0, false
+	}
+
+	if _, err := os.Stat(pidPath); err != nil {
+		return "", 0, false
+	}
+
+	pid := readPid(pidPath)
+	if pid != 0 && processAlive(pid) {
+		return "", 0, false
+	}
+	return pidPath, pid, true
+}
+
+// readPid returns the PID recorded in the pid file, or 0
+func readPid(pidPath string) int {
 	data, err := os.ReadFile(pidPath)
 	if err != nil {
-		return false, 0
+		return 0
 	}
 
 	var pid int
 	fmt.Sscanf(string(data), "%d", &pid)
+	return pid
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
 
-	// Check if process exists
 	process, err := os.FindProcess(pid)
 	if err != nil {
-		return false, 0
+		return false
 	}
 
 	// On Unix, FindProcess always succeeds, so we need to send signal 0
 	// to check if process actually exists
-	err = process.Signal(os.Signal(nil))
-	if err != nil {
-		os.Remove(pidPath) // Clean up stale pid file
-		return false, 0
-	}
-
-	return true, pid
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
 }
 
 // SetCurrentProject updates the current active project directory
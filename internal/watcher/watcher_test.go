@@ -1,9 +1,15 @@
 package watcher
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/faisalahmedsifat/yo/internal/registry"
+	"github.com/fsnotify/fsnotify"
 )
 
 func setupTestHome(t *testing.T) (string, func()) {
@@ -196,6 +202,31 @@ func TestIsRunningStalePid(t *testing.T) {
 	}
 }
 
+func TestIsRunningLivePid(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	// The test process itself stands in for a running daemon
+	pidPath := filepath.Join(tmpDir, ".yo", "watcher.pid")
+	os.WriteFile(pidPath, []byte(fmt.Sprintf("%d", os.Getpid())), 0644)
+
+	running, pid := IsRunning()
+	if !running || pid != os.Getpid() {
+		t.Errorf("Expected running with pid %d, got %v %d", os.Getpid(), running, pid)
+	}
+	if _, _, stale := StalePidFile(); stale {
+		t.Error("Expected live pid file not to be stale")
+	}
+
+	os.WriteFile(pidPath, []byte("999999999"), 0644)
+	if path, pid, stale := StalePidFile(); !stale || pid != 999999999 || path != pidPath {
+		t.Errorf("Expected stale pid file, got %s %d %v", path, pid, stale)
+	}
+	if _, err := os.Stat(pidPath); err != nil {
+		t.Error("StalePidFile should not remove the pid file")
+	}
+}
+
 func TestNewWatcher(t *testing.T) {
 	_, cleanup := setupTestHome(t)
 	defer cleanup()
@@ -252,3 +283,184 @@ func TestWatcherFindRepo(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleFileChangeRoutesToWorkspace(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+
+	// Two projects in one repo, one registered, plus an active project
+	repo := filepath.Join(tmpDir, "Dev", "mono")
+	api := filepath.Join(repo, "api")
+	active := filepath.Join(tmpDir, "Dev", "active")
+	for _, dir := range []string{filepath.Join(api, ".yo"), filepath.Join(active, ".yo")} {
+		os.MkdirAll(dir, 0755)
+	}
+
+	r, _ := registry.Load()
+	r.Add(api)
+	if err := r.Save(); err != nil {
+		t.Fatalf("Failed to save registry: %v", err)
+	}
+
+	w := &Watcher{
+		config:    &GlobalConfig{CurrentDir: active},
+		repos:     map[string]bool{repo: true},
+		debouncer: make(map[string]time.Time),
+	}
+
+	w.handleFileChange(filepath.Join(api, "main.go"))
+	w.handleFileChange(filepath.Join(repo, "web", "app.js"))
+
+	apiLog, _ := os.ReadFile(filepath.Join(api, ".yo", "activity.jsonl"))
+	if !strings.Contains(string(apiLog), `"file":"api/main.go"`) || strings.Contains(string(apiLog), `"untracked"`) {
+		t.Errorf("Expected api change in api workspace, got: %s", apiLog)
+	}
+	if strings.Contains(string(apiLog), "app.js") {
+		t.Errorf("Unexpected web change in api workspace: %s", apiLog)
+	}
+
+	activeLog, _ := os.ReadFile(filepath.Join(active, ".yo", "activity.jsonl"))
+	if !strings.Contains(string(activeLog), `"file":"web/app.js"`) || !strings.Contains(string(activeLog), `"untracked":true`) {
+		t.Errorf("Expected untracked web change in active workspace, got: %s", activeLog)
+	}
+}
+
+// newTestWorkspace sets up a repo that is a registered workspace and a
+// watcher for it
+func newTestWorkspace(t *testing.T, tmpDir string) (*Watcher, string) {
+	t.Helper()
+
+	repo := filepath.Join(tmpDir, "Dev", "proj")
+	if err := os.MkdirAll(filepath.Join(repo, ".yo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := registry.Load()
+	r.Add(repo)
+	if err := r.Save(); err != nil {
+		t.Fatalf("Failed to save registry: %v", err)
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("Failed to create fsnotify watcher: %v", err)
+	}
+	w := &Watcher{
+		fsWatcher: fsWatcher,
+		config:    &GlobalConfig{},
+		repos:     map[string]bool{repo: true},
+		debouncer: make(map[string]time.Time),
+		stopChan:  make(chan struct{}),
+	}
+	t.Cleanup(func() { fsWatcher.Close() })
+	return w, repo
+}
+
+func readActivity(repo string) string {
+	data, _ := os.ReadFile(filepath.Join(repo, ".yo", "activity.jsonl"))
+	return string(data)
+}
+
+func TestRenameAndRemove(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+	w, repo := newTestWorkspace(t, tmpDir)
+
+	src := filepath.Join(repo, "src")
+	os.MkdirAll(src, 0755)
+	if err := w.addDirRecursively(src); err != nil {
+		t.Fatalf("addDirRecursively failed: %v", err)
+	}
+
+	// A rename is a Rename of the old name, then a Create of the new one
+	os.WriteFile(filepath.Join(src, "new.go"), nil, 0644)
+	w.handleRename(filepath.Join(src, "old.go"))
+	w.handleCreate(filepath.Join(src, "new.go"))
+	if log := readActivity(repo); !strings.Contains(log, `"type":"file_rename","repo":"`+repo+`","file":"src/new.go"`) ||
+		!strings.Contains(log, `"from":"src/old.go"`) {
+		t.Errorf("Expected a file_rename, got: %s", log)
+	}
+
+	// A Create in another directory isn't the new name, even within the window
+	other := filepath.Join(repo, "other")
+	os.MkdirAll(other, 0755)
+	os.WriteFile(filepath.Join(other, "unrelated.go"), nil, 0644)
+	w.handleRename(filepath.Join(src, "new.go"))
+	w.handleCreate(filepath.Join(other, "unrelated.go"))
+	if log := readActivity(repo); strings.Contains(log, `"file":"other/unrelated.go","from"`) ||
+		!strings.Contains(log, `"type":"file_change","repo":"`+repo+`","file":"other/unrelated.go"`) {
+		t.Errorf("Expected unrelated.go to be a file_change, got: %s", log)
+	}
+	if w.pending == nil || w.pending.path != filepath.Join(src, "new.go") {
+		t.Error("Expected the rename to keep waiting for its new name")
+	}
+
+	// A renamed directory loses its watches
+	w.handleRename(src)
+	if w.dirs[src] {
+		t.Error("Expected the renamed directory to be unwatched")
+	}
+
+	// ...and with no new name inside the tree, it counts as deleted
+	time.Sleep(renameWindow + 200*time.Millisecond)
+	if log := readActivity(repo); !strings.Contains(log, `"type":"file_delete","repo":"`+repo+`","file":"src"`) {
+		t.Errorf("Expected a rename out of the tree to be a file_delete, got: %s", log)
+	}
+
+	w.handleRemove(filepath.Join(repo, "gone.go"))
+	if log := readActivity(repo); !strings.Contains(log, `"file":"gone.go"`) {
+		t.Errorf("Expected a file_delete for gone.go, got: %s", log)
+	}
+}
+
+func TestWatchesNewDirectories(t *testing.T) {
+	tmpDir, cleanup := setupTestHome(t)
+	defer cleanup()
+	w, repo := newTestWorkspace(t, tmpDir)
+
+	if err := w.addDirRecursively(repo); err != nil {
+		t.Fatalf("addDirRecursively failed: %v", err)
+	}
+	go w.eventLoop()
+	defer close(w.stopChan)
+
+	pkg := filepath.Join(repo, "pkg", "auth")
+	if err := os.MkdirAll(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the new directories to be watched, then write in them
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		w.mu.Lock()
+		watched := w.dirs[pkg]
+		w.mu.Unlock()
+		if watched {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the new directory to be watched")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	os.WriteFile(filepath.Join(pkg, "token.go"), []byte("package auth\n"), 0644)
+
+	for !strings.Contains(readActivity(repo), `"file":"pkg/auth/token.go"`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a change in the new directory, got: %s", readActivity(repo))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	os.RemoveAll(filepath.Join(repo, "pkg"))
+	for !strings.Contains(readActivity(repo), `"type":"file_delete","repo":"`+repo+`","file":"pkg"`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the removed directory logged, got: %s", readActivity(repo))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.dirs[pkg] {
+		t.Error("Expected the removed directory to be unwatched")
+	}
+}
@@ -1,6 +1,7 @@
 package workspace
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,41 +11,49 @@ import (
 	"github.com/faisalahmedsifat/yo/internal/templates"
 )
 
-// Init creates the .yo workspace structure
+// ErrNotInitialized is returned by commands run outside a workspace
+var ErrNotInitialized = state.ErrNotInitialized
+
+// ErrNoTask is returned by commands that need an active task
+var ErrNoTask = errors.New("no active task. Start one with 'yo red'")
+
+// Dirs are the subdirectories of .yo
+var Dirs = []string{"done", "sessions", "stats", "tasks"}
+
+// TemplateFiles are the files Init creates in .yo, with their content
+var TemplateFiles = map[string]string{
+	"backlog.md":       templates.Backlog,
+	"tech_debt_log.md": templates.TechDebtLog,
+	"bypass_log.md":    templates.BypassLog,
+	"AGENTS.md":        templates.Agents,
+}
+
+// Init creates the .yo workspace structure in the current directory (or
+// $YO_DIR), even when a parent directory already has a workspace
 func Init() error {
-	yoDir, err := state.GetYoDir()
+	yoDir, err := initDir()
 	if err != nil {
 		return err
 	}
 
 	// Check if already initialized
-	if _, err := os.Stat(yoDir); err == nil {
+	if _, err := os.Stat(filepath.Join(yoDir, "state.json")); err == nil {
 		return fmt.Errorf("workspace already initialized at %s", yoDir)
 	}
 
 	// Create directory structure
-	dirs := []string{
-		yoDir,
-		filepath.Join(yoDir, "done"),
-		filepath.Join(yoDir, "sessions"),
-		filepath.Join(yoDir, "stats"),
+	if err := os.MkdirAll(yoDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", yoDir, err)
 	}
-
-	for _, dir := range dirs {
+	for _, name := range Dirs {
+		dir := filepath.Join(yoDir, name)
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 	}
 
 	// Create template files
-	files := map[string]string{
-		"current_task.md":  templates.CurrentTask,
-		"backlog.md":       templates.Backlog,
-		"tech_debt_log.md": templates.TechDebtLog,
-		"AGENTS.md":        templates.Agents,
-	}
-
-	for name, content := range files {
+	for name, content := range TemplateFiles {
 		path := filepath.Join(yoDir, name)
 		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 			return fmt.Errorf("failed to create %s: %w", name, err)
@@ -53,13 +62,13 @@ func Init() error {
 
 	// Create state.json
 	s := state.NewState()
-	if err := s.Save(); err != nil {
+	if err := s.SaveTo(yoDir); err != nil {
 		return fmt.Errorf("failed to create state.json: %w", err)
 	}
 
 	// Create config.json
 	cfg := config.Default()
-	if err := cfg.Save(); err != nil {
+	if err := cfg.SaveTo(yoDir); err != nil {
 		return fmt.Errorf("failed to create config.json: %w", err)
 	}
 
@@ -72,23 +81,69 @@ func Init() error {
 	return nil
 }
 
+// initDir returns where Init creates the workspace
+func initDir() (string, error) {
+	if dir := os.Getenv(state.YoDirEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return filepath.Join(cwd, ".yo"), nil
+}
+
 // IsInitialized checks if workspace is initialized
 func IsInitialized() bool {
 	yoDir, err := state.GetYoDir()
 	if err != nil {
 		return false
 	}
-	_, err = os.Stat(yoDir)
+	_, err = os.Stat(filepath.Join(yoDir, "state.json"))
 	return err == nil
 }
 
-// GetCurrentTaskPath returns path to current_task.md
-func GetCurrentTaskPath() (string, error) {
+// GetCurrentTaskPath returns path to the active task's file
+func GetCurrentTaskPath(s *state.State) (string, error) {
+	if s.CurrentTaskID == "" {
+		return "", ErrNoTask
+	}
+	return GetTaskPath(s.CurrentTaskID)
+}
+
+// GetTaskPath returns path to a task's file, tasks/<id>.md
+func GetTaskPath(id string) (string, error) {
 	yoDir, err := state.GetYoDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(yoDir, "current_task.md"), nil
+	return filepath.Join(yoDir, "tasks", id+".md"), nil
+}
+
+// CreateTask writes a blank task file for id and returns its path. It
+// never overwrites an existing task.
+func CreateTask(id string) (string, error) {
+	path, err := GetTaskPath(id)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create tasks directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return "", fmt.Errorf("task file tasks/%s.md already exists. Run 'yo doctor'", id)
+		}
+		return "", fmt.Errorf("failed to create task file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(templates.Task); err != nil {
+		return "", fmt.Errorf("failed to write task file: %w", err)
+	}
+	return path, nil
 }
 
 // GetBacklogPath returns path to backlog.md
@@ -100,6 +155,15 @@ func GetBacklogPath() (string, error) {
 	return filepath.Join(yoDir, "backlog.md"), nil
 }
 
+// GetBypassLogPath returns path to bypass_log.md
+func GetBypassLogPath() (string, error) {
+	yoDir, err := state.GetYoDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(yoDir, "bypass_log.md"), nil
+}
+
 // GetTechDebtPath returns path to tech_debt_log.md
 func GetTechDebtPath() (string, error) {
 	yoDir, err := state.GetYoDir()
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/faisalahmedsifat/yo/internal/state"
 )
 
 func setupTestDir(t *testing.T) (string, func()) {
@@ -43,7 +45,6 @@ func TestInit(t *testing.T) {
 
 	// Verify files were created
 	expectedFiles := []string{
-		"current_task.md",
 		"backlog.md",
 		"tech_debt_log.md",
 		"state.json",
@@ -59,7 +60,7 @@ func TestInit(t *testing.T) {
 	}
 
 	// Verify subdirectories were created
-	expectedDirs := []string{"done", "sessions", "stats"}
+	expectedDirs := []string{"done", "sessions", "stats", "tasks"}
 	for _, d := range expectedDirs {
 		path := filepath.Join(yoDir, d)
 		if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -107,17 +108,46 @@ func TestGetCurrentTaskPath(t *testing.T) {
 	tmpDir, cleanup := setupTestDir(t)
 	defer cleanup()
 
-	path, err := GetCurrentTaskPath()
+	s := state.NewState()
+	if _, err := GetCurrentTaskPath(s); err != ErrNoTask {
+		t.Errorf("Expected ErrNoTask without a task, got %v", err)
+	}
+
+	s.CurrentTaskID = "fix_login_a1b2c3"
+	path, err := GetCurrentTaskPath(s)
 	if err != nil {
 		t.Fatalf("GetCurrentTaskPath failed: %v", err)
 	}
 
-	expected := filepath.Join(tmpDir, ".yo", "current_task.md")
+	expected := filepath.Join(tmpDir, ".yo", "tasks", "fix_login_a1b2c3.md")
 	if path != expected {
 		t.Errorf("Expected path %s, got %s", expected, path)
 	}
 }
 
+func TestCreateTask(t *testing.T) {
+	_, cleanup := setupTestDir(t)
+	defer cleanup()
+
+	if err := Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	path, err := CreateTask("fix_login_a1b2c3")
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	os.WriteFile(path, []byte("# Current Task\n\nLogin plan\n"), 0644)
+
+	// A second task with the same ID must not overwrite the first
+	if _, err := CreateTask("fix_login_a1b2c3"); err == nil {
+		t.Error("Expected CreateTask to refuse an existing task file")
+	}
+	if content, _ := os.ReadFile(path); !contains(string(content), "Login plan") {
+		t.Error("Expected the existing task file to be kept")
+	}
+}
+
 func TestGetBacklogPath(t *testing.T) {
 	tmpDir, cleanup := setupTestDir(t)
 	defer cleanup()
@@ -148,7 +178,7 @@ func TestGetTechDebtPath(t *testing.T) {
 	}
 }
 
-func TestCurrentTaskTemplate(t *testing.T) {
+func TestTaskTemplate(t *testing.T) {
 	_, cleanup := setupTestDir(t)
 	defer cleanup()
 
@@ -156,7 +186,10 @@ func TestCurrentTaskTemplate(t *testing.T) {
 		t.Fatalf("Init failed: %v", err)
 	}
 
-	taskPath, _ := GetCurrentTaskPath()
+	taskPath, err := CreateTask("fix_login_a1b2c3")
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
 	content, err := os.ReadFile(taskPath)
 	if err != nil {
 		t.Fatalf("Failed to read task file: %v", err)
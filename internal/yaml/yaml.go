@@ -0,0 +1,170 @@
+// Package yaml reads and writes the YAML yo needs: --output yaml and
+// answers files. Marshal writes scalars the way Parse reads them back.
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Marshal encodes v as YAML. Values go through encoding/json first, so
+// json tags decide the field names and order, and JSON and YAML output
+// always share one schema.
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeNode(dec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if root.inline() {
+		buf.WriteString(root.scalar() + "\n")
+	} else {
+		root.write(&buf, 0)
+	}
+	return buf.Bytes(), nil
+}
+
+// node is a JSON value that keeps object keys in order
+type node struct {
+	value interface{} // scalar: string, json.Number, bool or nil
+	keys  []string    // object keys, in order
+	vals  []*node     // object values or array items
+	kind  byte        // 's'calar, 'o'bject or 'a'rray
+}
+
+func decodeNode(dec *json.Decoder) (*node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		n := &node{kind: 'a'}
+		if t == '{' {
+			n.kind = 'o'
+		}
+		for dec.More() {
+			if n.kind == 'o' {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+			}
+			child, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			n.vals = append(n.vals, child)
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return nil, err
+		}
+		return n, nil
+	default:
+		return &node{kind: 's', value: t}, nil
+	}
+}
+
+// inline reports whether the node fits on the line of its key
+func (n *node) inline() bool {
+	return n.kind == 's' || len(n.vals) == 0
+}
+
+func (n *node) scalar() string {
+	switch n.kind {
+	case 'o':
+		return "{}"
+	case 'a':
+		return "[]"
+	}
+	switch v := n.value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	}
+	return fmt.Sprint(n.value)
+}
+
+func (n *node) write(buf *bytes.Buffer, indent int) {
+	pad := strings.Repeat(" ", indent)
+
+	if n.kind == 'o' {
+		for i, key := range n.keys {
+			child := n.vals[i]
+			buf.WriteString(pad + yamlString(key) + ":")
+			if child.inline() {
+				buf.WriteString(" " + child.scalar() + "\n")
+				continue
+			}
+			buf.WriteString("\n")
+			child.write(buf, indent+2)
+		}
+		return
+	}
+
+	for _, child := range n.vals {
+		if child.inline() {
+			buf.WriteString(pad + "- " + child.scalar() + "\n")
+			continue
+		}
+		// Write the item two deeper, then hang its first line on the dash
+		var item bytes.Buffer
+		child.write(&item, indent+2)
+		buf.WriteString(pad + "- " + strings.TrimPrefix(item.String(), pad+"  "))
+	}
+}
+
+// yamlString returns s plain when YAML reads it back as the same string,
+// and double-quoted otherwise
+func yamlString(s string) string {
+	if needsQuotes(s) {
+		data, _ := json.Marshal(s) // JSON strings are valid YAML double-quoted scalars
+		return string(data)
+	}
+	return s
+}
+
+func needsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return true
+	}
+	if json.Valid([]byte(s)) { // numbers and the like
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
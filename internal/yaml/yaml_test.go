@@ -0,0 +1,131 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	v := struct {
+		Name    string            `json:"name"`
+		Count   int               `json:"count"`
+		Ready   bool              `json:"ready"`
+		Missing *int              `json:"missing"`
+		Tags    []string          `json:"tags"`
+		Empty   []string          `json:"empty"`
+		Tricky  []string          `json:"tricky"`
+		Nested  []map[string]int  `json:"nested"`
+		Labels  map[string]string `json:"labels"`
+	}{
+		Name:   "Fix login",
+		Count:  3,
+		Ready:  true,
+		Tags:   []string{"auth", "web"},
+		Empty:  []string{},
+		Tricky: []string{"", "true", "42", "#tag", "a: b", " padded", "line\nbreak"},
+		Nested: []map[string]int{{"a": 1, "b": 2}},
+		Labels: map[string]string{},
+	}
+
+	data, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	expected := `name: Fix login
+count: 3
+ready: true
+missing: null
+tags:
+  - auth
+  - web
+empty: []
+tricky:
+  - ""
+  - "true"
+  - "42"
+  - "#tag"
+  - "a: b"
+  - " padded"
+  - "line\nbreak"
+nested:
+  - a: 1
+    b: 2
+labels: {}
+`
+	if string(data) != expected {
+		t.Errorf("Unexpected YAML:\n%s\nexpected:\n%s", data, expected)
+	}
+}
+
+func TestParse(t *testing.T) {
+	data := `# answers
+problem: "Login: broken"   # quoted
+impact: [users, 'frustration']
+severity: P1
+empty: ~
+step:
+- First
+- Second
+option:
+  - Patch | 2h | quick | fragile
+  - description: Rewrite
+    estimate: 2d
+nested:
+  key: value
+`
+	v, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"problem":  "Login: broken",
+		"impact":   []interface{}{"users", "frustration"},
+		"severity": "P1",
+		"empty":    nil,
+		"step":     []interface{}{"First", "Second"},
+		"option": []interface{}{
+			"Patch | 2h | quick | fragile",
+			map[string]interface{}{"description": "Rewrite", "estimate": "2d"},
+		},
+		"nested": map[string]interface{}{"key": "value"},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Errorf("Parse =\n%#v\nexpected\n%#v", v, expected)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		"a: 1\n  b: 2\n",
+		"just a line\n",
+		"a: [1, 2\n",
+		"a: \"open\n",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Expected an error for %q", data)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	values := []string{"Fix login", "", "true", "42", "#tag", "a: b", "ends:", " padded", "line\nbreak", "say \"hi\"", "x # y", "- dash", "<html>"}
+	data, err := Marshal(map[string]interface{}{"values": values})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	v, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v\n%s", err, data)
+	}
+	got := v.(map[string]interface{})["values"]
+	expected := make([]interface{}, len(values))
+	for i, s := range values {
+		expected[i] = s
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Round trip =\n%#v\nexpected\n%#v\nYAML:\n%s", got, expected, data)
+	}
+}
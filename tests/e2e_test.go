@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -40,7 +41,7 @@ func TestEndToEnd(t *testing.T) {
 
 	// Verify all files created
 	yoDir := filepath.Join(tmpDir, ".yo")
-	assertFileExists(t, filepath.Join(yoDir, "current_task.md"))
+	assertFileExists(t, filepath.Join(yoDir, "tasks"))
 	assertFileExists(t, filepath.Join(yoDir, "backlog.md"))
 	assertFileExists(t, filepath.Join(yoDir, "state.json"))
 	assertFileExists(t, filepath.Join(yoDir, "config.json"))
@@ -136,7 +137,7 @@ Missing device testing
 
 ## ✅ Completion
 `
-	err = os.WriteFile(filepath.Join(yoDir, "current_task.md"), []byte(taskContent), 0644)
+	err = os.WriteFile(filepath.Join(yoDir, "tasks", "login_button_e2e.md"), []byte(taskContent), 0644)
 	if err != nil {
 		t.Fatalf("Failed to write task: %v", err)
 	}
@@ -441,7 +442,7 @@ Missing device coverage in test matrix
 
 ## ✅ Completion
 `
-	os.WriteFile(filepath.Join(yoDir, "current_task.md"), []byte(taskContent), 0644)
+	os.WriteFile(filepath.Join(yoDir, "tasks", "mobile_login_fix.md"), []byte(taskContent), 0644)
 
 	// Set state to red
 	stateContent := `{"version":"1.0.0","current_stage":"red","current_task_id":"mobile_login_fix","timer":{},"session":{},"emergency_bypasses":{"today":0,"this_week":0,"last_reset":"2024-12-27"}}`
@@ -540,4 +541,399 @@ func TestDeferLogsTechDebt(t *testing.T) {
 	assertContains(t, techDebtStr, "No OAuth support")
 	assertContains(t, techDebtStr, "auth_feature")
 	assertContains(t, techDebtStr, "Deferred on")
+	assertContains(t, techDebtStr, "**Status:** open")
+
+	// The register lists, promotes and resolves entries
+	run("defer", "No rate limiting", "--estimate", "3h")
+	output = run("debt", "list")
+	assertContains(t, output, "2 outstanding")
+
+	var list struct {
+		Items []struct {
+			ID   string `json:"id"`
+			What string `json:"what"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(run("debt", "list", "-o", "json")), &list); err != nil || len(list.Items) != 2 {
+		t.Fatalf("Unexpected debt list: %+v, %v", list, err)
+	}
+
+	output = run("debt", "promote", list.Items[1].ID, "-p", "P1")
+	assertContains(t, output, "Promoted to P1")
+	backlogContent, _ := os.ReadFile(filepath.Join(yoDir, "backlog.md"))
+	assertContains(t, string(backlogContent), "No rate limiting #debt ~3h")
+
+	output = run("debt", "resolve", list.Items[0].ID[:4], "--note", "Added OAuth")
+	assertContains(t, output, "Tech debt resolved")
+	output = run("debt", "show", list.Items[0].ID)
+	assertContains(t, output, "Resolution: Added OAuth")
+	output = run("debt", "list")
+	assertContains(t, output, "1 outstanding")
+}
+
+// TestParkAndSwitch tests parking a task and switching back to it
+func TestParkAndSwitch(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "yo-switch-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	yoBinary := filepath.Join(tmpDir, "yo")
+	buildCmd := exec.Command("go", "build", "-o", yoBinary, ".")
+	buildCmd.Dir = getProjectRoot(t)
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build yo: %v\n%s", err, output)
+	}
+
+	run := func(args ...string) string {
+		cmd := exec.Command(yoBinary, args...)
+		cmd.Dir = tmpDir
+		output, _ := cmd.CombinedOutput()
+		return string(output)
+	}
+
+	run("init")
+
+	yoDir := filepath.Join(tmpDir, ".yo")
+	taskPath := filepath.Join(yoDir, "tasks", "fix_login.md")
+	os.WriteFile(filepath.Join(yoDir, "state.json"),
+		[]byte(`{"version":"1.0.0","current_stage":"yellow","current_task_id":"fix_login","timer":{}}`), 0644)
+	os.WriteFile(taskPath, []byte("# Current Task\n\nLogin plan in progress\n"), 0644)
+
+	output := run("park")
+	assertContains(t, output, "Task parked")
+	content, _ := os.ReadFile(taskPath)
+	assertContains(t, string(content), "Login plan in progress")
+
+	output = run("tasks")
+	assertContains(t, output, "fix_login")
+	assertContains(t, output, "YELLOW")
+
+	output = run("switch", "fix_login")
+	assertContains(t, output, "Switched to fix_login")
+
+	content, _ = os.ReadFile(taskPath)
+	assertContains(t, string(content), "Login plan in progress")
+
+	output = run("status")
+	assertContains(t, output, "fix_login")
+
+	output = run("switch", "nope")
+	assertContains(t, output, "no parked task")
+}
+
+// TestDoneTicksBacklogItem tests that finishing a task picked from the
+// backlog marks the backlog item as done
+func TestDoneTicksBacklogItem(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "yo-done-backlog-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	yoBinary := filepath.Join(tmpDir, "yo")
+	buildCmd := exec.Command("go", "build", "-o", yoBinary, ".")
+	buildCmd.Dir = getProjectRoot(t)
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build yo: %v\n%s", err, output)
+	}
+
+	run := func(args ...string) string {
+		cmd := exec.Command(yoBinary, args...)
+		cmd.Dir = tmpDir
+		output, _ := cmd.CombinedOutput()
+		return string(output)
+	}
+
+	run("init")
+
+	yoDir := filepath.Join(tmpDir, ".yo")
+	os.WriteFile(filepath.Join(yoDir, "backlog.md"),
+		[]byte("# Backlog\n\n## P0 - Launch Blockers\n- [ ] Fix login <!-- id:abc123 -->\n"), 0644)
+	os.WriteFile(filepath.Join(yoDir, "state.json"),
+		[]byte(`{"version":"1.0.0","current_stage":"green","current_task_id":"fix_login_abc123","current_backlog_id":"abc123","timer":{"started_at":"2024-12-27T10:00:00Z","estimated_hours":1,"threshold_hours":1}}`), 0644)
+
+	output := run("done")
+	assertContains(t, output, "Task Complete")
+	assertContains(t, output, "Fix login")
+
+	content, _ := os.ReadFile(filepath.Join(yoDir, "backlog.md"))
+	assertContains(t, string(content), "- [x] Fix login done:")
+	assertContains(t, string(content), "<!-- id:abc123 -->")
+
+	archives, _ := filepath.Glob(filepath.Join(yoDir, "done", "*_fix_login_abc123.md"))
+	if len(archives) != 1 {
+		t.Errorf("Expected archive named after the task ID, got %v", archives)
+	}
+}
+
+// TestWorkspaceDiscovery tests that commands find the workspace from a
+// subdirectory, honour YO_DIR, and that init registers workspaces
+func TestWorkspaceDiscovery(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "yo-discovery-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	yoBinary := filepath.Join(tmpDir, "yo")
+	buildCmd := exec.Command("go", "build", "-o", yoBinary, ".")
+	buildCmd.Dir = getProjectRoot(t)
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build yo: %v\n%s", err, output)
+	}
+
+	runIn := func(dir string, env []string, args ...string) string {
+		cmd := exec.Command(yoBinary, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), env...)
+		output, _ := cmd.CombinedOutput()
+		return string(output)
+	}
+
+	api := filepath.Join(tmpDir, "api")
+	web := filepath.Join(tmpDir, "web")
+	sub := filepath.Join(api, "internal", "handlers")
+	os.MkdirAll(sub, 0755)
+	os.MkdirAll(web, 0755)
+
+	runIn(api, nil, "init")
+	runIn(web, nil, "init")
+	os.WriteFile(filepath.Join(api, ".yo", "state.json"),
+		[]byte(`{"version":"1.0.0","current_stage":"red","current_task_id":"fix_auth","timer":{}}`), 0644)
+
+	output := runIn(sub, nil, "status")
+	assertContains(t, output, "fix_auth")
+
+	output = runIn(tmpDir, []string{"YO_DIR=" + filepath.Join(api, ".yo")}, "status")
+	assertContains(t, output, "fix_auth")
+
+	output = runIn(tmpDir, nil, "status", "--all")
+	assertContains(t, output, "api")
+	assertContains(t, output, "RED")
+	assertContains(t, output, "web")
+	assertContains(t, output, "NONE")
+}
+
+// TestStructuredOutput tests --output json on read commands and structured
+// error objects on failure
+func TestStructuredOutput(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "yo-output-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	yoBinary := filepath.Join(tmpDir, "yo")
+	buildCmd := exec.Command("go", "build", "-o", yoBinary, ".")
+	buildCmd.Dir = getProjectRoot(t)
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build yo: %v\n%s", err, output)
+	}
+
+	// Only stdout, so the JSON must parse on its own
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.Command(yoBinary, args...)
+		cmd.Dir = tmpDir
+		return cmd.Output()
+	}
+
+	stdout, err := run("status", "-o", "json")
+	if err == nil {
+		t.Fatal("Expected status to fail before init")
+	}
+	var failure struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(stdout, &failure); err != nil {
+		t.Fatalf("Error output is not JSON: %v\n%s", err, stdout)
+	}
+	if failure.Error.Code != "not_initialized" {
+		t.Errorf("Expected code not_initialized, got %q", failure.Error.Code)
+	}
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 3 {
+		t.Errorf("Expected exit code 3 for not_initialized, got %v", err)
+	}
+
+	run("init")
+	run("add", "--priority", "P0", "Fix login #auth ~2h")
+
+	stdout, err = run("status", "--output", "json")
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	var status struct {
+		Stage  string          `json:"stage"`
+		Timer  json.RawMessage `json:"timer"`
+		Parked []interface{}   `json:"parked"`
+	}
+	if err := json.Unmarshal(stdout, &status); err != nil {
+		t.Fatalf("Status output is not JSON: %v\n%s", err, stdout)
+	}
+	if status.Stage != "none" || string(status.Timer) != "null" || status.Parked == nil {
+		t.Errorf("Unexpected status: %s", stdout)
+	}
+
+	// No timer is still a reading
+	stdout, err = run("timer", "-o", "json")
+	if err != nil {
+		t.Fatalf("timer failed without a running timer: %v\n%s", err, stdout)
+	}
+	var reading struct {
+		Stage string          `json:"stage"`
+		Timer json.RawMessage `json:"timer"`
+	}
+	if err := json.Unmarshal(stdout, &reading); err != nil || reading.Stage != "none" || string(reading.Timer) != "null" {
+		t.Errorf("Unexpected timer reading: %s", stdout)
+	}
+
+	stdout, err = run("list", "-o", "json")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var list struct {
+		Items []struct {
+			Priority string   `json:"priority"`
+			Title    string   `json:"title"`
+			Tags     []string `json:"tags"`
+		} `json:"items"`
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal(stdout, &list); err != nil {
+		t.Fatalf("List output is not JSON: %v\n%s", err, stdout)
+	}
+	if list.Total != 1 || len(list.Items) != 1 || list.Items[0].Title != "Fix login" || list.Items[0].Priority != "P0" {
+		t.Errorf("Unexpected list: %s", stdout)
+	}
+
+	stdout, _ = run("list", "-o", "yaml")
+	assertContains(t, string(stdout), "title: Fix login")
+}
+
+func TestNonInteractiveWorkflow(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "yo-noinput-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	yoBinary := filepath.Join(tmpDir, "yo")
+	buildCmd := exec.Command("go", "build", "-o", yoBinary, ".")
+	buildCmd.Dir = getProjectRoot(t)
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build yo: %v\n%s", err, output)
+	}
+
+	// No stdin at all: any prompt that slips through would fail the command
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(yoBinary, append(args, "--no-input")...)
+		cmd.Dir = tmpDir
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+	mustRun := func(args ...string) string {
+		output, err := run(args...)
+		if err != nil {
+			t.Fatalf("yo %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return output
+	}
+
+	mustRun("init")
+
+	output, err := run("red")
+	if err == nil {
+		t.Fatal("Expected red to fail without answers under --no-input")
+	}
+	assertContains(t, output, "--problem")
+
+	jsonCmd := exec.Command(yoBinary, "red", "--no-input", "-o", "json")
+	jsonCmd.Dir = tmpDir
+	stdout, _ := jsonCmd.Output()
+	assertContains(t, string(stdout), `"input_required"`)
+
+	mustRun("red", "--problem", "Login fails for SSO users", "--impact", "users,frustration", "--severity", "P1")
+
+	answers := filepath.Join(tmpDir, "yellow.yaml")
+	os.WriteFile(answers, []byte(`cause-immediate: Cookie domain is wrong
+cause-underlying: Config copied from staging
+cause-system: No config review
+option:
+  - Patch the cookie | 2h | quick | fragile
+  - description: Rewrite SSO
+    estimate: 2d
+    pros: clean
+    cons: slow
+  - Disable SSO | 30m | instant | users lose SSO
+choose: A
+reason: Smallest change
+step: [Fix cookie domain, Add a test]
+criterion:
+  - SSO users can log in
+  - Regression test passes
+`), 0644)
+	mustRun("yellow", "--answers", answers)
+
+	taskFiles, _ := filepath.Glob(filepath.Join(tmpDir, ".yo", "tasks", "*.md"))
+	if len(taskFiles) != 1 {
+		t.Fatalf("Expected one task file, got %v", taskFiles)
+	}
+	task, _ := os.ReadFile(taskFiles[0])
+	assertContains(t, string(task), "Cookie domain is wrong")
+	assertContains(t, string(task), "- Description: Rewrite SSO")
+	assertContains(t, string(task), "- [ ] SSO users can log in")
+
+	output = mustRun("go")
+	assertContains(t, output, "GREEN LIGHT")
+
+	mustRun("check", "1")
+	output = mustRun("status")
+	assertContains(t, output, "Criteria: 1/2 met")
+
+	// Unmet criteria need an explicit answer to complete anyway
+	if output, err = run("done", "--criteria-met", "none"); err == nil {
+		t.Fatal("Expected done to stop at unmet criteria under --no-input")
+	}
+	assertContains(t, output, "--continue-working")
+
+	// Only the unticked criterion is left to answer
+	output = mustRun("done", "--criteria-met", "2")
+	assertContains(t, output, "Task Complete!")
+
+	archived, _ := filepath.Glob(filepath.Join(tmpDir, ".yo", "done", "*.md"))
+	if len(archived) != 1 {
+		t.Fatalf("Expected one archived task, got %v", archived)
+	}
+	archive, _ := os.ReadFile(archived[0])
+	assertContains(t, string(archive), "- Criteria Met: 2/2")
+	assertContains(t, string(archive), "✓ SSO users can log in")
+	activityLog, _ := os.ReadFile(filepath.Join(tmpDir, ".yo", "activity.jsonl"))
+	assertContains(t, string(activityLog), `"type":"criterion_check"`)
+
+	mustRun("add", "--priority", "P1", "Rate limit the API")
+	if _, err := os.Stat(taskFiles[0]); err == nil {
+		t.Error("Expected the finished task's file to be removed from tasks/")
+	}
+
+	mustRun("next", "--pick", "1", "--impact", "launch", "--severity", "P0")
+	taskFiles, _ = filepath.Glob(filepath.Join(tmpDir, ".yo", "tasks", "*.md"))
+	if len(taskFiles) != 1 {
+		t.Fatalf("Expected one task file, got %v", taskFiles)
+	}
+	task, _ = os.ReadFile(taskFiles[0])
+	assertContains(t, string(task), "- [x] Blocks launch")
+	assertContains(t, string(task), "- [x] P0 - Launch blocker")
+
+	// Picking the same backlog item again must not overwrite the parked task
+	mustRun("park")
+	if output, err = run("next", "--pick", "1"); err == nil {
+		t.Error("Expected next to refuse a task ID that is already parked")
+	}
+	assertContains(t, output, "already parked")
+	task, _ = os.ReadFile(taskFiles[0])
+	assertContains(t, string(task), "- [x] Blocks launch")
 }
@@ -41,7 +41,7 @@ func TestFullWorkflow(t *testing.T) {
 
 		// Verify files were created
 		expectedFiles := []string{
-			"current_task.md",
+			"tasks",
 			"backlog.md",
 			"tech_debt_log.md",
 			"state.json",
@@ -93,7 +93,7 @@ func TestFullWorkflow(t *testing.T) {
 
 	// Test: Simulate RED LIGHT by modifying the task file
 	t.Run("simulate RED LIGHT", func(t *testing.T) {
-		taskPath := filepath.Join(tmpDir, ".yo", "current_task.md")
+		taskPath := filepath.Join(tmpDir, ".yo", "tasks", "test_task.md")
 		content := `# Current Task
 
 ## 🔴 RED LIGHT - Problem Definition
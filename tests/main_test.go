@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestMain points HOME at a temp dir so the yo binaries under test register
+// workspaces there instead of in the real ~/.yo. Go's caches are pinned
+// first so the builds inside the tests keep using them.
+func TestMain(m *testing.M) {
+	out, err := exec.Command("go", "env", "GOCACHE", "GOMODCACHE", "GOPATH").Output()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "go env failed: %v\n", err)
+		os.Exit(1)
+	}
+	for i, key := range []string{"GOCACHE", "GOMODCACHE", "GOPATH"} {
+		if values := strings.Split(strings.TrimSpace(string(out)), "\n"); i < len(values) {
+			os.Setenv(key, values[i])
+		}
+	}
+
+	home, err := os.MkdirTemp("", "yo-home-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create temp home: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("HOME", home)
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}